@@ -0,0 +1,122 @@
+package prototools
+
+import (
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+const anyName protoreflect.FullName = "google.protobuf.Any"
+
+// AnyResolver finds the type of the message packed in a google.protobuf.Any when a path steps through one.
+// This defaults to protoregistry.GlobalTypes. Replace it before using the package if your types are
+// registered somewhere else, it is not safe to change while other goroutines use the package.
+var AnyResolver protoregistry.MessageTypeResolver = protoregistry.GlobalTypes
+
+/*
+unpackAny returns the message that part, the next part of a path, should be found in.
+If msg is a google.protobuf.Any, this is the message packed inside of it, unless the part's field is a field of
+the Any itself (type_url or value) and not a field of the packed message. Otherwise it is msg.
+
+anyPath is the path to msg and is used in error messages.
+*/
+func unpackAny(msg proto.Message, part, anyPath string) (proto.Message, error) {
+	ref := msg.ProtoReflect()
+	if ref.Descriptor().FullName() != anyName {
+		return msg, nil
+	}
+	pp, err := parsePart(part)
+	if err != nil {
+		return nil, err
+	}
+	name := pp.name
+	fields := ref.Descriptor().Fields()
+	own := fields.ByName(protoreflect.Name(name)) != nil
+
+	url := ref.Get(fields.ByName("type_url")).String()
+	if url == "" {
+		if own {
+			return msg, nil
+		}
+		return nil, Errorf(ErrIntermdiateNotSet, "Any field(%s) does not have a message, so field(%s) could not be found", anyPath, name)
+	}
+
+	mt, err := AnyResolver.FindMessageByURL(url)
+	if err != nil {
+		return nil, Errorf(ErrAnyType, "Any field(%s) has type(%s), which could not be found: %s", anyPath, url, err)
+	}
+	packed := mt.New().Interface()
+	if err := proto.Unmarshal(ref.Get(fields.ByName("value")).Bytes(), packed); err != nil {
+		return nil, Errorf(ErrAnyType, "Any field(%s) has a value that is not a %s: %s", anyPath, mt.Descriptor().FullName(), err)
+	}
+
+	if own && packed.ProtoReflect().Descriptor().Fields().ByName(protoreflect.Name(name)) == nil {
+		return msg, nil
+	}
+	return packed, nil
+}
+
+// checkableFields returns the fields of a path up to and including the first google.protobuf.Any that the path
+// steps into. The fields after that depend on what is packed in the Any, so checkPath() cannot check them.
+func checkableFields(md protoreflect.MessageDescriptor, fields []string) []string {
+	for x, field := range fields[:len(fields)-1] {
+		pp, err := parsePart(field)
+		if err != nil {
+			return fields
+		}
+		fd := partField(md, pp.name)
+		if fd == nil || valueDesc(fd).Kind() != protoreflect.MessageKind {
+			return fields
+		}
+		md = valueDesc(fd).Message()
+		if md.FullName() != anyName {
+			continue
+		}
+		if next, _ := parsePart(fields[x+1]); md.Fields().ByName(protoreflect.Name(next.name)) == nil {
+			return fields[:x+1]
+		}
+	}
+	return fields
+}
+
+// anyStack records the messages unpacked from Any messages while walking a path to write a field, so
+// they can be packed again after the write.
+type anyStack []packedAny
+
+// packedAny is an Any and the message that was unpacked from it.
+type packedAny struct {
+	any    protoreflect.Message
+	packed proto.Message
+}
+
+// unpack is unpackAny(), but remembers the Any if msg was unpacked.
+func (s *anyStack) unpack(msg proto.Message, part, anyPath string) (proto.Message, error) {
+	m, err := unpackAny(msg, part, anyPath)
+	if err != nil {
+		return nil, err
+	}
+	if m != msg {
+		*s = append(*s, packedAny{any: msg.ProtoReflect(), packed: m})
+	}
+	return m, nil
+}
+
+// repack packs every unpacked message back into its Any, starting with the innermost, whose Any is
+// inside of the message unpacked before it.
+func (s anyStack) repack() error {
+	for i := len(s) - 1; i >= 0; i-- {
+		b, err := proto.Marshal(s[i].packed)
+		if err != nil {
+			return Errorf(ErrAnyType, "could not pack %s into an Any: %s", s[i].packed.ProtoReflect().Descriptor().FullName(), err)
+		}
+		s[i].any.Set(s[i].any.Descriptor().Fields().ByName("value"), protoreflect.ValueOfBytes(b))
+	}
+	return nil
+}
+
+// anyPath is the path to the message holding fields[x], used in unpackAny() error messages.
+func anyPath(fields []string, x int) string {
+	return strings.Join(fields[:x], ".")
+}
@@ -0,0 +1,207 @@
+package prototools
+
+import (
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+
+	pb "github.com/johnsiilver/prototools/sample"
+)
+
+func mustAny(m proto.Message) *anypb.Any {
+	a, err := anypb.New(m)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+func newAnyMsg() *pb.WellKnown {
+	return &pb.WellKnown{
+		Vany: mustAny(&pb.Customer{
+			Name:   "Bob",
+			Orders: []*pb.Order{{Id: "1"}, {Id: "2"}},
+			Labels: map[string]string{"env": "prod"},
+		}),
+		LAny: []*anypb.Any{
+			mustAny(&pb.WellKnown{Vany: mustAny(&pb.Order{Id: "nested"})}),
+		},
+	}
+}
+
+func TestGetFieldAny(t *testing.T) {
+	tests := []struct {
+		desc   string
+		msg    proto.Message
+		fqPath string
+		want   interface{}
+		code   ErrCode
+	}{
+		{desc: "field in the packed message", msg: newAnyMsg(), fqPath: "vany.name", want: "Bob"},
+		{desc: "selector in the packed message", msg: newAnyMsg(), fqPath: "vany.orders[1].id", want: "2"},
+		{desc: "map in the packed message", msg: newAnyMsg(), fqPath: `vany.labels["env"]`, want: "prod"},
+		{desc: "negative index in the packed message", msg: newAnyMsg(), fqPath: "vany.orders[-1].id", want: "2"},
+		{desc: "Any in a list with a nested Any", msg: newAnyMsg(), fqPath: "l_any[0].vany.id", want: "nested"},
+		{
+			desc:   "Any's own field",
+			msg:    newAnyMsg(),
+			fqPath: "vany.type_url",
+			want:   "type.googleapis.com/r3.Customer",
+		},
+		{desc: "error: field not in the packed message", msg: newAnyMsg(), fqPath: "vany.nope", code: ErrBadFieldName},
+		{desc: "error: Any not set", msg: &pb.WellKnown{}, fqPath: "vany.name", code: ErrIntermdiateNotSet},
+		{
+			desc:   "error: type not registered",
+			msg:    &pb.WellKnown{Vany: &anypb.Any{TypeUrl: "type.googleapis.com/r3.Nope"}},
+			fqPath: "vany.name",
+			code:   ErrAnyType,
+		},
+	}
+
+	for _, test := range tests {
+		fv, err := GetField(test.msg, test.fqPath)
+		if !checkCode(t, "TestGetFieldAny", test.desc, err, test.code) {
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if diff := pretty.Compare(test.want, fv.Value); diff != "" {
+			t.Errorf("TestGetFieldAny(%s): -want/+got:\n%s", test.desc, diff)
+		}
+	}
+}
+
+func TestGetAllAny(t *testing.T) {
+	matches, err := GetAll(newAnyMsg(), "vany.orders[*].id")
+	if err != nil {
+		t.Fatalf("TestGetAllAny: got err == %s, want err == nil", err)
+	}
+	var got []interface{}
+	for _, m := range matches {
+		got = append(got, m.FieldValue.Value)
+	}
+	if diff := pretty.Compare([]interface{}{"1", "2"}, got); diff != "" {
+		t.Errorf("TestGetAllAny: -want/+got:\n%s", diff)
+	}
+}
+
+func TestUpdateProtoFieldAny(t *testing.T) {
+	tests := []struct {
+		desc    string
+		fqPath  string
+		value   interface{}
+		options []UpdateOption
+		check   string
+		code    ErrCode
+	}{
+		{desc: "field in the packed message", fqPath: "vany.name", value: "Alice", check: "vany.name"},
+		{desc: "list entry in the packed message", fqPath: "vany.orders[0].id", value: "x", check: "vany.orders[0].id"},
+		{
+			desc:    "create intermediates in the packed message",
+			fqPath:  "vany.payment.credits",
+			value:   int64(5),
+			options: []UpdateOption{CreateIntermediates()},
+			check:   "vany.payment.credits",
+		},
+		{desc: "nested Any", fqPath: "l_any[0].vany.id", value: "changed", check: "l_any[0].vany.id"},
+		{desc: "error: bad value", fqPath: "vany.name", value: 1, code: ErrTypeMismatch},
+	}
+
+	for _, test := range tests {
+		msg := newAnyMsg()
+		err := UpdateProtoField(msg, test.fqPath, test.value, test.options...)
+		if !checkCode(t, "TestUpdateProtoFieldAny", test.desc, err, test.code) {
+			continue
+		}
+		if err != nil {
+			if diff := Equal(newAnyMsg(), msg); diff != "" {
+				t.Errorf("TestUpdateProtoFieldAny(%s): message changed on error:\n%s", test.desc, diff)
+			}
+			continue
+		}
+
+		// Read back from a copy that went over the wire, so we know the value was packed.
+		b, err := proto.Marshal(msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := &pb.WellKnown{}
+		if err := proto.Unmarshal(b, got); err != nil {
+			t.Fatal(err)
+		}
+		fv, err := GetField(got, test.check)
+		if err != nil {
+			t.Errorf("TestUpdateProtoFieldAny(%s): GetField() got err == %s", test.desc, err)
+			continue
+		}
+		if fv.Value != test.value {
+			t.Errorf("TestUpdateProtoFieldAny(%s): got %v, want %v", test.desc, fv.Value, test.value)
+		}
+	}
+}
+
+func TestClearFieldAny(t *testing.T) {
+	msg := newAnyMsg()
+	if err := ClearField(msg, `vany.labels["env"]`); err != nil {
+		t.Fatalf("TestClearFieldAny: got err == %s, want err == nil", err)
+	}
+	got := &pb.Customer{}
+	if err := msg.Vany.UnmarshalTo(got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Labels) != 0 {
+		t.Errorf("TestClearFieldAny: got labels %v, want none", got.Labels)
+	}
+}
+
+func TestNoAnyStepping(t *testing.T) {
+	tests := []struct {
+		desc string
+		f    func(msg *pb.WellKnown) error
+	}{
+		{desc: "AppendField", f: func(msg *pb.WellKnown) error { return AppendField(msg, "vany.tags", "x") }},
+		{desc: "RemoveField", f: func(msg *pb.WellKnown) error { return RemoveField(msg, "vany.orders", 0) }},
+		{
+			desc: "ApplyFieldMask",
+			f: func(msg *pb.WellKnown) error {
+				return ApplyFieldMask(msg, newAnyMsg(), &fieldmaskpb.FieldMask{Paths: []string{"vany.name"}})
+			},
+		},
+		{
+			desc: "ApplyJSONPatch",
+			f: func(msg *pb.WellKnown) error {
+				return ApplyJSONPatch(msg, []byte(`[{"op": "replace", "path": "/vany/name", "value": "x"}]`))
+			},
+		},
+		{
+			desc: "FilterByFieldMask",
+			f: func(msg *pb.WellKnown) error {
+				return FilterByFieldMask(msg, &fieldmaskpb.FieldMask{Paths: []string{"vany.name"}})
+			},
+		},
+	}
+
+	for _, test := range tests {
+		msg := newAnyMsg()
+		err := test.f(msg)
+		if !checkCode(t, "TestNoAnyStepping", test.desc, err, ErrBadFieldName) {
+			continue
+		}
+		if !proto.Equal(msg, newAnyMsg()) {
+			t.Errorf("TestNoAnyStepping(%s): message was changed on error", test.desc)
+		}
+	}
+}
+
+func TestAnyResolver(t *testing.T) {
+	defer func(r protoregistry.MessageTypeResolver) { AnyResolver = r }(AnyResolver)
+	AnyResolver = &protoregistry.Types{}
+
+	_, err := GetField(newAnyMsg(), "vany.name")
+	checkCode(t, "TestAnyResolver", "empty registry", err, ErrAnyType)
+}
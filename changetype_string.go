@@ -0,0 +1,27 @@
+// Code generated by "stringer -type=ChangeType -trimprefix=Field"; DO NOT EDIT.
+
+package prototools
+
+import "strconv"
+
+func _() {
+	// An "invalid array index" compiler error signifies that the constant values have changed.
+	// Re-run the stringer command to generate them again.
+	var x [1]struct{}
+	_ = x[FieldUnknown-0]
+	_ = x[FieldAdded-1]
+	_ = x[FieldRemoved-2]
+	_ = x[FieldModified-3]
+}
+
+const _ChangeType_name = "UnknownAddedRemovedModified"
+
+var _ChangeType_index = [...]uint8{0, 7, 12, 19, 27}
+
+func (i ChangeType) String() string {
+	idx := int(i) - 0
+	if i < 0 || idx >= len(_ChangeType_index)-1 {
+		return "ChangeType(" + strconv.FormatInt(int64(i), 10) + ")"
+	}
+	return _ChangeType_name[_ChangeType_index[idx]:_ChangeType_index[idx+1]]
+}
@@ -0,0 +1,159 @@
+/*
+Command protoconv converts protocol buffer messages between the wire format, protojson, prototext and YAML.
+It does not need generated Go code, the message types come from a FileDescriptorSet made with:
+
+	protoc --include_imports --descriptor_set_out=service.protoset service.proto
+
+Usage:
+
+	protoconv -descriptor_set=<file> -message=<full name> [flags] [input file]
+
+The message is read from the input file, or stdin if there is none or it is "-". It is written to -o, or stdout.
+The input and output formats come from -from and -to, or from the file extensions if they are not set, see
+prototools.FormatFromPath(). stdin and stdout default to the wire format.
+
+With -stream, the input and output are streams of messages. Wire format streams are length-delimited, JSON
+and text streams have one message per line and YAML streams have one document per message. A message in a wire
+format stream can't be larger than -max_size.
+*/
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/johnsiilver/prototools"
+)
+
+func main() {
+	err := run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr)
+	switch {
+	case err == nil:
+	case err == flag.ErrHelp:
+		os.Exit(2)
+	default:
+		fmt.Fprintln(os.Stderr, "protoconv:", err)
+		os.Exit(1)
+	}
+}
+
+// run converts the message in args, which do not include the program name.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("protoconv", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	descSet := fs.String("descriptor_set", "", "FileDescriptorSet (binary or protojson) with the message type")
+	message := fs.String("message", "", "full name of the message type, aka my.pkg.Customer")
+	from := fs.String("from", "", "format of the input: wire, json, text or yaml (default from the file extension)")
+	to := fs.String("to", "", "format of the output: wire, json, text or yaml (default from the -o extension)")
+	out := fs.String("o", "-", "file to write the output to, - is stdout")
+	protoNames := fs.Bool("proto_names", false, "JSON and YAML use the field names from the .proto file instead of the JSON names")
+	emitDefaults := fs.Bool("emit_defaults", false, "JSON and YAML have fields that are not set with their default values")
+	enumNumbers := fs.Bool("enum_numbers", false, "JSON and YAML have enums as numbers instead of names")
+	multiline := fs.Bool("multiline", false, "JSON and text are written over many lines, ignored with -stream")
+	stream := fs.Bool("stream", false, "the input and output are streams of messages")
+	maxSize := fs.Int("max_size", prototools.DefaultMaxSize, "largest message in bytes that is read from a wire format stream")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: protoconv -descriptor_set=<file> -message=<full name> [flags] [input file]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *descSet == "" || *message == "" {
+		fs.Usage()
+		return fmt.Errorf("-descriptor_set and -message must be set")
+	}
+	if fs.NArg() > 1 {
+		fs.Usage()
+		return fmt.Errorf("only one input file can be given")
+	}
+	in := fs.Arg(0)
+	if in == "" {
+		in = "-"
+	}
+
+	fromFormat, err := format(*from, in)
+	if err != nil {
+		return err
+	}
+	toFormat, err := format(*to, *out)
+	if err != nil {
+		return err
+	}
+
+	b, err := ioutil.ReadFile(*descSet)
+	if err != nil {
+		return err
+	}
+	reg, err := prototools.LoadDescriptorSet(b)
+	if err != nil {
+		return err
+	}
+	msg, err := reg.NewMessage(*message)
+	if err != nil {
+		return err
+	}
+	mt := msg.ProtoReflect().Type()
+
+	options := []prototools.ConvertOption{prototools.ConvertResolver(reg.Types), prototools.ConvertMaxSize(*maxSize)}
+	for _, o := range []struct {
+		set    bool
+		option prototools.ConvertOption
+	}{
+		{*protoNames, prototools.ConvertProtoNames()},
+		{*emitDefaults, prototools.ConvertEmitDefaults()},
+		{*enumNumbers, prototools.ConvertEnumNumbers()},
+		{*multiline, prototools.ConvertMultiline()},
+		{*stream, prototools.ConvertStream()},
+	} {
+		if o.set {
+			options = append(options, o.option)
+		}
+	}
+
+	r := stdin
+	if in != "-" {
+		f, err := os.Open(in)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	dst := stdout
+	var f *os.File
+	if *out != "-" {
+		if f, err = os.Create(*out); err != nil {
+			return err
+		}
+		dst = f
+	}
+
+	w := bufio.NewWriter(dst)
+	err = prototools.Convert(w, bufio.NewReader(r), mt, fromFormat, toFormat, options...)
+	if err == nil {
+		err = w.Flush()
+	}
+	if f != nil {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// format returns the Format named by flag, or the format of file if flag is not set.
+func format(flag, file string) (prototools.Format, error) {
+	if flag != "" {
+		return prototools.ParseFormat(flag)
+	}
+	if file == "-" {
+		return prototools.FormatWire, nil
+	}
+	return prototools.FormatFromPath(file), nil
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/johnsiilver/prototools/internal/testutil"
+	pb "github.com/johnsiilver/prototools/sample"
+)
+
+// writeSet writes a FileDescriptorSet for the sample package to dir and returns its path.
+func writeSet(t *testing.T, dir string) string {
+	t.Helper()
+	b, err := proto.Marshal(testutil.FileDescriptorSet(pb.File_store_proto))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := filepath.Join(dir, "sample.protoset")
+	if err := ioutil.WriteFile(p, b, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	set := writeSet(t, dir)
+	in := filepath.Join(dir, "in.yaml")
+	if err := ioutil.WriteFile(in, []byte("name: John\norders:\n  - id: order0\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	out := filepath.Join(dir, "out.bin")
+	flags := []string{"-descriptor_set=" + set, "-message=r3.Customer"}
+
+	// YAML file to a wire format file.
+	if err := run(append(flags, "-o="+out, in), nil, ioutil.Discard, ioutil.Discard); err != nil {
+		t.Fatalf("TestRun(yaml to wire): got err == %s, want err == nil", err)
+	}
+	b, err := ioutil.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := &pb.Customer{}
+	if err := proto.Unmarshal(b, got); err != nil {
+		t.Fatal(err)
+	}
+	want := &pb.Customer{Name: "John", Orders: []*pb.Order{{Id: "order0"}}}
+	if !proto.Equal(want, got) {
+		t.Errorf("TestRun(yaml to wire): got %v, want %v", got, want)
+	}
+
+	// stdin to stdout, with the formats from flags.
+	stdout := &bytes.Buffer{}
+	if err := run(append(flags, "-to=json", "-proto_names", "-emit_defaults", "-"), bytes.NewReader(b), stdout, ioutil.Discard); err != nil {
+		t.Fatalf("TestRun(wire to json): got err == %s, want err == nil", err)
+	}
+	for _, want := range []string{`"name":"John"`, `"by_id":{}`} {
+		if !strings.Contains(strings.Replace(stdout.String(), " ", "", -1), want) {
+			t.Errorf("TestRun(wire to json): got %s, want it to contain %s", stdout, want)
+		}
+	}
+
+	// A wire format stream with a message larger than -max_size.
+	stream := protowire.AppendBytes(nil, b)
+	args := append(flags, "-stream", "-to=json", "-max_size="+strconv.Itoa(len(b)-1), "-")
+	if err := run(args, bytes.NewReader(stream), ioutil.Discard, ioutil.Discard); err == nil {
+		t.Errorf("TestRun(-max_size too small): got err == nil, want err != nil")
+	}
+	args = append(flags, "-stream", "-to=json", "-max_size="+strconv.Itoa(len(b)), "-")
+	if err := run(args, bytes.NewReader(stream), ioutil.Discard, ioutil.Discard); err != nil {
+		t.Errorf("TestRun(-max_size): got err == %s, want err == nil", err)
+	}
+
+	if err := run(append(flags, "-from=xml", in), nil, ioutil.Discard, ioutil.Discard); err == nil {
+		t.Errorf("TestRun(bad format): got err == nil, want err != nil")
+	}
+}
@@ -0,0 +1,478 @@
+/*
+Command prototools reads and changes protocol buffer messages stored in files using the fqPaths of the
+prototools package, aka "orders[0].lines[1].sku". It does not need generated Go code, the message types come
+from a FileDescriptorSet made with:
+
+	protoc --include_imports --descriptor_set_out=service.protoset service.proto
+
+Usage:
+
+	prototools -descriptor_set=<file> -message=<full name> [flags] <command> [args]
+
+Commands:
+
+	get <file> <fqPath>             Prints the value of a field.
+	set <file> <fqPath> <value>     Sets a field and prints the message. value is parsed like SetFieldFromString().
+	fields <file> [fqPath]          Prints the fields of the message, or of the message at fqPath.
+	diff <a> <b>                    Prints the changes from a to b. Exits with 1 if they are different.
+	enums                           Prints the enums used by the message.
+
+A file is read as wire format, protojson, prototext or YAML. The format comes from -format, or from the
+extension if -format is not set, see prototools.FormatFromPath(). A file of "-" is stdin.
+*/
+package main
+
+import (
+	"encoding/base64"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/johnsiilver/prototools"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// errDiffer is returned by the diff command when the messages are different.
+var errDiffer = errors.New("messages are different")
+
+func main() {
+	err := run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr)
+	switch {
+	case err == nil:
+	case errors.Is(err, errDiffer):
+		os.Exit(1)
+	case errors.Is(err, flag.ErrHelp):
+		os.Exit(2)
+	default:
+		fmt.Fprintln(os.Stderr, "prototools:", err)
+		os.Exit(1)
+	}
+}
+
+// app holds what the flags set up for running a command.
+type app struct {
+	reg     *prototools.Registry
+	message string
+	format  prototools.Format
+	out     prototools.Format
+	pretty  bool
+
+	stdin  io.Reader
+	stdout io.Writer
+}
+
+// run runs the command in args, which do not include the program name.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("prototools", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	descSet := fs.String("descriptor_set", "", "FileDescriptorSet (binary or protojson) with the message type")
+	message := fs.String("message", "", "full name of the message type, aka my.pkg.Customer")
+	format := fs.String("format", "", "format of the input files: wire, json, text or yaml (default from the file extension)")
+	out := fs.String("out_format", "", "format set prints the message in: wire, json, text or yaml (default the input format)")
+	pretty := fs.Bool("pretty", false, "get and fields print values like FieldAsStr() with pretty set")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: prototools -descriptor_set=<file> -message=<full name> [flags] <get|set|fields|diff|enums> [args]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	formats := []prototools.Format{prototools.FormatUnknown, prototools.FormatUnknown}
+	for i, f := range []string{*format, *out} {
+		if f == "" {
+			continue
+		}
+		var err error
+		if formats[i], err = prototools.ParseFormat(f); err != nil {
+			return err
+		}
+	}
+	if *descSet == "" || *message == "" {
+		fs.Usage()
+		return fmt.Errorf("-descriptor_set and -message must be set")
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return fmt.Errorf("a command must be given")
+	}
+
+	b, err := ioutil.ReadFile(*descSet)
+	if err != nil {
+		return err
+	}
+	reg, err := prototools.LoadDescriptorSet(b)
+	if err != nil {
+		return err
+	}
+	// Paths can step into Any messages and extensions that only the descriptor set has.
+	prototools.AnyResolver = reg.Types
+	prototools.ExtensionResolver = reg.Types
+
+	a := &app{reg: reg, message: *message, format: formats[0], out: formats[1], pretty: *pretty, stdin: stdin, stdout: stdout}
+	if _, err := reg.NewMessage(a.message); err != nil {
+		return err
+	}
+
+	cmd, cmdArgs := fs.Arg(0), fs.Args()[1:]
+	switch cmd {
+	case "get":
+		if len(cmdArgs) != 2 {
+			return fmt.Errorf("usage: get <file> <fqPath>")
+		}
+		return a.get(cmdArgs[0], cmdArgs[1])
+	case "set":
+		if len(cmdArgs) != 3 {
+			return fmt.Errorf("usage: set <file> <fqPath> <value>")
+		}
+		return a.set(cmdArgs[0], cmdArgs[1], cmdArgs[2])
+	case "fields":
+		if len(cmdArgs) != 1 && len(cmdArgs) != 2 {
+			return fmt.Errorf("usage: fields <file> [fqPath]")
+		}
+		fqPath := ""
+		if len(cmdArgs) == 2 {
+			fqPath = cmdArgs[1]
+		}
+		return a.fields(cmdArgs[0], fqPath)
+	case "diff":
+		if len(cmdArgs) != 2 {
+			return fmt.Errorf("usage: diff <a> <b>")
+		}
+		return a.diff(cmdArgs[0], cmdArgs[1])
+	case "enums":
+		if len(cmdArgs) != 0 {
+			return fmt.Errorf("usage: enums")
+		}
+		return a.enums()
+	}
+	return fmt.Errorf("unknown command(%s)", cmd)
+}
+
+// prettyKinds are the kinds FieldAsStr() can format. With -pretty, the other kinds are printed as they are
+// without it.
+var prettyKinds = map[protoreflect.Kind]bool{
+	protoreflect.BoolKind:    true,
+	protoreflect.StringKind:  true,
+	protoreflect.BytesKind:   true,
+	protoreflect.Int32Kind:   true,
+	protoreflect.Int64Kind:   true,
+	protoreflect.FloatKind:   true,
+	protoreflect.DoubleKind:  true,
+	protoreflect.EnumKind:    true,
+	protoreflect.MessageKind: true,
+}
+
+// get prints the value at fqPath. Messages are printed as protojson, lists one entry per line and maps
+// one "key: value" per line.
+func (a *app) get(file, fqPath string) error {
+	msg, err := a.load(file)
+	if err != nil {
+		return err
+	}
+
+	fv, err := prototools.GetField(msg, fqPath)
+	if err != nil {
+		return err
+	}
+	var lines []string
+	switch {
+	case fv.IsList:
+		for i := 0; fv.List != nil && i < fv.List.Len(); i++ {
+			s, err := formatValue(fv.FieldDesc, fv.List.Get(i))
+			if err != nil {
+				return err
+			}
+			lines = append(lines, s)
+		}
+	case fv.IsMap:
+		lines, err = mapLines(fv)
+	case a.pretty && prettyKinds[fv.Kind]:
+		var s string
+		s, _, err = prototools.FieldAsStr(msg, fqPath, true)
+		lines = []string{s}
+	default:
+		var s string
+		s, err = singleValue(fv)
+		lines = []string{s}
+	}
+	if err != nil {
+		return err
+	}
+	for _, l := range lines {
+		fmt.Fprintln(a.stdout, l)
+	}
+	return nil
+}
+
+// set sets the field at fqPath to value and prints the message.
+func (a *app) set(file, fqPath, value string) error {
+	msg, err := a.load(file)
+	if err != nil {
+		return err
+	}
+	if err := prototools.SetFieldFromString(msg, fqPath, value, prototools.CreateIntermediates()); err != nil {
+		return err
+	}
+
+	format := a.out
+	if format == prototools.FormatUnknown {
+		format = a.fileFormat(file)
+	}
+	b, err := prototools.Marshal(msg, format, prototools.ConvertMultiline(), prototools.ConvertDeterministic(), prototools.ConvertResolver(a.reg.Types))
+	if err != nil {
+		return err
+	}
+	// prototext ends with a newline when it is multiline, protojson does not.
+	if format == prototools.FormatJSON {
+		b = append(b, '\n')
+	}
+	_, err = a.stdout.Write(b)
+	return err
+}
+
+// fields prints a "name<tab>kind<tab>value" line for each field in the message at fqPath.
+func (a *app) fields(file, fqPath string) error {
+	msg, err := a.load(file)
+	if err != nil {
+		return err
+	}
+	fvs, err := prototools.GetFields(msg, fqPath)
+	if err != nil {
+		return err
+	}
+
+	for _, fv := range fvs {
+		name := string(fv.FieldDesc.Name())
+		kind := fv.Kind.String()
+		var s string
+		switch {
+		case fv.IsList:
+			kind = "repeated " + kind
+			n := 0
+			if fv.List != nil {
+				n = fv.List.Len()
+			}
+			s = fmt.Sprintf("[%d entries]", n)
+		case fv.IsMap:
+			kind = "map " + kind
+			s = fmt.Sprintf("[%d entries]", mapLen(fv.Value))
+		case a.pretty && prettyKinds[fv.Kind]:
+			s, _, err = prototools.FieldAsStr(msg, joinPath(fqPath, name), true)
+		default:
+			s, err = singleValue(fv)
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(a.stdout, "%s\t%s\t%s\n", name, kind, s)
+	}
+	return nil
+}
+
+// diff prints a "fqPath: old → new" line for each change from file a to file b.
+func (a *app) diff(fileA, fileB string) error {
+	msgA, err := a.load(fileA)
+	if err != nil {
+		return err
+	}
+	msgB, err := a.load(fileB)
+	if err != nil {
+		return err
+	}
+
+	changes, err := prototools.RenderDiff(msgA, msgB)
+	if err != nil {
+		return err
+	}
+	for _, c := range changes {
+		fmt.Fprintf(a.stdout, "%s: %s → %s\n", c.FQPath, c.From, c.To)
+	}
+	if len(changes) > 0 {
+		return errDiffer
+	}
+	return nil
+}
+
+// enums prints a "enum<tab>number<tab>proto name<tab>titled name" line for every enum value the message uses.
+func (a *app) enums() error {
+	msg, err := a.reg.NewMessage(a.message)
+	if err != nil {
+		return err
+	}
+	_, reverse := prototools.EnumLookup([]proto.Message{msg})
+
+	names := make([]string, 0, len(reverse))
+	for name := range reverse {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		nums := make([]int, 0, len(reverse[name]))
+		for num := range reverse[name] {
+			nums = append(nums, int(num))
+		}
+		sort.Ints(nums)
+		for _, num := range nums {
+			rec := reverse[name][int32(num)]
+			fmt.Fprintf(a.stdout, "%s\t%d\t%s\t%s\n", name, num, rec.ProtoName, rec.TitledName)
+		}
+	}
+	return nil
+}
+
+// load reads the message in file.
+func (a *app) load(file string) (proto.Message, error) {
+	var b []byte
+	var err error
+	if file == "-" {
+		b, err = ioutil.ReadAll(a.stdin)
+	} else {
+		b, err = ioutil.ReadFile(file)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	msg, err := a.reg.NewMessage(a.message)
+	if err != nil {
+		return nil, err
+	}
+	if err := prototools.Unmarshal(b, msg, a.fileFormat(file), prototools.ConvertResolver(a.reg.Types)); err != nil {
+		return nil, fmt.Errorf("file(%s) is not a %s: %w", file, a.message, err)
+	}
+	return msg, nil
+}
+
+// fileFormat returns the format of file, which is -format if it was set.
+func (a *app) fileFormat(file string) prototools.Format {
+	if a.format != prototools.FormatUnknown {
+		return a.format
+	}
+	if file == "-" {
+		return prototools.FormatWire
+	}
+	return prototools.FormatFromPath(file)
+}
+
+// singleValue formats the value of a field that is not a whole list or map.
+func singleValue(fv prototools.FieldValue) (string, error) {
+	switch v := fv.Value.(type) {
+	case proto.Message:
+		return formatValue(fv.FieldDesc, protoreflect.ValueOfMessage(v.ProtoReflect()))
+	case protoreflect.EnumNumber:
+		if fv.EnumDesc != nil {
+			return string(fv.EnumDesc.Name()), nil
+		}
+		return strconv.Itoa(int(v)), nil
+	}
+	return formatValue(fv.FieldDesc, protoreflect.ValueOf(fv.Value))
+}
+
+// formatValue formats a single value of field fd without losing anything, unlike FieldAsStr(). Messages are
+// protojson, bytes are base64 and enums are the name of the value.
+func formatValue(fd protoreflect.FieldDescriptor, v protoreflect.Value) (string, error) {
+	switch x := v.Interface().(type) {
+	case protoreflect.Message:
+		b, err := protojson.MarshalOptions{Multiline: true}.Marshal(x.Interface())
+		return string(b), err
+	case protoreflect.EnumNumber:
+		if fd != nil {
+			if ev := fd.Enum().Values().ByNumber(x); ev != nil {
+				return string(ev.Name()), nil
+			}
+		}
+		return strconv.Itoa(int(x)), nil
+	case []byte:
+		return base64.StdEncoding.EncodeToString(x), nil
+	case float32:
+		return strconv.FormatFloat(float64(x), 'g', -1, 32), nil
+	case float64:
+		return strconv.FormatFloat(x, 'g', -1, 64), nil
+	}
+	return fmt.Sprint(v.Interface()), nil
+}
+
+// mapLines returns a "key: value" line for each entry of the map field fv, sorted by key.
+func mapLines(fv prototools.FieldValue) ([]string, error) {
+	vd := fv.FieldDesc.MapValue()
+	type entry struct {
+		key  interface{}
+		line string
+	}
+	var entries []entry
+	var err error
+	add := func(k, v interface{}) bool {
+		var s string
+		s, err = formatValue(vd, protoreflect.ValueOf(v))
+		if err != nil {
+			return false
+		}
+		entries = append(entries, entry{key: k, line: fmt.Sprintf("%v: %s", k, s)})
+		return true
+	}
+
+	switch m := fv.Value.(type) {
+	case nil:
+	case protoreflect.Map:
+		m.Range(func(k protoreflect.MapKey, v protoreflect.Value) bool {
+			return add(k.Interface(), v.Message())
+		})
+	default:
+		iter := reflect.ValueOf(m).MapRange()
+		for iter.Next() {
+			if !add(iter.Key().Interface(), iter.Value().Interface()) {
+				break
+			}
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return keyLess(entries[i].key, entries[j].key) })
+
+	lines := make([]string, 0, len(entries))
+	for _, e := range entries {
+		lines = append(lines, e.line)
+	}
+	return lines, err
+}
+
+// keyLess returns true if the map key a sorts before b, which is of the same type. Numbers sort as numbers,
+// so 9 is before 10.
+func keyLess(a, b interface{}) bool {
+	x, y := reflect.ValueOf(a), reflect.ValueOf(b)
+	switch x.Kind() {
+	case reflect.Int32, reflect.Int64:
+		return x.Int() < y.Int()
+	case reflect.Uint32, reflect.Uint64:
+		return x.Uint() < y.Uint()
+	case reflect.Bool:
+		return !x.Bool() && y.Bool()
+	}
+	return fmt.Sprint(a) < fmt.Sprint(b)
+}
+
+// mapLen returns the number of entries in the map, which is the .Value of a map FieldValue.
+func mapLen(m interface{}) int {
+	switch x := m.(type) {
+	case nil:
+		return 0
+	case protoreflect.Map:
+		return x.Len()
+	}
+	return reflect.ValueOf(m).Len()
+}
+
+// joinPath adds field to the end of fqPath.
+func joinPath(fqPath, field string) string {
+	if fqPath == "" {
+		return field
+	}
+	return fqPath + "." + field
+}
@@ -0,0 +1,288 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/johnsiilver/prototools/internal/testutil"
+	pb "github.com/johnsiilver/prototools/sample"
+)
+
+// writeSet writes a FileDescriptorSet for the sample package to dir and returns its path.
+func writeSet(t *testing.T, dir string) string {
+	t.Helper()
+	b, err := proto.Marshal(testutil.FileDescriptorSet(pb.File_store_proto, pb.File_sample_proto, pb.File_kinds_proto))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return writeFile(t, dir, "sample.protoset", b)
+}
+
+func writeFile(t *testing.T, dir, name string, b []byte) string {
+	t.Helper()
+	p := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(p, b, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	set := writeSet(t, dir)
+
+	a := &pb.Customer{
+		Name:   "John",
+		Tags:   []string{"new", "vip"},
+		Labels: map[string]string{"env": "prod", "app": "web"},
+		Orders: []*pb.Order{{Id: "order0", Lines: []*pb.Line{{Sku: "sku0", Quantity: 1, Price: 1.25}}}},
+		ById:   map[int64]*pb.Order{9: {Id: "nine"}, 10: {Id: "ten"}},
+	}
+	b := proto.Clone(a).(*pb.Customer)
+	b.Name = "Jane"
+
+	wire, err := proto.Marshal(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	js, err := protojson.Marshal(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text, err := prototext.Marshal(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// c is what set prints for a with orders[0].id changed.
+	c := proto.Clone(a).(*pb.Customer)
+	c.Orders[0].Id = "order9"
+	setWire, err := proto.MarshalOptions{Deterministic: true}.Marshal(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	setJSON, err := protojson.MarshalOptions{Multiline: true}.Marshal(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	setText, err := prototext.MarshalOptions{Multiline: true}.Marshal(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wireFile := writeFile(t, dir, "a.bin", wire)
+	jsonFile := writeFile(t, dir, "b.json", js)
+	textFile := writeFile(t, dir, "a.txtpb", text)
+	flags := []string{"-descriptor_set=" + set, "-message=r3.Customer"}
+
+	tests := []struct {
+		desc  string
+		args  []string
+		stdin []byte
+		want  string
+		err   error
+	}{
+		{
+			desc: "get scalar from wire",
+			args: []string{"get", wireFile, "name"},
+			want: "John\n",
+		},
+		{
+			desc: "get from json",
+			args: []string{"get", jsonFile, "orders[0].lines[0].price"},
+			want: "1.25\n",
+		},
+		{
+			desc: "get from text",
+			args: []string{"get", textFile, "tags"},
+			want: "new\nvip\n",
+		},
+		{
+			desc:  "get from stdin",
+			args:  []string{"get", "-", `labels["env"]`},
+			stdin: wire,
+			want:  "prod\n",
+		},
+		{
+			desc: "get map",
+			args: []string{"get", wireFile, "labels"},
+			want: "app: web\nenv: prod\n",
+		},
+		{
+			desc: "get map with number keys",
+			args: []string{"get", wireFile, "by_id"},
+			want: "9: {\n\"id\": \"nine\"\n}\n10: {\n\"id\": \"ten\"\n}\n",
+		},
+		{
+			desc: "get message",
+			args: []string{"get", wireFile, "orders[0].lines[0]"},
+			want: "{\n  \"sku\": \"sku0\",\n  \"quantity\": 1,\n  \"price\": 1.25\n}\n",
+		},
+		{
+			desc: "get pretty",
+			args: []string{"-pretty", "get", wireFile, "orders[0].lines[0].price"},
+			want: "1.25\n",
+		},
+		{
+			desc: "get bad path",
+			args: []string{"get", wireFile, "nope"},
+			err:  errors.New(""),
+		},
+		{
+			desc: "set",
+			args: []string{"-out_format=text", "set", wireFile, "orders[0].id", "order9"},
+			want: string(setText),
+		},
+		{
+			desc: "set json",
+			args: []string{"-out_format=json", "set", wireFile, "orders[0].id", "order9"},
+			want: string(setJSON) + "\n",
+		},
+		{
+			desc: "set in the input format",
+			args: []string{"set", wireFile, "orders[0].id", "order9"},
+			want: string(setWire),
+		},
+		{
+			desc: "fields",
+			args: []string{"fields", wireFile, "orders[0].lines[0]"},
+			want: "sku\tstring\tsku0\nquantity\tint32\t1\nprice\tdouble\t1.25\n",
+		},
+		{
+			desc: "diff",
+			args: []string{"diff", wireFile, jsonFile},
+			want: "name: John → Jane\n",
+			err:  errDiffer,
+		},
+		{
+			desc: "no diff",
+			args: []string{"diff", wireFile, textFile},
+		},
+		{
+			desc: "unknown message",
+			args: []string{"-message=r3.Nope", "get", wireFile, "name"},
+			err:  errors.New(""),
+		},
+		{
+			desc: "unknown command",
+			args: []string{"what"},
+			err:  errors.New(""),
+		},
+	}
+
+	for _, test := range tests {
+		stdout := &bytes.Buffer{}
+		err := run(append(append([]string{}, flags...), test.args...), bytes.NewReader(test.stdin), stdout, ioutil.Discard)
+		switch {
+		case err == nil && test.err != nil:
+			t.Errorf("TestRun(%s): got err == nil, want err != nil", test.desc)
+			continue
+		case err != nil && test.err == nil:
+			t.Errorf("TestRun(%s): got err == %s, want err == nil", test.desc, err)
+			continue
+		case err != nil && test.err == errDiffer && !errors.Is(err, errDiffer):
+			t.Errorf("TestRun(%s): got err == %s, want errDiffer", test.desc, err)
+			continue
+		}
+
+		// protojson and prototext add random spaces, so they are removed before comparing.
+		got := strings.Replace(stdout.String(), " ", "", -1)
+		want := strings.Replace(test.want, " ", "", -1)
+		if got != want {
+			t.Errorf("TestRun(%s): got %q, want %q", test.desc, stdout.String(), test.want)
+		}
+	}
+}
+
+func TestRunEnums(t *testing.T) {
+	set := writeSet(t, t.TempDir())
+
+	stdout := &bytes.Buffer{}
+	if err := run([]string{"-descriptor_set=" + set, "-message=r3.Layer0", "enums"}, nil, stdout, ioutil.Discard); err != nil {
+		t.Fatalf("TestRunEnums: got err == %s, want err == nil", err)
+	}
+	for _, want := range []string{"EE_WHATEVER", "EV_Not_Ok"} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Errorf("TestRunEnums: got %q, want it to contain %q", stdout.String(), want)
+		}
+	}
+}
+
+func TestRunPretty(t *testing.T) {
+	dir := t.TempDir()
+	set := writeSet(t, dir)
+
+	msg := &pb.Kinds{
+		Vbool:     true,
+		Vsint32:   -3,
+		Vsfixed32: -4,
+		Vsint64:   -6,
+		Vsfixed64: -7,
+		Vuint32:   8,
+		Vfixed32:  9,
+		Vuint64:   10,
+		Vfixed64:  11,
+		Vfloat:    1.5,
+		Venum:     pb.EnumValues_EV_Not_Ok,
+		LUint32:   []uint32{1, 2},
+	}
+	b, err := proto.Marshal(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := writeFile(t, dir, "kinds.bin", b)
+	flags := []string{"-descriptor_set=" + set, "-message=r3.Kinds", "-pretty"}
+
+	tests := []struct {
+		desc string
+		args []string
+		want string
+	}{
+		{desc: "get uint32", args: []string{"get", file, "vuint32"}, want: "8\n"},
+		{desc: "get sint64", args: []string{"get", file, "vsint64"}, want: "-6\n"},
+		{desc: "get fixed64", args: []string{"get", file, "vfixed64"}, want: "11\n"},
+		{desc: "get pretty enum", args: []string{"get", file, "venum"}, want: "Not Ok\n"},
+	}
+
+	for _, test := range tests {
+		stdout := &bytes.Buffer{}
+		if err := run(append(append([]string{}, flags...), test.args...), nil, stdout, ioutil.Discard); err != nil {
+			t.Errorf("TestRunPretty(%s): got err == %s, want err == nil", test.desc, err)
+			continue
+		}
+		if got := stdout.String(); got != test.want {
+			t.Errorf("TestRunPretty(%s): got %q, want %q", test.desc, got, test.want)
+		}
+	}
+
+	stdout := &bytes.Buffer{}
+	if err := run(append(flags, "fields", file), nil, stdout, ioutil.Discard); err != nil {
+		t.Fatalf("TestRunPretty(fields): got err == %s, want err == nil", err)
+	}
+	lines := []string{
+		"vbool\tbool\tTrue\n",
+		"vsint32\tsint32\t-3\n",
+		"vsfixed32\tsfixed32\t-4\n",
+		"vsint64\tsint64\t-6\n",
+		"vsfixed64\tsfixed64\t-7\n",
+		"vuint32\tuint32\t8\n",
+		"vfixed32\tfixed32\t9\n",
+		"vuint64\tuint64\t10\n",
+		"vfixed64\tfixed64\t11\n",
+		"vfloat\tfloat\t1.50\n",
+		"venum\tenum\tNot Ok\n",
+		"l_uint32\trepeated uint32\t[2 entries]\n",
+	}
+	for _, line := range lines {
+		if !strings.Contains(stdout.String(), line) {
+			t.Errorf("TestRunPretty(fields): output does not have line %q:\n%s", line, stdout)
+		}
+	}
+}
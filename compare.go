@@ -0,0 +1,293 @@
+package prototools
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+/*
+The options in this file are for Equal() and HumanDiff(). Fields are selected with the same fqPath
+convention as GetField(), such as "layer1.supported.vint32". A part of a path without a selector matches
+every entry of a repeated field or map, so "orders.id" matches "orders[0].id" and "orders[1].id". A part
+with a selector matches only that entry, such as "orders[1].id" or `labels["env"]`.
+
+Paths are not checked against the messages being compared, a path that does not exist matches nothing.
+*/
+
+var protocmpMessage = reflect.TypeOf(protocmp.Message(nil))
+
+// IgnorePaths ignores the fields at fqPaths when comparing.
+func IgnorePaths(fqPaths ...string) cmp.Option {
+	return cmp.FilterPath(pathFilter(fqPaths), cmp.Ignore())
+}
+
+// RepeatedAsSet compares the repeated fields at fqPaths without regard to the order of their entries.
+// Entries are put in order by their exact value before they are compared, so entries that are only equal
+// because of another option, such as FloatTolerance(), might not line up.
+func RepeatedAsSet(fqPaths ...string) cmp.Option {
+	match := pathFilter(fqPaths)
+	return cmp.FilterPath(
+		func(p cmp.Path) bool {
+			return isRepeated(p) && match(p)
+		},
+		cmp.Transformer("prototools.RepeatedAsSet", sortEntries),
+	)
+}
+
+// RepeatedByKey compares the entries of the repeated message field at fqPath by the value of keyField
+// instead of by index, the same as DiffListByKey() does for Diff(). keyField must be a scalar field of the
+// entry's message. An entry is shown with its key as the selector, aka orders["1"].
+func RepeatedByKey(fqPath, keyField string) cmp.Option {
+	match := pathFilter([]string{fqPath})
+	return cmp.FilterPath(
+		func(p cmp.Path) bool {
+			return isRepeated(p) && p.Last().Type() == reflect.SliceOf(protocmpMessage) && match(p)
+		},
+		cmp.Transformer("prototools.RepeatedByKey", func(entries []protocmp.Message) map[string]protocmp.Message {
+			return entriesByKey(entries, protoreflect.Name(keyField))
+		}),
+	)
+}
+
+// FloatTolerance has float and double fields at fqPaths be equal if they are within margin of each other.
+// If no fqPaths are provided, this applies to all float and double fields.
+func FloatTolerance(margin float64, fqPaths ...string) cmp.Option {
+	within := func(x, y float64) bool {
+		return x == y || math.Abs(x-y) <= margin || math.IsNaN(x) && math.IsNaN(y)
+	}
+	return cmp.FilterPath(
+		pathFilter(fqPaths),
+		cmp.Options{
+			cmp.Comparer(within),
+			cmp.Comparer(func(x, y float32) bool { return within(float64(x), float64(y)) }),
+		},
+	)
+}
+
+// TimeTolerance has int64 fields whose names end in "_time", which hold Unix seconds like FieldAsStr()
+// expects, be equal if they are within d of each other. If no fqPaths are provided, this applies to all
+// of these fields, otherwise only the ones at fqPaths.
+func TimeTolerance(d time.Duration, fqPaths ...string) cmp.Option {
+	match := pathFilter(fqPaths)
+	secs := int64(d / time.Second)
+	return cmp.FilterPath(
+		func(p cmp.Path) bool {
+			fields := cmpFields(p)
+			if len(fields) == 0 {
+				return false
+			}
+			pp, _ := parsePart(fields[len(fields)-1])
+			return strings.HasSuffix(pp.name, "_time") && match(p)
+		},
+		cmp.Comparer(func(x, y int64) bool {
+			diff := x - y
+			if diff < 0 {
+				diff = -diff
+			}
+			return diff <= secs
+		}),
+	)
+}
+
+// IgnoreUnsetDefaults treats a field that is set to its default value, or a message field that only holds
+// fields like that, the same as a field that is not set. This only matters for fields with presence, such as
+// message fields and fields marked optional.
+func IgnoreUnsetDefaults() cmp.Option {
+	return cmp.FilterPath(
+		func(p cmp.Path) bool {
+			mi, ok := p.Last().(cmp.MapIndex)
+			if !ok || p.Index(-2).Type() != protocmpMessage {
+				return false
+			}
+			vx, vy := p.Index(-2).Values()
+			k := mi.Key().String()
+			return isUnsetDefault(vx.Interface().(protocmp.Message), k) && isUnsetDefault(vy.Interface().(protocmp.Message), k)
+		},
+		cmp.Ignore(),
+	)
+}
+
+// isUnsetDefault returns true if the field k in m is not set, is set to its default value or is a message
+// whose fields are all like that.
+func isUnsetDefault(m protocmp.Message, k string) bool {
+	v, ok := m[k]
+	if !ok {
+		return true
+	}
+	fd := m.Descriptor().Fields().ByName(protoreflect.Name(k))
+	if fd == nil || fd.IsList() || fd.IsMap() {
+		return false
+	}
+
+	if sub, ok := v.(protocmp.Message); ok {
+		for sk := range sub {
+			if !strings.HasPrefix(sk, "@") && !isUnsetDefault(sub, sk) {
+				return false
+			}
+		}
+		return true
+	}
+	return equalScalar(m.ProtoReflect().Get(fd), fd.Default())
+}
+
+// pathFilter returns a cmp.FilterPath() function that matches the fields at fqPaths. No fqPaths matches
+// everything.
+func pathFilter(fqPaths []string) func(p cmp.Path) bool {
+	patterns := make([][]string, 0, len(fqPaths))
+	for _, fqPath := range fqPaths {
+		patterns = append(patterns, FQPathSplit(fqPath))
+	}
+
+	return func(p cmp.Path) bool {
+		if len(patterns) == 0 {
+			return true
+		}
+		fields := cmpFields(p)
+		for _, pattern := range patterns {
+			if matchFields(pattern, fields) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// matchFields returns true if the fields of a path match pattern. A part of pattern without a selector
+// matches a field with any selector.
+func matchFields(pattern, fields []string) bool {
+	if len(pattern) != len(fields) {
+		return false
+	}
+	for x, want := range pattern {
+		wp, _ := parsePart(want)
+		gp, _ := parsePart(fields[x])
+		if wp.name != gp.name {
+			return false
+		}
+		if wp.hasSelector && want != fields[x] {
+			return false
+		}
+	}
+	return true
+}
+
+// cmpFields converts a cmp.Path over messages that have gone through protocmp.Transform() into the fields
+// of an fqPath.
+func cmpFields(p cmp.Path) []string {
+	var fields []string
+	for x, step := range p {
+		switch s := step.(type) {
+		case cmp.MapIndex:
+			if x > 0 && p.Index(x-1).Type() == protocmpMessage {
+				fields = append(fields, s.Key().String())
+				continue
+			}
+			if len(fields) > 0 {
+				fields[len(fields)-1] += "[" + reflectKey(s.Key()) + "]"
+			}
+		case cmp.SliceIndex:
+			i := s.Key()
+			if i < 0 {
+				if i, _ = s.SplitKeys(); i < 0 {
+					_, i = s.SplitKeys()
+				}
+			}
+			if len(fields) > 0 {
+				fields[len(fields)-1] += "[" + strconv.Itoa(i) + "]"
+			}
+		}
+	}
+	return fields
+}
+
+// reflectKey formats a map key the way keySelector() does.
+func reflectKey(k reflect.Value) string {
+	if k.Kind() == reflect.String {
+		return strconv.Quote(k.String())
+	}
+	return fmt.Sprintf("%v", k.Interface())
+}
+
+// isRepeated returns true if p ends at the value of a repeated field in a message that went through
+// protocmp.Transform(). This is not true for the output of our own transformers, which would otherwise be
+// transformed again.
+func isRepeated(p cmp.Path) bool {
+	if t := p.Index(-1).Type(); t == nil || t.Kind() != reflect.Slice || t == reflect.TypeOf([]byte(nil)) {
+		return false
+	}
+	if t := p.Index(-2).Type(); t == nil || t.Kind() != reflect.Interface {
+		return false
+	}
+	return p.Index(-3).Type() == protocmpMessage
+}
+
+// sortEntries returns a copy of the repeated field v with the entries in a stable order.
+func sortEntries(v interface{}) interface{} {
+	rv := reflect.ValueOf(v)
+	sorted := reflect.MakeSlice(rv.Type(), rv.Len(), rv.Len())
+	reflect.Copy(sorted, rv)
+
+	keys := make([]string, rv.Len())
+	for i := range keys {
+		keys[i] = entryKey(rv.Index(i).Interface())
+	}
+	sort.Sort(byEntryKey{keys: keys, entries: reflect.Swapper(sorted.Interface())})
+	return sorted.Interface()
+}
+
+// entryKey returns a string for an entry of a repeated field that is the same for equal entries.
+func entryKey(v interface{}) string {
+	switch t := v.(type) {
+	case protocmp.Message:
+		b, err := proto.MarshalOptions{Deterministic: true}.Marshal(t)
+		if err != nil {
+			return t.String()
+		}
+		return string(b)
+	case protocmp.Enum:
+		return strconv.Itoa(int(t.Number()))
+	case []byte:
+		return string(t)
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+// byEntryKey sorts the entries of a repeated field by their entryKey().
+type byEntryKey struct {
+	keys    []string
+	entries func(i, j int)
+}
+
+func (b byEntryKey) Len() int           { return len(b.keys) }
+func (b byEntryKey) Less(i, j int) bool { return b.keys[i] < b.keys[j] }
+func (b byEntryKey) Swap(i, j int) {
+	b.keys[i], b.keys[j] = b.keys[j], b.keys[i]
+	b.entries(i, j)
+}
+
+// entriesByKey maps the entries of a repeated message field by the value of their field named key.
+// If two entries have the same key, the later one has its position added to the key, aka "1#3".
+func entriesByKey(entries []protocmp.Message, key protoreflect.Name) map[string]protocmp.Message {
+	m := make(map[string]protocmp.Message, len(entries))
+	for i, entry := range entries {
+		k := ""
+		if fd := entry.Descriptor().Fields().ByName(key); fd != nil {
+			k = fmt.Sprintf("%v", listKey(entry.ProtoReflect().Get(fd)))
+		}
+		if _, ok := m[k]; ok {
+			k = fmt.Sprintf("%s#%d", k, i)
+		}
+		m[k] = entry
+	}
+	return m
+}
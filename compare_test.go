@@ -0,0 +1,209 @@
+package prototools
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/johnsiilver/prototools/sample"
+)
+
+func TestEqualOptions(t *testing.T) {
+	tests := []struct {
+		desc      string
+		want, got proto.Message
+		options   []cmp.Option
+		equal     bool
+	}{
+		{
+			desc:  "no options",
+			want:  &pb.Layer0{Vint32: 1},
+			got:   &pb.Layer0{Vint32: 2},
+			equal: false,
+		},
+		{
+			desc:    "ignore a nested field",
+			want:    &pb.Layer0{Vint32: 1, Layer1: &pb.Layer1{Vstring: "a"}},
+			got:     &pb.Layer0{Vint32: 1, Layer1: &pb.Layer1{Vstring: "b"}},
+			options: []cmp.Option{IgnorePaths("layer1.vstring")},
+			equal:   true,
+		},
+		{
+			desc:    "ignore only the field at the path",
+			want:    &pb.Layer0{Vint32: 1, Layer1: &pb.Layer1{Vstring: "a"}},
+			got:     &pb.Layer0{Vint32: 2, Layer1: &pb.Layer1{Vstring: "b"}},
+			options: []cmp.Option{IgnorePaths("layer1.vstring")},
+			equal:   false,
+		},
+		{
+			desc:    "ignore a field in every entry",
+			want:    &pb.Customer{Orders: []*pb.Order{{Id: "1"}, {Id: "2"}}},
+			got:     &pb.Customer{Orders: []*pb.Order{{Id: "3"}, {Id: "4"}}},
+			options: []cmp.Option{IgnorePaths("orders.id")},
+			equal:   true,
+		},
+		{
+			desc:    "ignore a field in one entry",
+			want:    &pb.Customer{Orders: []*pb.Order{{Id: "1"}, {Id: "2"}}},
+			got:     &pb.Customer{Orders: []*pb.Order{{Id: "3"}, {Id: "4"}}},
+			options: []cmp.Option{IgnorePaths("orders[0].id")},
+			equal:   false,
+		},
+		{
+			desc:    "ignore a map entry",
+			want:    &pb.Customer{Labels: map[string]string{"env": "dev", "a": "b"}},
+			got:     &pb.Customer{Labels: map[string]string{"env": "prod", "a": "b"}},
+			options: []cmp.Option{IgnorePaths(`labels["env"]`)},
+			equal:   true,
+		},
+		{
+			desc:    "repeated scalars as a set",
+			want:    &pb.Customer{Tags: []string{"a", "b", "c"}},
+			got:     &pb.Customer{Tags: []string{"c", "a", "b"}},
+			options: []cmp.Option{RepeatedAsSet("tags")},
+			equal:   true,
+		},
+		{
+			desc:    "repeated messages as a set",
+			want:    &pb.Customer{Orders: []*pb.Order{{Id: "1"}, {Id: "2", Lines: []*pb.Line{{Sku: "a"}}}}},
+			got:     &pb.Customer{Orders: []*pb.Order{{Id: "2", Lines: []*pb.Line{{Sku: "a"}}}, {Id: "1"}}},
+			options: []cmp.Option{RepeatedAsSet("orders")},
+			equal:   true,
+		},
+		{
+			desc:    "set with a different entry",
+			want:    &pb.Customer{Tags: []string{"a", "b"}},
+			got:     &pb.Customer{Tags: []string{"b", "c"}},
+			options: []cmp.Option{RepeatedAsSet("tags")},
+			equal:   false,
+		},
+		{
+			desc:    "set only applies to the path",
+			want:    &pb.Kinds{LString: []string{"a", "b"}, LInt32: []int32{1, 2}},
+			got:     &pb.Kinds{LString: []string{"b", "a"}, LInt32: []int32{2, 1}},
+			options: []cmp.Option{RepeatedAsSet("l_string")},
+			equal:   false,
+		},
+		{
+			desc: "repeated by key",
+			want: &pb.Customer{Orders: []*pb.Order{
+				{Id: "1", Lines: []*pb.Line{{Sku: "a", Quantity: 1}, {Sku: "b", Quantity: 2}}},
+				{Id: "2"},
+			}},
+			got: &pb.Customer{Orders: []*pb.Order{
+				{Id: "2"},
+				{Id: "1", Lines: []*pb.Line{{Sku: "b", Quantity: 2}, {Sku: "a", Quantity: 1}}},
+			}},
+			options: []cmp.Option{RepeatedByKey("orders", "id"), RepeatedByKey("orders.lines", "sku")},
+			equal:   true,
+		},
+		{
+			desc:    "repeated by key with a changed entry",
+			want:    &pb.Customer{Orders: []*pb.Order{{Id: "1", Lines: []*pb.Line{{Sku: "a"}}}, {Id: "2"}}},
+			got:     &pb.Customer{Orders: []*pb.Order{{Id: "2"}, {Id: "1"}}},
+			options: []cmp.Option{RepeatedByKey("orders", "id")},
+			equal:   false,
+		},
+		{
+			desc:    "repeated by key with an ignored field",
+			want:    &pb.Customer{Orders: []*pb.Order{{Id: "1", Lines: []*pb.Line{{Sku: "a"}}}, {Id: "2"}}},
+			got:     &pb.Customer{Orders: []*pb.Order{{Id: "2"}, {Id: "1"}}},
+			options: []cmp.Option{RepeatedByKey("orders", "id"), IgnorePaths("orders.lines")},
+			equal:   true,
+		},
+		{
+			desc:    "float tolerance",
+			want:    &pb.Supported{Vfloat: 1.0, Vdouble: 2.0},
+			got:     &pb.Supported{Vfloat: 1.05, Vdouble: 1.95},
+			options: []cmp.Option{FloatTolerance(0.1)},
+			equal:   true,
+		},
+		{
+			desc:    "float outside tolerance",
+			want:    &pb.Supported{Vdouble: 2.0},
+			got:     &pb.Supported{Vdouble: 2.5},
+			options: []cmp.Option{FloatTolerance(0.1)},
+			equal:   false,
+		},
+		{
+			desc:    "float tolerance for a path",
+			want:    &pb.Supported{Vfloat: 1.0, Vdouble: 2.0},
+			got:     &pb.Supported{Vfloat: 1.05, Vdouble: 1.95},
+			options: []cmp.Option{FloatTolerance(0.1, "vfloat")},
+			equal:   false,
+		},
+		{
+			desc:    "float tolerance for repeated entries",
+			want:    &pb.Kinds{LDouble: []float64{1, 2}},
+			got:     &pb.Kinds{LDouble: []float64{1.01, 1.99}},
+			options: []cmp.Option{FloatTolerance(0.1, "l_double")},
+			equal:   true,
+		},
+		{
+			desc:    "time tolerance",
+			want:    &pb.Layer0{Layer1: &pb.Layer1{Supported: &pb.Supported{VTime: 100}}},
+			got:     &pb.Layer0{Layer1: &pb.Layer1{Supported: &pb.Supported{VTime: 160}}},
+			options: []cmp.Option{TimeTolerance(time.Minute)},
+			equal:   true,
+		},
+		{
+			desc:    "time outside tolerance",
+			want:    &pb.Supported{VTime: 100},
+			got:     &pb.Supported{VTime: 161},
+			options: []cmp.Option{TimeTolerance(time.Minute)},
+			equal:   false,
+		},
+		{
+			desc:    "time tolerance does not apply to other int64 fields",
+			want:    &pb.Supported{Vint64: 100},
+			got:     &pb.Supported{Vint64: 101},
+			options: []cmp.Option{TimeTolerance(time.Minute)},
+			equal:   false,
+		},
+		{
+			desc:  "unset and empty message",
+			want:  &pb.Layer0{},
+			got:   &pb.Layer0{Layer1: &pb.Layer1{}},
+			equal: false,
+		},
+		{
+			desc:    "ignore unset defaults",
+			want:    &pb.Layer0{},
+			got:     &pb.Layer0{Layer1: &pb.Layer1{Supported: &pb.Supported{}}},
+			options: []cmp.Option{IgnoreUnsetDefaults()},
+			equal:   true,
+		},
+		{
+			desc:    "ignore unset defaults with a value set",
+			want:    &pb.Layer0{},
+			got:     &pb.Layer0{Layer1: &pb.Layer1{Supported: &pb.Supported{Vint32: 1}}},
+			options: []cmp.Option{IgnoreUnsetDefaults()},
+			equal:   false,
+		},
+	}
+
+	for _, test := range tests {
+		diff := Equal(test.want, test.got, test.options...)
+		if got := diff == ""; got != test.equal {
+			t.Errorf("TestEqualOptions(%s): got equal == %v, want equal == %v; diff:\n%s", test.desc, got, test.equal, diff)
+		}
+		if hd := HumanDiff(test.want, test.got, test.options...); (hd == "") != test.equal {
+			t.Errorf("TestEqualOptions(%s): HumanDiff() did not agree with Equal()", test.desc)
+		}
+	}
+}
+
+func TestRepeatedByKeyDiff(t *testing.T) {
+	want := &pb.Customer{Orders: []*pb.Order{{Id: "1"}, {Id: "2"}}}
+	got := &pb.Customer{Orders: []*pb.Order{{Id: "2"}, {Id: "3"}}}
+
+	diff := HumanDiff(want, got, RepeatedByKey("orders", "id"))
+	for _, s := range []string{`"1"`, `"3"`} {
+		if !strings.Contains(diff, s) {
+			t.Errorf("TestRepeatedByKeyDiff: diff did not contain key %s:\n%s", s, diff)
+		}
+	}
+}
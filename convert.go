@@ -0,0 +1,483 @@
+package prototools
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"gopkg.in/yaml.v3"
+)
+
+//go:generate stringer -type=Format -trimprefix=Format
+
+// Format is a way a message is encoded.
+type Format int8
+
+const (
+	// FormatUnknown means the format wasn't set.
+	FormatUnknown Format = 0
+	// FormatWire is the binary wire format from proto.Marshal().
+	FormatWire Format = 1
+	// FormatJSON is protojson.
+	FormatJSON Format = 2
+	// FormatText is prototext.
+	FormatText Format = 3
+	// FormatYAML is protojson written as YAML.
+	FormatYAML Format = 4
+)
+
+// ParseFormat returns the Format called s, which is "wire", "json", "text" or "yaml" in any case.
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(s) {
+	case "wire":
+		return FormatWire, nil
+	case "json":
+		return FormatJSON, nil
+	case "text":
+		return FormatText, nil
+	case "yaml":
+		return FormatYAML, nil
+	}
+	return FormatUnknown, Errorf(ErrParse, "format(%s) must be wire, json, text or yaml", s)
+}
+
+// FormatFromPath returns the format of a file from its extension: ".json" is FormatJSON, ".yaml" and ".yml" are
+// FormatYAML, ".txt", ".textproto", ".txtpb" and ".pbtxt" are FormatText and anything else is FormatWire.
+func FormatFromPath(p string) Format {
+	switch strings.ToLower(filepath.Ext(p)) {
+	case ".json":
+		return FormatJSON
+	case ".yaml", ".yml":
+		return FormatYAML
+	case ".txt", ".textproto", ".txtpb", ".pbtxt":
+		return FormatText
+	}
+	return FormatWire
+}
+
+type convertOpts struct {
+	protoNames    bool
+	emitDefaults  bool
+	enumNumbers   bool
+	stream        bool
+	multiline     bool
+	resolver      Resolver
+	deterministic bool
+	maxSize       int
+}
+
+// Resolver finds the types for Any messages and extensions. protoregistry.GlobalTypes and the Types of a
+// Registry are Resolvers. It is used by ConvertResolver() and ReaderResolver().
+type Resolver interface {
+	protoregistry.MessageTypeResolver
+	protoregistry.ExtensionTypeResolver
+}
+
+// ConvertOption is an option for Convert(), Marshal() and Unmarshal().
+type ConvertOption func(o *convertOpts)
+
+// ConvertProtoNames writes JSON and YAML with the field names from the .proto file instead of their JSON names.
+func ConvertProtoNames() ConvertOption {
+	return func(o *convertOpts) {
+		o.protoNames = true
+	}
+}
+
+// ConvertEmitDefaults writes fields that are not set in JSON and YAML with their default values.
+func ConvertEmitDefaults() ConvertOption {
+	return func(o *convertOpts) {
+		o.emitDefaults = true
+	}
+}
+
+// ConvertEnumNumbers writes enums in JSON and YAML as numbers instead of names.
+func ConvertEnumNumbers() ConvertOption {
+	return func(o *convertOpts) {
+		o.enumNumbers = true
+	}
+}
+
+// ConvertMultiline writes JSON and text over many lines with indents. This is ignored for streams, which have
+// one JSON or text message per line.
+func ConvertMultiline() ConvertOption {
+	return func(o *convertOpts) {
+		o.multiline = true
+	}
+}
+
+// ConvertDeterministic writes the wire format with map entries in a stable order.
+func ConvertDeterministic() ConvertOption {
+	return func(o *convertOpts) {
+		o.deterministic = true
+	}
+}
+
+// ConvertResolver finds the types for Any messages and extensions, such as the Types of a Registry.
+// This defaults to protoregistry.GlobalTypes.
+func ConvertResolver(r Resolver) ConvertOption {
+	return func(o *convertOpts) {
+		o.resolver = r
+	}
+}
+
+// ConvertMaxSize changes the size of the largest message Convert() reads from a wire format stream from
+// DefaultMaxSize. This works like ReaderMaxSize().
+func ConvertMaxSize(size int) ConvertOption {
+	return func(o *convertOpts) {
+		o.maxSize = size
+	}
+}
+
+/*
+ConvertStream has Convert() read and write streams of messages instead of a single message. How messages
+are split depends on the format:
+
+	╔════════╤════════════════════════════════════════════════════════════════════╗
+	║ Format │ Stream                                                             ║
+	╠════════╪════════════════════════════════════════════════════════════════════╣
+	║ Wire   │ Each message has its size before it as a varint (length-delimited) ║
+	║ JSON   │ JSON values one after the other, written one per line              ║
+	║ Text   │ One message per line, an empty line is an empty message            ║
+	║ YAML   │ One document per message, separated by "---"                       ║
+	╚════════╧════════════════════════════════════════════════════════════════════╝
+
+A wire message larger than DefaultMaxSize, or the size passed to ConvertMaxSize(), is an error with the code ErrValueOutOfRange. The size is checked
+before anything is read, so a corrupt size can't make Convert() allocate a huge buffer.
+*/
+func ConvertStream() ConvertOption {
+	return func(o *convertOpts) {
+		o.stream = true
+	}
+}
+
+func newConvertOpts(options []ConvertOption) convertOpts {
+	opts := convertOpts{resolver: protoregistry.GlobalTypes, maxSize: DefaultMaxSize}
+	for _, o := range options {
+		o(&opts)
+	}
+	return opts
+}
+
+func (o convertOpts) json(multiline bool) protojson.MarshalOptions {
+	return protojson.MarshalOptions{
+		Multiline:       multiline,
+		UseProtoNames:   o.protoNames,
+		EmitUnpopulated: o.emitDefaults,
+		UseEnumNumbers:  o.enumNumbers,
+		Resolver:        o.resolver,
+	}
+}
+
+/*
+Convert reads messages of type mt encoded in from and writes them to w encoded in to.
+mt can come from a generated message, aka (&pb.Customer{}).ProtoReflect().Type(), or a Registry when there is
+no generated code:
+
+	mt, err := reg.Types.FindMessageByName("my.pkg.Customer")
+	if err != nil {
+		// Do something
+	}
+	err = prototools.Convert(os.Stdout, os.Stdin, mt, prototools.FormatWire, prototools.FormatYAML, prototools.ConvertResolver(reg.Types))
+
+Only one message is read unless ConvertStream() is passed. The options that change how JSON is written also
+change YAML, which is JSON written as YAML.
+*/
+func Convert(w io.Writer, r io.Reader, mt protoreflect.MessageType, from, to Format, options ...ConvertOption) error {
+	opts := newConvertOpts(options)
+	if opts.stream {
+		opts.multiline = false
+	}
+	if opts.maxSize <= 0 {
+		return Errorf(ErrValueOutOfRange, "ConvertMaxSize(%d) must be more than 0", opts.maxSize)
+	}
+
+	dec, err := newStreamDecoder(r, mt, from, opts)
+	if err != nil {
+		return err
+	}
+	enc := newStreamEncoder(w, to, opts.stream)
+
+	for {
+		b, err := dec.next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		msg := mt.New().Interface()
+		if err := unmarshal(b, msg, from, opts); err != nil {
+			return err
+		}
+		out, err := marshal(msg, to, opts)
+		if err != nil {
+			return err
+		}
+		if err := enc.write(out); err != nil {
+			return err
+		}
+		if !opts.stream {
+			return nil
+		}
+	}
+}
+
+// Marshal encodes msg in format f. ConvertStream() is ignored.
+func Marshal(msg proto.Message, f Format, options ...ConvertOption) ([]byte, error) {
+	return marshal(msg, f, newConvertOpts(options))
+}
+
+// Unmarshal decodes b, which is encoded in format f, into msg. ConvertStream() is ignored.
+func Unmarshal(b []byte, msg proto.Message, f Format, options ...ConvertOption) error {
+	return unmarshal(b, msg, f, newConvertOpts(options))
+}
+
+func marshal(msg proto.Message, f Format, opts convertOpts) ([]byte, error) {
+	var b []byte
+	var err error
+	switch f {
+	case FormatWire:
+		b, err = proto.MarshalOptions{Deterministic: opts.deterministic}.Marshal(msg)
+	case FormatJSON:
+		b, err = opts.json(opts.multiline).Marshal(msg)
+	case FormatText:
+		b, err = prototext.MarshalOptions{Multiline: opts.multiline, Resolver: opts.resolver}.Marshal(msg)
+	case FormatYAML:
+		b, err = opts.json(false).Marshal(msg)
+		if err == nil {
+			b, err = jsonToYAML(b)
+		}
+	default:
+		return nil, Errorf(ErrParse, "format(%s) is not supported", f)
+	}
+	if err != nil {
+		return nil, Errorf(ErrParse, "could not write %s as %s: %s", msg.ProtoReflect().Descriptor().FullName(), f, err)
+	}
+	return b, nil
+}
+
+func unmarshal(b []byte, msg proto.Message, f Format, opts convertOpts) error {
+	var err error
+	switch f {
+	case FormatWire:
+		err = proto.UnmarshalOptions{Resolver: opts.resolver}.Unmarshal(b, msg)
+	case FormatJSON:
+		err = protojson.UnmarshalOptions{Resolver: opts.resolver}.Unmarshal(b, msg)
+	case FormatText:
+		err = prototext.UnmarshalOptions{Resolver: opts.resolver}.Unmarshal(b, msg)
+	case FormatYAML:
+		b, err = yamlToJSON(b, msg.ProtoReflect().Descriptor())
+		if err == nil {
+			err = protojson.UnmarshalOptions{Resolver: opts.resolver}.Unmarshal(b, msg)
+		}
+	default:
+		return Errorf(ErrParse, "format(%s) is not supported", f)
+	}
+	if err != nil {
+		return Errorf(ErrParse, "could not read %s as %s: %s", msg.ProtoReflect().Descriptor().FullName(), f, err)
+	}
+	return nil
+}
+
+// jsonToYAML converts JSON to block style YAML, keeping the order of the fields.
+func jsonToYAML(b []byte) ([]byte, error) {
+	// JSON is YAML, so yaml.v3 can read it into a Node, which keeps the order of the keys.
+	var node yaml.Node
+	if err := yaml.Unmarshal(b, &node); err != nil {
+		return nil, err
+	}
+	blockStyle(&node)
+	return yaml.Marshal(&node)
+}
+
+// blockStyle removes the JSON (flow and quoted) styles from node, so it is written like hand written YAML.
+// Strings that would read as another type are still quoted by the encoder.
+func blockStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, n := range node.Content {
+		blockStyle(n)
+	}
+}
+
+// yamlToJSON converts YAML for a message md to JSON for protojson. Field names in YAML can be any spelling
+// that ProtoName() turns into the field's name, aka "OrderId" for "order_id", in addition to the proto and
+// JSON names that protojson accepts.
+func yamlToJSON(b []byte, md protoreflect.MessageDescriptor) ([]byte, error) {
+	var node yaml.Node
+	if err := yaml.Unmarshal(b, &node); err != nil {
+		return nil, err
+	}
+	if node.Kind == 0 {
+		return []byte("{}"), nil
+	}
+	doc := &node
+	if doc.Kind == yaml.DocumentNode && len(doc.Content) == 1 {
+		doc = doc.Content[0]
+	}
+	normalizeKeys(doc, md)
+
+	var v interface{}
+	if err := doc.Decode(&v); err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+// normalizeKeys renames the keys of node, which holds the message md, that are not a field's proto or JSON
+// name to the field's proto name if ProtoName() of the key is a field's proto name.
+func normalizeKeys(node *yaml.Node, md protoreflect.MessageDescriptor) {
+	if node.Kind != yaml.MappingNode || strings.HasPrefix(string(md.FullName()), "google.protobuf.") {
+		return
+	}
+	fields := md.Fields()
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, val := node.Content[i], node.Content[i+1]
+		fd := fields.ByName(protoreflect.Name(key.Value))
+		if fd == nil {
+			fd = fields.ByJSONName(key.Value)
+		}
+		if fd == nil {
+			fd = fields.ByName(protoreflect.Name(ProtoName(key.Value)))
+			if fd == nil {
+				continue
+			}
+			key.Value = string(fd.Name())
+		}
+
+		vd := valueDesc(fd)
+		if vd.Kind() != protoreflect.MessageKind && vd.Kind() != protoreflect.GroupKind {
+			continue
+		}
+		switch {
+		case fd.IsList() && val.Kind == yaml.SequenceNode:
+			for _, n := range val.Content {
+				normalizeKeys(n, vd.Message())
+			}
+		case fd.IsMap() && val.Kind == yaml.MappingNode:
+			for x := 1; x < len(val.Content); x += 2 {
+				normalizeKeys(val.Content[x], vd.Message())
+			}
+		default:
+			normalizeKeys(val, vd.Message())
+		}
+	}
+}
+
+// streamDecoder splits the encoded messages in a stream.
+type streamDecoder struct {
+	next func() ([]byte, error)
+}
+
+func newStreamDecoder(r io.Reader, mt protoreflect.MessageType, f Format, opts convertOpts) (*streamDecoder, error) {
+	if !opts.stream {
+		done := false
+		return &streamDecoder{next: func() ([]byte, error) {
+			if done {
+				return nil, io.EOF
+			}
+			done = true
+			return ioutil.ReadAll(r)
+		}}, nil
+	}
+
+	switch f {
+	case FormatWire:
+		dr, err := NewReader(r, mt, ReaderMaxSize(opts.maxSize))
+		if err != nil {
+			return nil, err
+		}
+		return &streamDecoder{next: dr.next}, nil
+	case FormatJSON:
+		dec := json.NewDecoder(r)
+		return &streamDecoder{next: func() ([]byte, error) {
+			var raw json.RawMessage
+			if err := dec.Decode(&raw); err != nil {
+				if err == io.EOF {
+					return nil, err
+				}
+				return nil, Errorf(ErrParse, "JSON stream could not be read: %s", err)
+			}
+			return raw, nil
+		}}, nil
+	case FormatText:
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(nil, 64*1024*1024)
+		return &streamDecoder{next: func() ([]byte, error) {
+			// An empty message is an empty line, so empty lines are not skipped.
+			if scanner.Scan() {
+				return bytes.TrimSpace(scanner.Bytes()), nil
+			}
+			if err := scanner.Err(); err != nil {
+				return nil, Errorf(ErrParse, "text stream could not be read: %s", err)
+			}
+			return nil, io.EOF
+		}}, nil
+	case FormatYAML:
+		dec := yaml.NewDecoder(r)
+		return &streamDecoder{next: func() ([]byte, error) {
+			var node yaml.Node
+			if err := dec.Decode(&node); err != nil {
+				if err == io.EOF {
+					return nil, err
+				}
+				return nil, Errorf(ErrParse, "YAML stream could not be read: %s", err)
+			}
+			return yaml.Marshal(&node)
+		}}, nil
+	}
+	return nil, Errorf(ErrParse, "format(%s) is not supported", f)
+}
+
+// streamEncoder joins encoded messages into a stream.
+type streamEncoder struct {
+	w      io.Writer
+	f      Format
+	stream bool
+	// count is the number of messages written.
+	count int
+}
+
+func newStreamEncoder(w io.Writer, f Format, stream bool) *streamEncoder {
+	return &streamEncoder{w: w, f: f, stream: stream}
+}
+
+func (e *streamEncoder) write(b []byte) error {
+	defer func() { e.count++ }()
+	if !e.stream {
+		if e.f == FormatJSON || e.f == FormatText {
+			b = appendNewline(b)
+		}
+		_, err := e.w.Write(b)
+		return err
+	}
+
+	switch e.f {
+	case FormatWire:
+		b = appendDelimited(nil, b)
+	case FormatJSON, FormatText:
+		b = appendNewline(b)
+	case FormatYAML:
+		if e.count > 0 {
+			b = append([]byte("---\n"), b...)
+		}
+	}
+	_, err := e.w.Write(b)
+	return err
+}
+
+func appendNewline(b []byte) []byte {
+	if len(b) > 0 && b[len(b)-1] == '\n' {
+		return b
+	}
+	return append(b, '\n')
+}
@@ -0,0 +1,277 @@
+package prototools
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/johnsiilver/prototools/sample"
+)
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		s    string
+		want Format
+		err  bool
+	}{
+		{s: "wire", want: FormatWire},
+		{s: "JSON", want: FormatJSON},
+		{s: "text", want: FormatText},
+		{s: "Yaml", want: FormatYAML},
+		{s: "xml", err: true},
+	}
+
+	for _, test := range tests {
+		got, err := ParseFormat(test.s)
+		switch {
+		case err == nil && test.err:
+			t.Errorf("TestParseFormat(%s): got err == nil, want err != nil", test.s)
+			continue
+		case err != nil && !test.err:
+			t.Errorf("TestParseFormat(%s): got err == %s, want err == nil", test.s, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("TestParseFormat(%s): got %s, want %s", test.s, got, test.want)
+		}
+	}
+
+	for p, want := range map[string]Format{"a.json": FormatJSON, "a.YML": FormatYAML, "a.textproto": FormatText, "a.bin": FormatWire, "a": FormatWire} {
+		if got := FormatFromPath(p); got != want {
+			t.Errorf("TestParseFormat(FormatFromPath(%s)): got %s, want %s", p, got, want)
+		}
+	}
+}
+
+func TestMarshalUnmarshal(t *testing.T) {
+	msgs := []proto.Message{
+		newCustomer(),
+		&pb.Supported{Ev: pb.EnumValues_EV_Ok, Vstring: "true", Vint64: 1 << 60, VTime: 1612812462, Vdouble: 1.5},
+		&pb.Customer{Labels: map[string]string{"1": "one"}, ById: map[int64]*pb.Order{5: {Id: "five"}}},
+		newWellKnown(),
+	}
+
+	for _, f := range []Format{FormatWire, FormatJSON, FormatText, FormatYAML} {
+		for _, want := range msgs {
+			b, err := Marshal(want, f)
+			if err != nil {
+				t.Errorf("TestMarshalUnmarshal(%s): Marshal got err == %s", f, err)
+				continue
+			}
+			got := want.ProtoReflect().New().Interface()
+			if err := Unmarshal(b, got, f); err != nil {
+				t.Errorf("TestMarshalUnmarshal(%s): Unmarshal got err == %s, input:\n%s", f, err, b)
+				continue
+			}
+			if diff := Equal(want, got); diff != "" {
+				t.Errorf("TestMarshalUnmarshal(%s): -want/+got:\n%s", f, diff)
+			}
+		}
+	}
+}
+
+func TestMarshalOptions(t *testing.T) {
+	msg := &pb.Supported{Ev: pb.EnumValues_EV_Ok, VTime: 10}
+
+	tests := []struct {
+		desc    string
+		f       Format
+		options []ConvertOption
+		want    []string
+		notWant []string
+	}{
+		{
+			desc:    "YAML",
+			f:       FormatYAML,
+			want:    []string{"ev: EV_Ok\n", `vTime: "10"` + "\n"},
+			notWant: []string{"{", "vbool"},
+		},
+		{
+			desc:    "YAML proto names",
+			f:       FormatYAML,
+			options: []ConvertOption{ConvertProtoNames()},
+			want:    []string{`v_time: "10"`},
+		},
+		{
+			desc:    "YAML enum numbers and defaults",
+			f:       FormatYAML,
+			options: []ConvertOption{ConvertEnumNumbers(), ConvertEmitDefaults()},
+			want:    []string{"ev: 1\n", "vbool: false\n"},
+		},
+		{
+			desc:    "JSON proto names",
+			f:       FormatJSON,
+			options: []ConvertOption{ConvertProtoNames(), ConvertEnumNumbers()},
+			want:    []string{`"v_time"`, `"ev":1`},
+		},
+		{
+			desc:    "JSON multiline",
+			f:       FormatJSON,
+			options: []ConvertOption{ConvertMultiline()},
+			want:    []string{"{\n"},
+		},
+	}
+
+	for _, test := range tests {
+		b, err := Marshal(msg, test.f, test.options...)
+		if err != nil {
+			t.Errorf("TestMarshalOptions(%s): got err == %s, want err == nil", test.desc, err)
+			continue
+		}
+		got := strings.Replace(string(b), " ", "", -1)
+		for _, want := range test.want {
+			if !strings.Contains(got, strings.Replace(want, " ", "", -1)) {
+				t.Errorf("TestMarshalOptions(%s): got:\n%s\nwant it to contain %q", test.desc, b, want)
+			}
+		}
+		for _, notWant := range test.notWant {
+			if strings.Contains(got, notWant) {
+				t.Errorf("TestMarshalOptions(%s): got:\n%s\nwant it to not contain %q", test.desc, b, notWant)
+			}
+		}
+	}
+}
+
+func TestUnmarshalYAML(t *testing.T) {
+	tests := []struct {
+		desc string
+		yaml string
+		want proto.Message
+		code ErrCode
+	}{
+		{
+			desc: "proto and JSON names",
+			yaml: "name: John\nby_id:\n  5:\n    id: five\n",
+			want: &pb.Customer{Name: "John", ById: map[int64]*pb.Order{5: {Id: "five"}}},
+		},
+		{
+			desc: "names ProtoName() converts",
+			yaml: "Name: John\nOrders:\n  - Id: order0\n    Lines:\n      - Sku: sku0\nLabels:\n  My Key: v\n",
+			want: &pb.Customer{
+				Name:   "John",
+				Orders: []*pb.Order{{Id: "order0", Lines: []*pb.Line{{Sku: "sku0"}}}},
+				Labels: map[string]string{"My Key": "v"},
+			},
+		},
+		{
+			desc: "empty",
+			yaml: "",
+			want: &pb.Customer{},
+		},
+		{
+			desc: "unknown field",
+			yaml: "nope: 1\n",
+			code: ErrParse,
+		},
+		{
+			desc: "not YAML",
+			yaml: "name: [",
+			code: ErrParse,
+		},
+	}
+
+	for _, test := range tests {
+		got := &pb.Customer{}
+		err := Unmarshal([]byte(test.yaml), got, FormatYAML)
+		if !checkCode(t, "TestUnmarshalYAML", test.desc, err, test.code) {
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if diff := Equal(test.want, got); diff != "" {
+			t.Errorf("TestUnmarshalYAML(%s): -want/+got:\n%s", test.desc, diff)
+		}
+	}
+}
+
+func TestConvertStream(t *testing.T) {
+	want := []*pb.Customer{newCustomer(), {Name: "Jane"}, {}}
+
+	var wire []byte
+	for _, msg := range want {
+		b, err := proto.Marshal(msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		wire = protowire.AppendBytes(wire, b)
+	}
+	mt := (&pb.Customer{}).ProtoReflect().Type()
+
+	// Convert the stream through every format and back to the wire format.
+	in := wire
+	formats := []Format{FormatWire, FormatJSON, FormatYAML, FormatText, FormatWire}
+	for i := 0; i < len(formats)-1; i++ {
+		out := &bytes.Buffer{}
+		if err := Convert(out, bytes.NewReader(in), mt, formats[i], formats[i+1], ConvertStream(), ConvertMultiline()); err != nil {
+			t.Fatalf("TestConvertStream(%s to %s): got err == %s, want err == nil", formats[i], formats[i+1], err)
+		}
+		in = out.Bytes()
+	}
+
+	var got []*pb.Customer
+	for len(in) > 0 {
+		b, n := protowire.ConsumeBytes(in)
+		if n < 0 {
+			t.Fatalf("TestConvertStream: bad stream: %s", protowire.ParseError(n))
+		}
+		msg := &pb.Customer{}
+		if err := proto.Unmarshal(b, msg); err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, msg)
+		in = in[n:]
+	}
+	if len(got) != len(want) {
+		t.Fatalf("TestConvertStream: got %d messages, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if diff := Equal(want[i], got[i]); diff != "" {
+			t.Errorf("TestConvertStream(message %d): -want/+got:\n%s", i, diff)
+		}
+	}
+}
+
+func TestConvert(t *testing.T) {
+	mt := (&pb.Customer{}).ProtoReflect().Type()
+
+	out := &bytes.Buffer{}
+	if err := Convert(out, strings.NewReader(`{"name": "John", "tags": ["vip"]}`), mt, FormatJSON, FormatText, ConvertMultiline()); err != nil {
+		t.Fatalf("TestConvert: got err == %s, want err == nil", err)
+	}
+	if got := strings.Replace(out.String(), " ", "", -1); got != "name:\"John\"\ntags:\"vip\"\n" {
+		t.Errorf("TestConvert: got %q", out.String())
+	}
+
+	err := Convert(&bytes.Buffer{}, strings.NewReader(`{"name": 3}`), mt, FormatJSON, FormatText)
+	checkCode(t, "TestConvert", "bad JSON", err, ErrParse)
+
+	err = Convert(&bytes.Buffer{}, bytes.NewReader([]byte{10, 5, 1}), mt, FormatWire, FormatJSON, ConvertStream())
+	checkCode(t, "TestConvert", "short stream", err, ErrParse)
+
+	// A corrupt size must be rejected before a buffer of that size is allocated.
+	huge := protowire.AppendVarint(nil, 1<<62)
+	err = Convert(&bytes.Buffer{}, bytes.NewReader(huge), mt, FormatWire, FormatJSON, ConvertStream())
+	checkCode(t, "TestConvert", "huge size", err, ErrValueOutOfRange)
+
+	big := protowire.AppendVarint(nil, DefaultMaxSize+1)
+	err = Convert(&bytes.Buffer{}, bytes.NewReader(big), mt, FormatWire, FormatJSON, ConvertStream())
+	checkCode(t, "TestConvert", "more than DefaultMaxSize", err, ErrValueOutOfRange)
+
+	msg, err := proto.Marshal(newCustomer())
+	if err != nil {
+		t.Fatal(err)
+	}
+	stream := protowire.AppendBytes(nil, msg)
+	err = Convert(&bytes.Buffer{}, bytes.NewReader(stream), mt, FormatWire, FormatJSON, ConvertStream(), ConvertMaxSize(len(msg)-1))
+	checkCode(t, "TestConvert", "more than ConvertMaxSize", err, ErrValueOutOfRange)
+
+	err = Convert(&bytes.Buffer{}, bytes.NewReader(stream), mt, FormatWire, FormatJSON, ConvertStream(), ConvertMaxSize(len(msg)))
+	checkCode(t, "TestConvert", "ConvertMaxSize", err, ErrUnknown)
+
+	err = Convert(&bytes.Buffer{}, bytes.NewReader(stream), mt, FormatWire, FormatJSON, ConvertStream(), ConvertMaxSize(0))
+	checkCode(t, "TestConvert", "ConvertMaxSize(0)", err, ErrValueOutOfRange)
+}
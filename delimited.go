@@ -0,0 +1,344 @@
+package prototools
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// DefaultMaxSize is the largest message a Reader reads unless ReaderMaxSize() is used. This is the same
+// as protodelim.
+const DefaultMaxSize = 4 << 20
+
+// indexEntrySize is the size of an entry in a sidecar index, which is the offset of a record as a
+// big endian uint64.
+const indexEntrySize = 8
+
+type readerOpts struct {
+	index    io.ReaderAt
+	maxSize  int
+	resolver Resolver
+}
+
+// ReaderOption is an option for NewReader().
+type ReaderOption func(o *readerOpts)
+
+// ReaderIndex gives the Reader the sidecar index written by a Writer with WriterIndex(). This allows
+// Seek() to go to a record without reading the ones before it.
+func ReaderIndex(index io.ReaderAt) ReaderOption {
+	return func(o *readerOpts) {
+		o.index = index
+	}
+}
+
+// ReaderMaxSize changes the size of the largest message the Reader will read from DefaultMaxSize.
+// A larger message is an error, which protects against reading a file that is not length-delimited.
+func ReaderMaxSize(size int) ReaderOption {
+	return func(o *readerOpts) {
+		o.maxSize = size
+	}
+}
+
+// ReaderResolver finds the extensions when messages are read, such as the Types of a Registry.
+// This defaults to protoregistry.GlobalTypes. It is not used for paths in Scan() that step through a
+// google.protobuf.Any, those use AnyResolver like GetField() does.
+func ReaderResolver(r Resolver) ReaderOption {
+	return func(o *readerOpts) {
+		o.resolver = r
+	}
+}
+
+/*
+Reader reads messages stored one after another, each with its size before it as a varint. This is the framing
+of protodelim and of Java's writeDelimitedTo(). Only one message is in memory at a time, so files of any size
+can be read.
+
+	r, err := prototools.NewReader(f, (&pb.Customer{}).ProtoReflect().Type())
+	if err != nil {
+		// Do something
+	}
+	for {
+		msg, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			// Do something
+		}
+		customer := msg.(*pb.Customer)
+		...
+	}
+
+Use Scan() to get a few fields of each message, which avoids decoding the rest of each message.
+*/
+type Reader struct {
+	src  io.Reader
+	r    *bufio.Reader
+	mt   protoreflect.MessageType
+	opts readerOpts
+
+	// record is the index of the next record.
+	record int
+	buf    []byte
+}
+
+// NewReader returns a Reader for messages of type mt in r. r must be an io.ReadSeeker for Seek().
+func NewReader(r io.Reader, mt protoreflect.MessageType, options ...ReaderOption) (*Reader, error) {
+	opts := readerOpts{maxSize: DefaultMaxSize, resolver: protoregistry.GlobalTypes}
+	for _, o := range options {
+		o(&opts)
+	}
+	if opts.maxSize <= 0 {
+		return nil, Errorf(ErrValueOutOfRange, "ReaderMaxSize(%d) must be more than 0", opts.maxSize)
+	}
+	return &Reader{src: r, r: bufio.NewReader(r), mt: mt, opts: opts}, nil
+}
+
+// Record returns the index of the record that Next() will read.
+func (r *Reader) Record() int {
+	return r.record
+}
+
+// Next returns the next message. It returns io.EOF when there are no more messages.
+func (r *Reader) Next() (proto.Message, error) {
+	b, err := r.next()
+	if err != nil {
+		return nil, err
+	}
+	msg := r.mt.New().Interface()
+	if err := r.unmarshal(b, msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+/*
+Scan calls f with the values at fqPaths in each message that is left, in the same order as fqPaths. A value is
+what GetField() returns, so a path that steps through a google.protobuf.Any unpacks it with AnyResolver and
+not with the ReaderResolver(). If a path cannot be followed in a message because a message, list entry or map
+key in it is not there, its FieldValue is empty. Only the fields the paths start with are decoded, so this
+is much cheaper than Next() when the paths use a small part of each message.
+
+The values are only good until f returns, as they may be in a message that is reused. If f returns an error,
+Scan stops and returns it.
+
+	// Print the id of every customer's first order.
+	err := r.Scan([]string{"name", "orders[0].id"}, func(record int, values []prototools.FieldValue) error {
+		fmt.Println(record, values[0].Value, values[1].Value)
+		return nil
+	})
+*/
+func (r *Reader) Scan(fqPaths []string, f func(record int, values []FieldValue) error) error {
+	md := r.mt.Descriptor()
+	keep := map[protowire.Number]bool{}
+	for _, fqPath := range fqPaths {
+		fields := FQPathSplit(fqPath)
+		if _, err := checkPath(md, checkableFields(md, fields)); err != nil {
+			return err
+		}
+		pp, err := parsePart(fields[0])
+		if err != nil {
+			return err
+		}
+		keep[partField(md, pp.name).Number()] = true
+	}
+
+	msg := r.mt.New()
+	values := make([]FieldValue, len(fqPaths))
+	for {
+		record := r.record
+		b, err := r.next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		b, err = projectWire(b, keep)
+		if err != nil {
+			return Errorf(ErrParse, "record(%d) is not a %s: %s", record, md.FullName(), err)
+		}
+		proto.Reset(msg.Interface())
+		if err := r.unmarshal(b, msg.Interface()); err != nil {
+			return err
+		}
+
+		for i, fqPath := range fqPaths {
+			fv, err := GetField(msg.Interface(), fqPath)
+			if err != nil {
+				if e, ok := err.(Error); !ok || !missingCode(e.Code) {
+					return err
+				}
+				fv = FieldValue{}
+			}
+			values[i] = fv
+		}
+		if err := f(record, values); err != nil {
+			return err
+		}
+	}
+}
+
+// missingCode returns true if code means a path could not be followed because something in the message
+// was not set, instead of the path being wrong.
+func missingCode(code ErrCode) bool {
+	switch code {
+	case ErrIntermdiateNotSet, ErrIndexOutOfRange, ErrKeyNotFound:
+		return true
+	}
+	return false
+}
+
+/*
+Seek moves the Reader to the record at index, so Next() returns it. This needs the Reader to have the sidecar
+index from ReaderIndex() and to be reading from an io.ReadSeeker.
+*/
+func (r *Reader) Seek(index int) error {
+	if r.opts.index == nil {
+		return fmt.Errorf("Seek() requires a Reader made with ReaderIndex()")
+	}
+	seeker, ok := r.src.(io.Seeker)
+	if !ok {
+		return fmt.Errorf("Seek() requires a Reader that reads from an io.ReadSeeker, had %T", r.src)
+	}
+	if index < 0 {
+		return Errorf(ErrIndexOutOfRange, "record(%d) is not in the index", index)
+	}
+
+	var entry [indexEntrySize]byte
+	if _, err := r.opts.index.ReadAt(entry[:], int64(index)*indexEntrySize); err != nil {
+		if err == io.EOF {
+			return Errorf(ErrIndexOutOfRange, "record(%d) is not in the index", index)
+		}
+		return err
+	}
+	if _, err := seeker.Seek(int64(binary.BigEndian.Uint64(entry[:])), io.SeekStart); err != nil {
+		return err
+	}
+	r.r.Reset(r.src)
+	r.record = index
+	return nil
+}
+
+// next returns the encoded next message. The bytes are only good until the next call.
+func (r *Reader) next() ([]byte, error) {
+	size, err := binary.ReadUvarint(r.r)
+	if err != nil {
+		if err == io.EOF {
+			return nil, err
+		}
+		return nil, Errorf(ErrParse, "size of record(%d) could not be read: %s", r.record, err)
+	}
+	if size > uint64(r.opts.maxSize) {
+		return nil, Errorf(ErrValueOutOfRange, "record(%d) is %d bytes, which is more than the max size of %d", r.record, size, r.opts.maxSize)
+	}
+
+	if uint64(cap(r.buf)) < size {
+		r.buf = make([]byte, size)
+	}
+	b := r.buf[:size]
+	if _, err := io.ReadFull(r.r, b); err != nil {
+		return nil, Errorf(ErrParse, "record(%d) of %d bytes could not be read: %s", r.record, size, err)
+	}
+	r.record++
+	return b, nil
+}
+
+func (r *Reader) unmarshal(b []byte, msg proto.Message) error {
+	if err := (proto.UnmarshalOptions{Resolver: r.opts.resolver}).Unmarshal(b, msg); err != nil {
+		return Errorf(ErrParse, "record(%d) is not a %s: %s", r.record-1, msg.ProtoReflect().Descriptor().FullName(), err)
+	}
+	return nil
+}
+
+// projectWire returns the fields of the encoded message b whose numbers are in keep.
+func projectWire(b []byte, keep map[protowire.Number]bool) ([]byte, error) {
+	var out []byte
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return nil, protowire.ParseError(n)
+		}
+		m := protowire.ConsumeFieldValue(num, typ, b[n:])
+		if m < 0 {
+			return nil, protowire.ParseError(m)
+		}
+		if keep[num] {
+			out = append(out, b[:n+m]...)
+		}
+		b = b[n+m:]
+	}
+	return out, nil
+}
+
+type writerOpts struct {
+	index io.Writer
+}
+
+// WriterOption is an option for NewWriter().
+type WriterOption func(o *writerOpts)
+
+// WriterIndex writes a sidecar index to index, which lets a Reader made with ReaderIndex() seek to a record.
+// The index has the offset of each record as a big endian uint64.
+func WriterIndex(index io.Writer) WriterOption {
+	return func(o *writerOpts) {
+		o.index = index
+	}
+}
+
+// Writer writes messages one after another, each with its size before it as a varint. See Reader.
+type Writer struct {
+	w    io.Writer
+	opts writerOpts
+
+	// offset is where the next record starts.
+	offset int64
+	// buf and rec are reused for the encoded message and the record.
+	buf, rec []byte
+}
+
+// NewWriter returns a Writer that writes messages to w. If w starts at an offset other than 0 in its file,
+// such as when appending, the offsets in the index will not be right.
+func NewWriter(w io.Writer, options ...WriterOption) *Writer {
+	opts := writerOpts{}
+	for _, o := range options {
+		o(&opts)
+	}
+	return &Writer{w: w, opts: opts}
+}
+
+// Write writes msg as the next record.
+func (w *Writer) Write(msg proto.Message) error {
+	b, err := proto.MarshalOptions{}.MarshalAppend(w.buf[:0], msg)
+	if err != nil {
+		return Errorf(ErrParse, "could not marshal %s: %s", msg.ProtoReflect().Descriptor().FullName(), err)
+	}
+	w.buf = b
+
+	w.rec = appendDelimited(w.rec[:0], b)
+	if _, err := w.w.Write(w.rec); err != nil {
+		return err
+	}
+	if w.opts.index != nil {
+		var entry [indexEntrySize]byte
+		binary.BigEndian.PutUint64(entry[:], uint64(w.offset))
+		if _, err := w.opts.index.Write(entry[:]); err != nil {
+			return err
+		}
+	}
+	w.offset += int64(len(w.rec))
+	return nil
+}
+
+// appendDelimited appends the encoded message b to dst with its size before it.
+func appendDelimited(dst, b []byte) []byte {
+	dst = protowire.AppendVarint(dst, uint64(len(b)))
+	return append(dst, b...)
+}
@@ -0,0 +1,249 @@
+package prototools
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protowire"
+
+	pb "github.com/johnsiilver/prototools/sample"
+)
+
+// writeCustomers writes msgs with a Writer and returns the records and the sidecar index.
+func writeCustomers(t *testing.T, msgs []*pb.Customer) (data, index []byte) {
+	t.Helper()
+	buf, idx := &bytes.Buffer{}, &bytes.Buffer{}
+	w := NewWriter(buf, WriterIndex(idx))
+	for _, msg := range msgs {
+		if err := w.Write(msg); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return buf.Bytes(), idx.Bytes()
+}
+
+func delimitedCustomers() []*pb.Customer {
+	return []*pb.Customer{
+		newCustomer(),
+		{Name: "Jane", Labels: map[string]string{"env": "prod"}},
+		{},
+		{Name: "Bob", Orders: []*pb.Order{{Id: "bob0"}}},
+	}
+}
+
+func TestWriterReader(t *testing.T) {
+	want := delimitedCustomers()
+	data, _ := writeCustomers(t, want)
+
+	// The framing is a varint size and the message, the same as protodelim.
+	b := data
+	for i, msg := range want {
+		rec, n := protowire.ConsumeBytes(b)
+		if n < 0 {
+			t.Fatalf("TestWriterReader: record %d is not length-delimited: %s", i, protowire.ParseError(n))
+		}
+		if wire := wireBytes(t, msg); string(rec) != wire {
+			t.Errorf("TestWriterReader: record %d is not the marshaled message", i)
+		}
+		b = b[n:]
+	}
+
+	r, err := NewReader(bytes.NewReader(data), (&pb.Customer{}).ProtoReflect().Type())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, msg := range want {
+		if r.Record() != i {
+			t.Errorf("TestWriterReader: got Record() == %d, want %d", r.Record(), i)
+		}
+		got, err := r.Next()
+		if err != nil {
+			t.Fatalf("TestWriterReader: record %d got err == %s, want err == nil", i, err)
+		}
+		if diff := Equal(msg, got); diff != "" {
+			t.Errorf("TestWriterReader: record %d -want/+got:\n%s", i, diff)
+		}
+	}
+	if _, err := r.Next(); err != io.EOF {
+		t.Errorf("TestWriterReader: got err == %v at the end, want io.EOF", err)
+	}
+}
+
+func TestReaderSeek(t *testing.T) {
+	msgs := delimitedCustomers()
+	data, index := writeCustomers(t, msgs)
+	mt := (&pb.Customer{}).ProtoReflect().Type()
+
+	r, err := NewReader(bytes.NewReader(data), mt, ReaderIndex(bytes.NewReader(index)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		desc   string
+		record int
+		code   ErrCode
+	}{
+		{desc: "last", record: 3},
+		{desc: "first", record: 0},
+		{desc: "empty message", record: 2},
+		{desc: "past the end", record: 4, code: ErrIndexOutOfRange},
+		{desc: "negative", record: -1, code: ErrIndexOutOfRange},
+	}
+
+	for _, test := range tests {
+		err := r.Seek(test.record)
+		if !checkCode(t, "TestReaderSeek", test.desc, err, test.code) {
+			continue
+		}
+		if err != nil {
+			continue
+		}
+
+		got, err := r.Next()
+		if err != nil {
+			t.Errorf("TestReaderSeek(%s): got err == %s, want err == nil", test.desc, err)
+			continue
+		}
+		if diff := Equal(msgs[test.record], got); diff != "" {
+			t.Errorf("TestReaderSeek(%s): -want/+got:\n%s", test.desc, diff)
+		}
+		if r.Record() != test.record+1 {
+			t.Errorf("TestReaderSeek(%s): got Record() == %d, want %d", test.desc, r.Record(), test.record+1)
+		}
+	}
+
+	noIndex, err := NewReader(bytes.NewReader(data), mt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := noIndex.Seek(1); err == nil {
+		t.Errorf("TestReaderSeek(no index): got err == nil, want err != nil")
+	}
+	noSeeker, err := NewReader(bytes.NewBuffer(data), mt, ReaderIndex(bytes.NewReader(index)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := noSeeker.Seek(1); err == nil {
+		t.Errorf("TestReaderSeek(not a seeker): got err == nil, want err != nil")
+	}
+}
+
+func TestReaderScan(t *testing.T) {
+	data, _ := writeCustomers(t, delimitedCustomers())
+	mt := (&pb.Customer{}).ProtoReflect().Type()
+
+	tests := []struct {
+		desc    string
+		fqPaths []string
+		want    [][]interface{}
+		code    ErrCode
+	}{
+		{
+			desc:    "columns",
+			fqPaths: []string{"name", "orders[0].id", `labels["env"]`},
+			want: [][]interface{}{
+				{"John", "order0", nil},
+				{"Jane", nil, "prod"},
+				{"", nil, nil},
+				{"Bob", "bob0", nil},
+			},
+		},
+		{
+			desc:    "bad field",
+			fqPaths: []string{"name", "nope"},
+			code:    ErrBadFieldName,
+		},
+		{
+			desc:    "not a message",
+			fqPaths: []string{"name.first"},
+			code:    ErrIntermediateNotMessage,
+		},
+	}
+
+	for _, test := range tests {
+		r, err := NewReader(bytes.NewReader(data), mt)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var got [][]interface{}
+		err = r.Scan(test.fqPaths, func(record int, values []FieldValue) error {
+			if record != len(got) {
+				t.Errorf("TestReaderScan(%s): got record %d, want %d", test.desc, record, len(got))
+			}
+			row := make([]interface{}, len(values))
+			for i, v := range values {
+				row[i] = v.Value
+			}
+			got = append(got, row)
+			return nil
+		})
+		if !checkCode(t, "TestReaderScan", test.desc, err, test.code) {
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if len(got) != len(test.want) {
+			t.Errorf("TestReaderScan(%s): got %d records, want %d", test.desc, len(got), len(test.want))
+			continue
+		}
+		for i := range test.want {
+			for x := range test.want[i] {
+				if got[i][x] != test.want[i][x] {
+					t.Errorf("TestReaderScan(%s): record %d path %s: got %v, want %v", test.desc, i, test.fqPaths[x], got[i][x], test.want[i][x])
+				}
+			}
+		}
+	}
+
+	// An error from f stops the scan.
+	r, err := NewReader(bytes.NewReader(data), mt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stop := errors.New("stop")
+	calls := 0
+	err = r.Scan([]string{"name"}, func(int, []FieldValue) error {
+		calls++
+		return stop
+	})
+	if err != stop || calls != 1 {
+		t.Errorf("TestReaderScan(stop): got err == %v after %d calls, want stop after 1", err, calls)
+	}
+}
+
+func TestReaderErrors(t *testing.T) {
+	data, _ := writeCustomers(t, delimitedCustomers())
+	mt := (&pb.Customer{}).ProtoReflect().Type()
+	// Field 1 says it has 5 bytes, but there is only 1.
+	bad := []byte{0x0a, 0x05, 'a'}
+
+	tests := []struct {
+		desc    string
+		data    []byte
+		options []ReaderOption
+		code    ErrCode
+	}{
+		{desc: "larger than max size", data: data, options: []ReaderOption{ReaderMaxSize(2)}, code: ErrValueOutOfRange},
+		{desc: "truncated record", data: data[:5], code: ErrParse},
+		{desc: "truncated size", data: []byte{0x80}, code: ErrParse},
+		{desc: "bad message", data: appendDelimited(nil, bad), code: ErrParse},
+	}
+
+	for _, test := range tests {
+		r, err := NewReader(bytes.NewReader(test.data), mt, test.options...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = r.Next()
+		checkCode(t, "TestReaderErrors", test.desc, err, test.code)
+	}
+
+	if _, err := NewReader(bytes.NewReader(data), mt, ReaderMaxSize(0)); err == nil {
+		t.Errorf("TestReaderErrors(max size 0): got err == nil, want err != nil")
+	}
+}
@@ -0,0 +1,111 @@
+package prototools
+
+import (
+	"bytes"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// Registry holds the files and types from a FileDescriptorSet. The messages it makes are dynamicpb messages,
+// which work with every function in this package the same as generated messages.
+type Registry struct {
+	// Files has every file in the set.
+	Files *protoregistry.Files
+	// Types has every message, enum and extension in the set. Set AnyResolver and ExtensionResolver to this
+	// if paths step into Any messages or extensions that only the set knows about.
+	Types *protoregistry.Types
+}
+
+/*
+LoadDescriptorSet reads a FileDescriptorSet, such as a .protoset file, and returns a Registry for its types.
+b can be the binary wire format or protojson, which is detected by b starting with a "{".
+
+The set must have every file that a file in it imports, which protoc does with --include_imports.
+This includes the well-known types, which are not taken from the Go registry, so that the set is
+complete on its own.
+
+	b, err := ioutil.ReadFile("service.protoset")
+	if err != nil {
+		// Do something
+	}
+	reg, err := prototools.LoadDescriptorSet(b)
+	if err != nil {
+		// Do something
+	}
+	msg, err := reg.NewMessage("my.pkg.Customer")
+	if err != nil {
+		// Do something
+	}
+	if err := proto.Unmarshal(data, msg); err != nil {
+		// Do something
+	}
+	fv, err := prototools.GetField(msg, "orders[0].id")
+*/
+func LoadDescriptorSet(b []byte) (*Registry, error) {
+	set := &descriptorpb.FileDescriptorSet{}
+	if bytes.HasPrefix(bytes.TrimSpace(b), []byte("{")) {
+		if err := protojson.Unmarshal(b, set); err != nil {
+			return nil, Errorf(ErrParse, "could not unmarshal the FileDescriptorSet from JSON: %s", err)
+		}
+	} else {
+		if err := proto.Unmarshal(b, set); err != nil {
+			return nil, Errorf(ErrParse, "could not unmarshal the FileDescriptorSet: %s", err)
+		}
+	}
+
+	files, err := protodesc.NewFiles(set)
+	if err != nil {
+		return nil, Errorf(ErrParse, "FileDescriptorSet is not valid: %s", err)
+	}
+
+	types := &protoregistry.Types{}
+	files.RangeFiles(func(fd protoreflect.FileDescriptor) bool {
+		err = registerTypes(types, fd.Messages(), fd.Enums(), fd.Extensions())
+		return err == nil
+	})
+	if err != nil {
+		return nil, Errorf(ErrParse, "FileDescriptorSet is not valid: %s", err)
+	}
+
+	return &Registry{Files: files, Types: types}, nil
+}
+
+// NewMessage returns an empty message of the type with fullName, aka "my.pkg.Customer".
+func (r *Registry) NewMessage(fullName string) (*dynamicpb.Message, error) {
+	mt, err := r.Types.FindMessageByName(protoreflect.FullName(fullName))
+	if err != nil {
+		return nil, Errorf(ErrTypeNotFound, "message type(%s) is not in the FileDescriptorSet", fullName)
+	}
+	return mt.New().Interface().(*dynamicpb.Message), nil
+}
+
+// registerTypes registers the messages, enums and extensions of a file or message with types, including the
+// ones declared inside of the messages.
+func registerTypes(types *protoregistry.Types, msgs protoreflect.MessageDescriptors, enums protoreflect.EnumDescriptors, exts protoreflect.ExtensionDescriptors) error {
+	for i := 0; i < enums.Len(); i++ {
+		if err := types.RegisterEnum(dynamicpb.NewEnumType(enums.Get(i))); err != nil {
+			return err
+		}
+	}
+	for i := 0; i < exts.Len(); i++ {
+		if err := types.RegisterExtension(dynamicpb.NewExtensionType(exts.Get(i))); err != nil {
+			return err
+		}
+	}
+	for i := 0; i < msgs.Len(); i++ {
+		md := msgs.Get(i)
+		if err := types.RegisterMessage(dynamicpb.NewMessageType(md)); err != nil {
+			return err
+		}
+		if err := registerTypes(types, md.Messages(), md.Enums(), md.Extensions()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,251 @@
+package prototools
+
+import (
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	"github.com/johnsiilver/prototools/internal/testutil"
+	pb "github.com/johnsiilver/prototools/sample"
+)
+
+// sampleRegistry returns a Registry for all the files in the sample package.
+func sampleRegistry(t *testing.T) *Registry {
+	t.Helper()
+	b, err := proto.Marshal(testutil.FileDescriptorSet(pb.File_sample_proto, pb.File_store_proto, pb.File_kinds_proto, pb.File_wellknown_proto, pb.File_legacy_proto))
+	if err != nil {
+		t.Fatal(err)
+	}
+	reg, err := LoadDescriptorSet(b)
+	if err != nil {
+		t.Fatalf("LoadDescriptorSet: got err == %s, want err == nil", err)
+	}
+	return reg
+}
+
+// toDynamic copies msg into a dynamic message from reg.
+func toDynamic(t *testing.T, reg *Registry, msg proto.Message) proto.Message {
+	t.Helper()
+	dyn, err := reg.NewMessage(string(msg.ProtoReflect().Descriptor().FullName()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := proto.Marshal(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := (proto.UnmarshalOptions{Resolver: reg.Types}).Unmarshal(b, dyn); err != nil {
+		t.Fatal(err)
+	}
+	return dyn
+}
+
+// wireBytes is the deterministic wire encoding of msg, used to compare a generated and a dynamic message.
+func wireBytes(t *testing.T, msg proto.Message) string {
+	t.Helper()
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestLoadDescriptorSet(t *testing.T) {
+	set := testutil.FileDescriptorSet(pb.File_store_proto)
+	binary, err := proto.Marshal(set)
+	if err != nil {
+		t.Fatal(err)
+	}
+	js, err := protojson.Marshal(set)
+	if err != nil {
+		t.Fatal(err)
+	}
+	missing, err := proto.Marshal(&descriptorpb.FileDescriptorSet{File: set.File[len(set.File)-1:]})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		desc string
+		b    []byte
+		code ErrCode
+	}{
+		{desc: "binary", b: binary},
+		{desc: "json", b: append([]byte("\n  "), js...)},
+		{desc: "not a FileDescriptorSet", b: []byte{0xff, 0xff}, code: ErrParse},
+		{desc: "bad json", b: []byte(`{"file": 3}`), code: ErrParse},
+		{desc: "import missing", b: missing, code: ErrParse},
+	}
+
+	for _, test := range tests {
+		reg, err := LoadDescriptorSet(test.b)
+		if !checkCode(t, "TestLoadDescriptorSet", test.desc, err, test.code) {
+			continue
+		}
+		if err != nil {
+			continue
+		}
+
+		for _, name := range []string{"r3.Customer", "r3.Supported", "r3.Layer0.EnumEmbedded"} {
+			var err error
+			if name == "r3.Layer0.EnumEmbedded" {
+				_, err = reg.Types.FindEnumByName(protoreflect.FullName(name))
+			} else {
+				_, err = reg.NewMessage(name)
+			}
+			if err != nil {
+				t.Errorf("TestLoadDescriptorSet(%s): type(%s) got err == %s, want err == nil", test.desc, name, err)
+			}
+		}
+		_, err = reg.NewMessage("r3.NotThere")
+		checkCode(t, "TestLoadDescriptorSet", test.desc+": unknown type", err, ErrTypeNotFound)
+	}
+}
+
+func TestDynamicParity(t *testing.T) {
+	reg := sampleRegistry(t)
+	defer func(r protoregistry.ExtensionTypeResolver) { ExtensionResolver = r }(ExtensionResolver)
+	ExtensionResolver = reg.Types
+
+	layer0 := &pb.Layer0{
+		Vint32: 1,
+		Ee:     pb.Layer0_EE_WHATEVER,
+		Layer1: &pb.Layer1{
+			Vstring:   "hello",
+			Supported: &pb.Supported{Ev: pb.EnumValues_EV_Ok, VTime: 1612812462, Vfloat: 1.5},
+		},
+	}
+
+	tests := []struct {
+		desc   string
+		msg    proto.Message
+		fqPath string
+	}{
+		{desc: "scalar", msg: layer0, fqPath: "vint32"},
+		{desc: "enum", msg: layer0, fqPath: "ee"},
+		{desc: "nested enum", msg: layer0, fqPath: "layer1.supported.ev"},
+		{desc: "time", msg: layer0, fqPath: "layer1.supported.v_time"},
+		{desc: "float", msg: layer0, fqPath: "layer1.supported.vfloat"},
+		{desc: "message", msg: layer0, fqPath: "layer1.supported"},
+		{desc: "index", msg: newCustomer(), fqPath: "orders[1].lines[0].sku"},
+		{desc: "list", msg: newCustomer(), fqPath: "tags"},
+		{desc: "timestamp", msg: newWellKnown(), fqPath: "vtimestamp"},
+		{desc: "duration", msg: newWellKnown(), fqPath: "vduration"},
+		{desc: "wrapper", msg: newWellKnown(), fqPath: "vuint32_value"},
+		{desc: "extension", msg: newLegacy(), fqPath: "(r3.details).count"},
+	}
+
+	for _, test := range tests {
+		dyn := toDynamic(t, reg, test.msg)
+
+		want, err := GetField(test.msg, test.fqPath)
+		if err != nil {
+			t.Errorf("TestDynamicParity(%s): GetField on generated message: %s", test.desc, err)
+			continue
+		}
+		got, err := GetField(dyn, test.fqPath)
+		if err != nil {
+			t.Errorf("TestDynamicParity(%s): GetField on dynamic message: %s", test.desc, err)
+			continue
+		}
+		if got.Kind != want.Kind {
+			t.Errorf("TestDynamicParity(%s): GetField got Kind %v, want %v", test.desc, got.Kind, want.Kind)
+		}
+		if wm, ok := want.Value.(proto.Message); ok {
+			if wireBytes(t, got.Value.(proto.Message)) != wireBytes(t, wm) {
+				t.Errorf("TestDynamicParity(%s): GetField got %v, want %v", test.desc, got.Value, want.Value)
+			}
+		} else if diff := pretty.Compare(want.Value, got.Value); diff != "" {
+			t.Errorf("TestDynamicParity(%s): GetField -want/+got:\n%s", test.desc, diff)
+		}
+		if diff := pretty.Compare(want.WellKnown, got.WellKnown); diff != "" {
+			t.Errorf("TestDynamicParity(%s): GetField WellKnown -want/+got:\n%s", test.desc, diff)
+		}
+
+		for _, p := range []bool{false, true} {
+			wantStr, wantKind, wantErr := FieldAsStr(test.msg, test.fqPath, p)
+			gotStr, gotKind, gotErr := FieldAsStr(dyn, test.fqPath, p)
+			if (gotErr == nil) != (wantErr == nil) || gotStr != wantStr || gotKind != wantKind {
+				t.Errorf("TestDynamicParity(%s): FieldAsStr(pretty=%v) got (%q, %v, %v), want (%q, %v, %v)", test.desc, p, gotStr, gotKind, gotErr, wantStr, wantKind, wantErr)
+			}
+		}
+	}
+}
+
+func TestDynamicUpdateParity(t *testing.T) {
+	reg := sampleRegistry(t)
+	defer func(r protoregistry.ExtensionTypeResolver) { ExtensionResolver = r }(ExtensionResolver)
+	ExtensionResolver = reg.Types
+
+	tests := []struct {
+		desc   string
+		msg    func() proto.Message
+		fqPath string
+		value  interface{}
+		opts   []UpdateOption
+		// fromString uses SetFieldFromString() with value, which must be a string.
+		fromString bool
+		code       ErrCode
+	}{
+		{desc: "scalar", msg: func() proto.Message { return &pb.Supported{} }, fqPath: "vstring", value: "hello"},
+		{desc: "enum", msg: func() proto.Message { return &pb.Supported{} }, fqPath: "ev", value: protoreflect.EnumNumber(2)},
+		{desc: "create intermediates", msg: func() proto.Message { return &pb.Layer0{} }, fqPath: "layer1.supported.vint64", value: int64(5), opts: []UpdateOption{CreateIntermediates()}},
+		{desc: "index", msg: func() proto.Message { return newCustomer() }, fqPath: "orders[0].lines[1].price", value: 9.5},
+		{desc: "map", msg: func() proto.Message { return newCustomer() }, fqPath: `labels["env"]`, value: "prod"},
+		{desc: "timestamp", msg: func() proto.Message { return &pb.WellKnown{} }, fqPath: "vtimestamp", value: wkTime},
+		{desc: "wrapper", msg: func() proto.Message { return &pb.WellKnown{} }, fqPath: "vstring_value", value: "wrapped"},
+		{desc: "extension", msg: func() proto.Message { return newLegacy() }, fqPath: "(r3.details).count", value: int32(7)},
+		{desc: "create oneof intermediate", msg: func() proto.Message { return newCustomer() }, fqPath: "payment.card.number", value: "1", fromString: true, opts: []UpdateOption{CreateIntermediates()}},
+		{desc: "error: unset intermediate", msg: func() proto.Message { return &pb.Layer0{} }, fqPath: "layer1.vstring", value: "x", code: ErrIntermdiateNotSet},
+		{desc: "error: unset nested intermediate", msg: func() proto.Message { return &pb.Layer0{Layer1: &pb.Layer1{}} }, fqPath: "layer1.supported.vint32", value: int32(1), code: ErrIntermdiateNotSet},
+		{desc: "error: unset oneof intermediate", msg: func() proto.Message { return newCustomer() }, fqPath: "payment.card.number", value: "1", fromString: true, code: ErrIntermdiateNotSet},
+	}
+
+	for _, test := range tests {
+		want := test.msg()
+		got := toDynamic(t, reg, want)
+
+		update := func(msg proto.Message) error {
+			if test.fromString {
+				return SetFieldFromString(msg, test.fqPath, test.value.(string), test.opts...)
+			}
+			return UpdateProtoField(msg, test.fqPath, test.value, test.opts...)
+		}
+		if !checkCode(t, "TestDynamicUpdateParity", test.desc+" on generated message", update(want), test.code) {
+			continue
+		}
+		if !checkCode(t, "TestDynamicUpdateParity", test.desc+" on dynamic message", update(got), test.code) {
+			continue
+		}
+		if wireBytes(t, got) != wireBytes(t, want) {
+			t.Errorf("TestDynamicUpdateParity(%s): got %v, want %v", test.desc, got, want)
+		}
+	}
+}
+
+func TestDynamicEnumLookup(t *testing.T) {
+	reg := sampleRegistry(t)
+
+	layer0, err := reg.NewMessage("r3.Layer0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bunch, err := reg.NewMessage("r3.BunchOTypes")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantF, wantR := EnumLookup([]proto.Message{&pb.Layer0{}, &pb.BunchOTypes{}})
+	gotF, gotR := EnumLookup([]proto.Message{layer0, bunch})
+	if diff := pretty.Compare(wantF, gotF); diff != "" {
+		t.Errorf("TestDynamicEnumLookup: ForwardLookup -want/+got:\n%s", diff)
+	}
+	if diff := pretty.Compare(wantR, gotR); diff != "" {
+		t.Errorf("TestDynamicEnumLookup: ReverseLookup -want/+got:\n%s", diff)
+	}
+}
@@ -0,0 +1,318 @@
+package prototools
+
+import (
+	"bytes"
+	"math"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+//go:generate stringer -type=ChangeType -trimprefix=Field
+
+// ChangeType is the type of change that happened to a field.
+type ChangeType int8
+
+const (
+	// FieldUnknown means the type wasn't set.
+	FieldUnknown ChangeType = 0
+	// FieldAdded means the field was not set and now is.
+	FieldAdded ChangeType = 1
+	// FieldRemoved means the field was set and now is not.
+	FieldRemoved ChangeType = 2
+	// FieldModified means the field was set and now has a different value.
+	FieldModified ChangeType = 3
+)
+
+// FieldChange details a field that changed in a proto. If the field is a non-message, .From and .To
+// will contain the value.
+type FieldChange struct {
+	// Name is the name of the field. If the change is to an entry in a repeated field or map, this includes
+	// the selector, aka "orders[1]" or `labels["env"]`.
+	Name string
+	// Path is the path to the message holding the field in the proto you diffed. The field will be at:
+	// strings.Join(fc.Path, ".") + "." + fc.Name .
+	Path []string
+	// Type is the type of change.
+	Type ChangeType
+	// Kind is the kind of the value that changed. For a map, this is the kind of the map's values.
+	Kind protoreflect.Kind
+	// From is the old value and To is the new value. Either is nil if the field was not set. The types
+	// are the same as FieldValue.Value for a single value, so a message is a proto.Message and an enum is
+	// a protoreflect.EnumNumber.
+	From, To interface{}
+	// FieldDesc is the descriptor of the field.
+	FieldDesc protoreflect.FieldDescriptor
+}
+
+// FQPath will return the fully qualified path to the value.
+func (f FieldChange) FQPath() string {
+	if len(f.Path) == 0 {
+		return f.Name
+	}
+	return strings.Join(f.Path, ".") + "." + f.Name
+}
+
+type diffOpts struct {
+	// listKeys are paths of repeated message fields, without selectors, to the name of the key field.
+	listKeys map[string]protoreflect.Name
+}
+
+// DiffOption is an option for Diff().
+type DiffOption func(o *diffOpts)
+
+// DiffListByKey has Diff() match the entries of the repeated message field at fqPath by the value of
+// keyField instead of by index. This means an entry that moved is not a change. fqPath does not have selectors,
+// so "orders.lines" refers to the lines in every order. keyField must be a scalar field of the entry's message.
+func DiffListByKey(fqPath, keyField string) DiffOption {
+	return func(o *diffOpts) {
+		if o.listKeys == nil {
+			o.listKeys = map[string]protoreflect.Name{}
+		}
+		o.listKeys[fqPath] = protoreflect.Name(keyField)
+	}
+}
+
+/*
+Diff compares a (older) to b (newer) and returns every field that was added, removed or modified.
+Messages that are set in both are compared field by field, a message set in only one is a single change.
+
+Entries of repeated fields are compared by index, so an entry in b past the end of a is FieldAdded and an
+entry in a past the end of b is FieldRemoved. Use DiffListByKey() to match entries by a key field instead,
+in which case the Name of the change uses the entry's index in a for a removal and in b otherwise.
+Entries of maps are compared by key.
+
+Fields are reported in the order they are declared, map keys in sorted order. a or b can be nil, which is
+the same as an empty message. Unknown fields and extensions are not compared.
+*/
+func Diff(a, b proto.Message, options ...DiffOption) ([]FieldChange, error) {
+	opts := diffOpts{}
+	for _, o := range options {
+		o(&opts)
+	}
+
+	switch {
+	case a == nil && b == nil:
+		return nil, nil
+	case a == nil:
+		a = b.ProtoReflect().Type().New().Interface()
+	case b == nil:
+		b = a.ProtoReflect().Type().New().Interface()
+	}
+	ar, br := a.ProtoReflect(), b.ProtoReflect()
+	if ar.Descriptor().FullName() != br.Descriptor().FullName() {
+		return nil, Errorf(ErrTypeMismatch, "cannot diff a %s against a %s", ar.Descriptor().FullName(), br.Descriptor().FullName())
+	}
+
+	for fqPath, keyField := range opts.listKeys {
+		if err := checkListKey(ar.Descriptor(), fqPath, keyField); err != nil {
+			return nil, err
+		}
+	}
+
+	d := &differ{opts: opts}
+	d.message(nil, nil, ar, br)
+	return d.changes, nil
+}
+
+// checkListKey validates a DiffListByKey() option against md.
+func checkListKey(md protoreflect.MessageDescriptor, fqPath string, keyField protoreflect.Name) error {
+	fields := strings.Split(fqPath, ".")
+	var fd protoreflect.FieldDescriptor
+	for x, field := range fields {
+		path := strings.Join(fields[0:x+1], ".")
+		if md == nil {
+			return Errorf(ErrIntermediateNotMessage, "field(%s) should be a message, was a %s", strings.Join(fields[0:x], "."), fd.Kind())
+		}
+		fd = md.Fields().ByName(protoreflect.Name(field))
+		if fd == nil {
+			return Errorf(ErrBadFieldName, "field(%s) could not be found", path)
+		}
+		md = nil
+		if vd := valueDesc(fd); vd.Kind() == protoreflect.MessageKind {
+			md = vd.Message()
+		}
+	}
+	if !fd.IsList() || md == nil {
+		return Errorf(ErrBadPath, "field(%s) must be a repeated message field to match entries by key", fqPath)
+	}
+	kfd := md.Fields().ByName(keyField)
+	if kfd == nil {
+		return Errorf(ErrBadFieldName, "field(%s) has no key field(%s)", fqPath, keyField)
+	}
+	if kfd.IsList() || kfd.IsMap() || kfd.Kind() == protoreflect.MessageKind || kfd.Kind() == protoreflect.GroupKind {
+		return Errorf(ErrBadPath, "field(%s) key field(%s) must be a scalar", fqPath, keyField)
+	}
+	return nil
+}
+
+// differ walks two messages and records the changes between them.
+type differ struct {
+	opts    diffOpts
+	changes []FieldChange
+}
+
+// message records the changes between a and b. path is the path to the messages, with selectors. names
+// is the same path without selectors, which is how DiffListByKey() options are found.
+func (d *differ) message(path, names []string, a, b protoreflect.Message) {
+	fields := a.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		hasA, hasB := a.Has(fd), b.Has(fd)
+		if !hasA && !hasB {
+			continue
+		}
+
+		name := string(fd.Name())
+		switch {
+		case fd.IsList():
+			d.list(path, append(names, name), fd, a.Get(fd).List(), b.Get(fd).List())
+			continue
+		case fd.IsMap():
+			d.mapField(path, append(names, name), fd, a.Get(fd).Map(), b.Get(fd).Map())
+			continue
+		}
+
+		var va, vb protoreflect.Value
+		if hasA {
+			va = a.Get(fd)
+		}
+		if hasB {
+			vb = b.Get(fd)
+		}
+		d.value(path, append(names, name), name, fd, va, vb)
+	}
+}
+
+// value records the change between two values of fd. An invalid value means it was not set.
+func (d *differ) value(path, names []string, name string, fd protoreflect.FieldDescriptor, a, b protoreflect.Value) {
+	switch {
+	case a.IsValid() && !b.IsValid():
+		d.add(path, name, FieldRemoved, fd, a, b)
+	case !a.IsValid() && b.IsValid():
+		d.add(path, name, FieldAdded, fd, a, b)
+	case valueDesc(fd).Kind() == protoreflect.MessageKind || valueDesc(fd).Kind() == protoreflect.GroupKind:
+		d.message(append(path, name), names, a.Message(), b.Message())
+	case !equalScalar(a, b):
+		d.add(path, name, FieldModified, fd, a, b)
+	}
+}
+
+// list records the changes between two lists of fd.
+func (d *differ) list(path, names []string, fd protoreflect.FieldDescriptor, a, b protoreflect.List) {
+	if key, ok := d.opts.listKeys[strings.Join(names, ".")]; ok {
+		d.keyedList(path, names, fd, key, a, b)
+		return
+	}
+
+	for i := 0; i < a.Len() || i < b.Len(); i++ {
+		var va, vb protoreflect.Value
+		if i < a.Len() {
+			va = a.Get(i)
+		}
+		if i < b.Len() {
+			vb = b.Get(i)
+		}
+		d.value(path, names, indexName(fd, i), fd, va, vb)
+	}
+}
+
+// keyedList is like list, but matches entries by the value of the field named key.
+func (d *differ) keyedList(path, names []string, fd protoreflect.FieldDescriptor, key protoreflect.Name, a, b protoreflect.List) {
+	kfd := fd.Message().Fields().ByName(key)
+
+	// byKey holds the indexes in a for each key. Duplicate keys are matched in order.
+	byKey := map[interface{}][]int{}
+	for i := 0; i < a.Len(); i++ {
+		k := listKey(a.Get(i).Message().Get(kfd))
+		byKey[k] = append(byKey[k], i)
+	}
+
+	matched := make([]int, b.Len())
+	inB := make([]bool, a.Len())
+	for j := 0; j < b.Len(); j++ {
+		k := listKey(b.Get(j).Message().Get(kfd))
+		matched[j] = -1
+		if idx := byKey[k]; len(idx) > 0 {
+			matched[j] = idx[0]
+			inB[idx[0]] = true
+			byKey[k] = idx[1:]
+		}
+	}
+
+	for i := 0; i < a.Len(); i++ {
+		if !inB[i] {
+			d.value(path, names, indexName(fd, i), fd, a.Get(i), protoreflect.Value{})
+		}
+	}
+	for j := 0; j < b.Len(); j++ {
+		var va protoreflect.Value
+		if matched[j] != -1 {
+			va = a.Get(matched[j])
+		}
+		d.value(path, names, indexName(fd, j), fd, va, b.Get(j))
+	}
+}
+
+// mapField records the changes between two maps of fd.
+func (d *differ) mapField(path, names []string, fd protoreflect.FieldDescriptor, a, b protoreflect.Map) {
+	keys := sortedMapKeys(a)
+	b.Range(func(k protoreflect.MapKey, _ protoreflect.Value) bool {
+		if !a.Has(k) {
+			keys = append(keys, k)
+		}
+		return true
+	})
+	sortMapKeys(keys)
+
+	for _, k := range keys {
+		name := string(fd.Name()) + "[" + keySelector(k) + "]"
+		d.value(path, names, name, fd, a.Get(k), b.Get(k))
+	}
+}
+
+// add adds a change. An invalid value means it was not set.
+func (d *differ) add(path []string, name string, t ChangeType, fd protoreflect.FieldDescriptor, a, b protoreflect.Value) {
+	fc := FieldChange{
+		Name:      name,
+		Path:      append([]string(nil), path...),
+		Type:      t,
+		Kind:      valueDesc(fd).Kind(),
+		FieldDesc: fd,
+	}
+	if a.IsValid() {
+		fc.From = elemValue(fd, a).Value
+	}
+	if b.IsValid() {
+		fc.To = elemValue(fd, b).Value
+	}
+	d.changes = append(d.changes, fc)
+}
+
+// indexName is the name of entry i of the repeated field fd.
+func indexName(fd protoreflect.FieldDescriptor, i int) string {
+	return string(fd.Name()) + "[" + strconv.Itoa(i) + "]"
+}
+
+// listKey converts the value of a key field into something that can be a Go map key.
+func listKey(v protoreflect.Value) interface{} {
+	if b, ok := v.Interface().([]byte); ok {
+		return string(b)
+	}
+	return v.Interface()
+}
+
+// equalScalar returns true if a and b, which are not messages, hold the same value. Unlike ==, NaN
+// is equal to NaN.
+func equalScalar(a, b protoreflect.Value) bool {
+	switch va := a.Interface().(type) {
+	case []byte:
+		return bytes.Equal(va, b.Bytes())
+	case float32, float64:
+		fa, fb := a.Float(), b.Float()
+		return fa == fb || math.IsNaN(fa) && math.IsNaN(fb)
+	}
+	return a.Interface() == b.Interface()
+}
@@ -0,0 +1,174 @@
+package prototools
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	pb "github.com/johnsiilver/prototools/sample"
+)
+
+// change is a FieldChange without the descriptor, which makes tests easier to read.
+type change struct {
+	FQPath   string
+	Type     ChangeType
+	Kind     protoreflect.Kind
+	From, To interface{}
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		desc    string
+		a, b    proto.Message
+		options []DiffOption
+		want    []change
+		err     bool
+	}{
+		{
+			desc: "equal",
+			a:    newCustomer(),
+			b:    newCustomer(),
+		},
+		{
+			desc: "scalars and nested messages",
+			a: &pb.Layer0{
+				Vint32: 1,
+				Layer1: &pb.Layer1{Vstring: "old", Supported: &pb.Supported{Vbool: true}},
+			},
+			b: &pb.Layer0{
+				Ee:     pb.Layer0_EE_WHATEVER,
+				Layer1: &pb.Layer1{Vstring: "new", Supported: &pb.Supported{Vbool: true}},
+			},
+			want: []change{
+				{FQPath: "layer1.vstring", Type: FieldModified, Kind: protoreflect.StringKind, From: "old", To: "new"},
+				{FQPath: "vint32", Type: FieldRemoved, Kind: protoreflect.Int32Kind, From: int32(1)},
+				{FQPath: "ee", Type: FieldAdded, Kind: protoreflect.EnumKind, To: protoreflect.EnumNumber(1)},
+			},
+		},
+		{
+			desc: "message set on one side",
+			a:    &pb.Layer0{},
+			b:    &pb.Layer0{Layer1: &pb.Layer1{Vstring: "new"}},
+			want: []change{
+				{FQPath: "layer1", Type: FieldAdded, Kind: protoreflect.MessageKind, To: &pb.Layer1{Vstring: "new"}},
+			},
+		},
+		{
+			desc: "nil is an empty message",
+			a:    nil,
+			b:    &pb.Layer0{Vint32: 2},
+			want: []change{
+				{FQPath: "vint32", Type: FieldAdded, Kind: protoreflect.Int32Kind, To: int32(2)},
+			},
+		},
+		{
+			desc: "lists by index and maps by key",
+			a: &pb.Customer{
+				Tags:   []string{"a", "b", "c"},
+				Orders: []*pb.Order{{Id: "1"}, {Id: "2"}},
+				Labels: map[string]string{"env": "dev", "gone": "x"},
+			},
+			b: &pb.Customer{
+				Tags:   []string{"a", "z"},
+				Orders: []*pb.Order{{Id: "2"}, {Id: "1"}, {Id: "3"}},
+				Labels: map[string]string{"env": "prod", "new": "y"},
+			},
+			want: []change{
+				{FQPath: "orders[0].id", Type: FieldModified, Kind: protoreflect.StringKind, From: "1", To: "2"},
+				{FQPath: "orders[1].id", Type: FieldModified, Kind: protoreflect.StringKind, From: "2", To: "1"},
+				{FQPath: "orders[2]", Type: FieldAdded, Kind: protoreflect.MessageKind, To: &pb.Order{Id: "3"}},
+				{FQPath: "tags[1]", Type: FieldModified, Kind: protoreflect.StringKind, From: "b", To: "z"},
+				{FQPath: "tags[2]", Type: FieldRemoved, Kind: protoreflect.StringKind, From: "c"},
+				{FQPath: `labels["env"]`, Type: FieldModified, Kind: protoreflect.StringKind, From: "dev", To: "prod"},
+				{FQPath: `labels["gone"]`, Type: FieldRemoved, Kind: protoreflect.StringKind, From: "x"},
+				{FQPath: `labels["new"]`, Type: FieldAdded, Kind: protoreflect.StringKind, To: "y"},
+			},
+		},
+		{
+			desc: "lists by key",
+			a: &pb.Customer{
+				Orders: []*pb.Order{
+					{Id: "1", Lines: []*pb.Line{{Sku: "a", Quantity: 1}, {Sku: "b", Quantity: 1}}},
+					{Id: "2"},
+				},
+			},
+			b: &pb.Customer{
+				Orders: []*pb.Order{
+					{Id: "3"},
+					{Id: "1", Lines: []*pb.Line{{Sku: "b", Quantity: 2}, {Sku: "a", Quantity: 1}}},
+				},
+			},
+			options: []DiffOption{DiffListByKey("orders", "id"), DiffListByKey("orders.lines", "sku")},
+			want: []change{
+				{FQPath: "orders[1]", Type: FieldRemoved, Kind: protoreflect.MessageKind, From: &pb.Order{Id: "2"}},
+				{FQPath: "orders[0]", Type: FieldAdded, Kind: protoreflect.MessageKind, To: &pb.Order{Id: "3"}},
+				{FQPath: "orders[1].lines[0].quantity", Type: FieldModified, Kind: protoreflect.Int32Kind, From: int32(1), To: int32(2)},
+			},
+		},
+		{
+			desc: "message maps",
+			a:    &pb.Customer{ById: map[int64]*pb.Order{1: {Id: "a"}}},
+			b:    &pb.Customer{ById: map[int64]*pb.Order{1: {Id: "b"}}},
+			want: []change{
+				{FQPath: "by_id[1].id", Type: FieldModified, Kind: protoreflect.StringKind, From: "a", To: "b"},
+			},
+		},
+		{
+			desc: "oneof member changed",
+			a:    &pb.Payment{Method: &pb.Payment_Voucher{Voucher: "v"}},
+			b:    &pb.Payment{Method: &pb.Payment_Credits{Credits: 5}},
+			want: []change{
+				{FQPath: "voucher", Type: FieldRemoved, Kind: protoreflect.StringKind, From: "v"},
+				{FQPath: "credits", Type: FieldAdded, Kind: protoreflect.Int64Kind, To: int64(5)},
+			},
+		},
+		{
+			desc: "error: different types",
+			a:    &pb.Layer0{},
+			b:    &pb.Layer1{},
+			err:  true,
+		},
+		{
+			desc:    "error: key on a non-repeated field",
+			a:       &pb.Customer{},
+			b:       &pb.Customer{},
+			options: []DiffOption{DiffListByKey("payment", "id")},
+			err:     true,
+		},
+		{
+			desc:    "error: key field does not exist",
+			a:       &pb.Customer{},
+			b:       &pb.Customer{},
+			options: []DiffOption{DiffListByKey("orders.lines", "nope")},
+			err:     true,
+		},
+	}
+
+	for _, test := range tests {
+		changes, err := Diff(test.a, test.b, test.options...)
+		switch {
+		case err == nil && test.err:
+			t.Errorf("TestDiff(%s): got err == nil, want err != nil", test.desc)
+			continue
+		case err != nil && !test.err:
+			t.Errorf("TestDiff(%s): got err == %s, want err == nil", test.desc, err)
+			continue
+		case err != nil:
+			continue
+		}
+
+		var got []change
+		for _, c := range changes {
+			if c.FieldDesc == nil {
+				t.Errorf("TestDiff(%s): change to %s has no FieldDesc", test.desc, c.FQPath())
+			}
+			got = append(got, change{FQPath: c.FQPath(), Type: c.Type, Kind: c.Kind, From: c.From, To: c.To})
+		}
+		if diff := cmp.Diff(test.want, got, protocmp.Transform()); diff != "" {
+			t.Errorf("TestDiff(%s): -want/+got:\n%s", test.desc, diff)
+		}
+	}
+}
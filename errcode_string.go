@@ -11,15 +11,27 @@ func _() {
 	_ = x[ErrUnknown-0]
 	_ = x[ErrIntermediateNotMessage-1]
 	_ = x[ErrIntermdiateNotSet-2]
+	_ = x[ErrBadFieldName-3]
+	_ = x[ErrNotMessage-4]
+	_ = x[ErrIndexOutOfRange-5]
+	_ = x[ErrBadPath-6]
+	_ = x[ErrKeyNotFound-7]
+	_ = x[ErrTypeMismatch-8]
+	_ = x[ErrValueOutOfRange-9]
+	_ = x[ErrParse-10]
+	_ = x[ErrPatchTestFailed-11]
+	_ = x[ErrAnyType-12]
+	_ = x[ErrTypeNotFound-13]
 }
 
-const _ErrCode_name = "ErrUnknownErrIntermediateNotMessageErrIntermdiateNotSet"
+const _ErrCode_name = "ErrUnknownErrIntermediateNotMessageErrIntermdiateNotSetErrBadFieldNameErrNotMessageErrIndexOutOfRangeErrBadPathErrKeyNotFoundErrTypeMismatchErrValueOutOfRangeErrParseErrPatchTestFailedErrAnyTypeErrTypeNotFound"
 
-var _ErrCode_index = [...]uint8{0, 10, 35, 55}
+var _ErrCode_index = [...]uint8{0, 10, 35, 55, 70, 83, 101, 111, 125, 140, 158, 166, 184, 194, 209}
 
 func (i ErrCode) String() string {
-	if i < 0 || i >= ErrCode(len(_ErrCode_index)-1) {
+	idx := int(i) - 0
+	if i < 0 || idx >= len(_ErrCode_index)-1 {
 		return "ErrCode(" + strconv.FormatInt(int64(i), 10) + ")"
 	}
-	return _ErrCode_name[_ErrCode_index[i]:_ErrCode_index[i+1]]
+	return _ErrCode_name[_ErrCode_index[idx]:_ErrCode_index[idx+1]]
 }
@@ -0,0 +1,37 @@
+package prototools
+
+import (
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// ExtensionResolver finds the extension fields named in paths, aka "(my.pkg.ext_field)". This defaults to
+// protoregistry.GlobalTypes. Replace it before using the package if your extensions are registered somewhere
+// else, it is not safe to change while other goroutines use the package.
+var ExtensionResolver protoregistry.ExtensionTypeResolver = protoregistry.GlobalTypes
+
+// isExtensionName returns true if name is an extension in a path, which is its full name in parentheses.
+func isExtensionName(name string) bool {
+	return len(name) > 2 && strings.HasPrefix(name, "(") && strings.HasSuffix(name, ")")
+}
+
+// partField returns the field called name in md, where name is from a path part. name is either the proto name
+// of a field or the full name of an extension of md in parentheses, aka "(my.pkg.ext_field)". It returns nil
+// if there is no such field or the extension does not extend md.
+func partField(md protoreflect.MessageDescriptor, name string) protoreflect.FieldDescriptor {
+	if !isExtensionName(name) {
+		return md.Fields().ByName(protoreflect.Name(name))
+	}
+
+	xt, err := ExtensionResolver.FindExtensionByName(protoreflect.FullName(name[1 : len(name)-1]))
+	if err != nil {
+		return nil
+	}
+	xd := xt.TypeDescriptor()
+	if xd.ContainingMessage().FullName() != md.FullName() {
+		return nil
+	}
+	return xd
+}
@@ -0,0 +1,162 @@
+package prototools
+
+import (
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoregistry"
+
+	pb "github.com/johnsiilver/prototools/sample"
+)
+
+func newLegacy() *pb.Legacy {
+	msg := &pb.Legacy{Name: proto.String("root")}
+	child := &pb.Legacy{}
+	proto.SetExtension(child, pb.E_Nickname, "kid")
+	proto.SetExtension(msg, pb.E_Nickname, "bob")
+	proto.SetExtension(msg, pb.E_Details, &pb.LegacyDetails{Count: proto.Int32(3), Child: child})
+	proto.SetExtension(msg, pb.E_Scores, []int32{1, 2, 3})
+	proto.SetExtension(msg, pb.E_LegacyHolder_Flag, true)
+	return msg
+}
+
+func TestGetFieldExtension(t *testing.T) {
+	tests := []struct {
+		desc   string
+		msg    proto.Message
+		fqPath string
+		want   interface{}
+		code   ErrCode
+	}{
+		{desc: "scalar extension", msg: newLegacy(), fqPath: "(r3.nickname)", want: "bob"},
+		{desc: "field in a message extension", msg: newLegacy(), fqPath: "(r3.details).count", want: int32(3)},
+		{desc: "extension of a nested message", msg: newLegacy(), fqPath: "(r3.details).child.(r3.nickname)", want: "kid"},
+		{desc: "repeated extension with a selector", msg: newLegacy(), fqPath: "(r3.scores)[-1]", want: int32(3)},
+		{desc: "repeated extension", msg: newLegacy(), fqPath: "(r3.scores)", want: []int32{1, 2, 3}},
+		{desc: "extension declared in a message", msg: newLegacy(), fqPath: "(r3.LegacyHolder.flag)", want: true},
+		{desc: "extension not set", msg: &pb.Legacy{}, fqPath: "(r3.nickname)", want: ""},
+		{desc: "error: not an extension of the message", msg: &pb.Layer0{}, fqPath: "(r3.nickname)", code: ErrBadFieldName},
+		{desc: "error: extension does not exist", msg: newLegacy(), fqPath: "(r3.nope)", code: ErrBadFieldName},
+	}
+
+	for _, test := range tests {
+		fv, err := GetField(test.msg, test.fqPath)
+		if !checkCode(t, "TestGetFieldExtension", test.desc, err, test.code) {
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if diff := pretty.Compare(test.want, fv.Value); diff != "" {
+			t.Errorf("TestGetFieldExtension(%s): -want/+got:\n%s", test.desc, diff)
+		}
+	}
+}
+
+func TestUpdateProtoFieldExtension(t *testing.T) {
+	tests := []struct {
+		desc    string
+		fqPath  string
+		value   interface{}
+		options []UpdateOption
+		want    func() *pb.Legacy
+		code    ErrCode
+	}{
+		{
+			desc:   "scalar extension",
+			fqPath: "(r3.nickname)",
+			value:  "robert",
+			want: func() *pb.Legacy {
+				m := newLegacy()
+				proto.SetExtension(m, pb.E_Nickname, "robert")
+				return m
+			},
+		},
+		{
+			desc:   "field in a message extension",
+			fqPath: "(r3.details).count",
+			value:  10,
+			want: func() *pb.Legacy {
+				m := newLegacy()
+				proto.GetExtension(m, pb.E_Details).(*pb.LegacyDetails).Count = proto.Int32(10)
+				return m
+			},
+		},
+		{
+			desc:   "repeated extension entry",
+			fqPath: "(r3.scores)[0]",
+			value:  int32(9),
+			want: func() *pb.Legacy {
+				m := newLegacy()
+				proto.SetExtension(m, pb.E_Scores, []int32{9, 2, 3})
+				return m
+			},
+		},
+		{
+			desc:    "create intermediate extensions",
+			fqPath:  "(r3.details).child.(r3.details).count",
+			value:   1,
+			options: []UpdateOption{CreateIntermediates()},
+			want: func() *pb.Legacy {
+				m := newLegacy()
+				child := proto.GetExtension(m, pb.E_Details).(*pb.LegacyDetails).Child
+				proto.SetExtension(child, pb.E_Details, &pb.LegacyDetails{Count: proto.Int32(1)})
+				return m
+			},
+		},
+		{
+			desc:   "error: wrong type",
+			fqPath: "(r3.LegacyHolder.flag)",
+			value:  "true",
+			code:   ErrTypeMismatch,
+		},
+	}
+
+	for _, test := range tests {
+		msg := newLegacy()
+		err := UpdateProtoField(msg, test.fqPath, test.value, test.options...)
+		if !checkCode(t, "TestUpdateProtoFieldExtension", test.desc, err, test.code) {
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if diff := Equal(test.want(), msg); diff != "" {
+			t.Errorf("TestUpdateProtoFieldExtension(%s): -want/+got:\n%s", test.desc, diff)
+		}
+	}
+}
+
+func TestExtensionLists(t *testing.T) {
+	msg := newLegacy()
+	if err := AppendField(msg, "(r3.scores)", 4); err != nil {
+		t.Fatalf("TestExtensionLists: AppendField() got err == %s, want err == nil", err)
+	}
+	if err := ClearField(msg, "(r3.nickname)"); err != nil {
+		t.Fatalf("TestExtensionLists: ClearField() got err == %s, want err == nil", err)
+	}
+
+	if got := proto.GetExtension(msg, pb.E_Scores).([]int32); len(got) != 4 || got[3] != 4 {
+		t.Errorf("TestExtensionLists: got scores %v, want [1 2 3 4]", got)
+	}
+	if proto.HasExtension(msg, pb.E_Nickname) {
+		t.Errorf("TestExtensionLists: nickname was not cleared")
+	}
+}
+
+func TestExtensionResolver(t *testing.T) {
+	defer func(r protoregistry.ExtensionTypeResolver) { ExtensionResolver = r }(ExtensionResolver)
+
+	types := &protoregistry.Types{}
+	if err := types.RegisterExtension(pb.E_Nickname); err != nil {
+		t.Fatal(err)
+	}
+	ExtensionResolver = types
+
+	if _, err := GetField(newLegacy(), "(r3.nickname)"); err != nil {
+		t.Errorf("TestExtensionResolver: got err == %s, want err == nil", err)
+	}
+	_, err := GetField(newLegacy(), "(r3.details).count")
+	checkCode(t, "TestExtensionResolver", "not registered", err, ErrBadFieldName)
+}
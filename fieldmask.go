@@ -0,0 +1,238 @@
+package prototools
+
+import (
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// FullMask is the field mask path that means every field, as defined in AIP-134.
+const FullMask = "*"
+
+/*
+FieldMaskFromDiff returns a FieldMask with the paths of the fields that are different between oldMsg and
+newMsg. Applying the mask with ApplyFieldMask(oldMsg, newMsg, mask) makes oldMsg equal to newMsg.
+
+Paths use the same fqPath convention as GetField(). A repeated field is always replaced as a whole, so a change
+to "orders[1].id" is the path "orders". A map entry is replaced as a whole, so a change to `by_id[1].id` is
+the path `by_id[1]`. Either message can be nil, which is the same as an empty message.
+*/
+func FieldMaskFromDiff(oldMsg, newMsg proto.Message) (*fieldmaskpb.FieldMask, error) {
+	changes, err := Diff(oldMsg, newMsg)
+	if err != nil {
+		return nil, err
+	}
+
+	mask := &fieldmaskpb.FieldMask{}
+	if len(changes) == 0 {
+		return mask, nil
+	}
+
+	var md protoreflect.MessageDescriptor
+	if newMsg != nil {
+		md = newMsg.ProtoReflect().Descriptor()
+	} else {
+		md = oldMsg.ProtoReflect().Descriptor()
+	}
+
+	seen := map[string]bool{}
+	for _, c := range changes {
+		p := maskPathFor(md, c.FQPath())
+		if seen[p] {
+			continue
+		}
+		seen[p] = true
+		mask.Paths = append(mask.Paths, p)
+	}
+	return mask, nil
+}
+
+// maskPathFor returns the field mask path for fqPath in the message described by md. This is fqPath
+// truncated at the first repeated field or map entry.
+func maskPathFor(md protoreflect.MessageDescriptor, fqPath string) string {
+	fields := FQPathSplit(fqPath)
+	for x, field := range fields {
+		pp, _ := parsePart(field)
+		fd := partField(md, pp.name)
+		if fd == nil {
+			return fqPath
+		}
+		if pp.hasSelector {
+			if fd.IsList() {
+				fields[x] = pp.name
+			}
+			return strings.Join(fields[:x+1], ".")
+		}
+		if x < len(fields)-1 {
+			md = fd.Message()
+		}
+	}
+	return fqPath
+}
+
+/*
+ApplyFieldMask copies the fields in mask from src to dst, following AIP-134 update_mask semantics. src and dst
+must be the same type of message.
+
+Paths use the same fqPath convention as GetField(). Every field in a path except the last must be a message that
+is not repeated. The last field can be anything. A repeated field or map is replaced as a whole. The last field
+can select a single map entry with a key, aka `labels["env"]`.
+
+A field that is not set in src is cleared in dst, so a mask is also how fields are removed. Intermediate
+messages in dst are created as needed. A path of FullMask("*") replaces all of dst with src. A nil or empty mask
+is the same as a mask of every field that is set in src.
+
+Every path is checked before dst is changed, so if this returns an error dst is unchanged.
+*/
+func ApplyFieldMask(dst, src proto.Message, mask *fieldmaskpb.FieldMask) error {
+	dr, sr := dst.ProtoReflect(), src.ProtoReflect()
+	if dr.Descriptor().FullName() != sr.Descriptor().FullName() {
+		return Errorf(ErrTypeMismatch, "cannot apply a field mask from a %s to a %s", sr.Descriptor().FullName(), dr.Descriptor().FullName())
+	}
+
+	paths := mask.GetPaths()
+	if len(paths) == 0 {
+		m, err := FieldMaskFromDiff(nil, src)
+		if err != nil {
+			return err
+		}
+		paths = m.Paths
+	}
+
+	split := make([][]string, 0, len(paths))
+	for _, p := range paths {
+		if p == FullMask {
+			if len(paths) != 1 {
+				return Errorf(ErrBadPath, "field mask path(%s) must be the only path", FullMask)
+			}
+			proto.Reset(dst)
+			proto.Merge(dst, src)
+			return nil
+		}
+		fields, err := maskPath(dr.Descriptor(), p)
+		if err != nil {
+			return err
+		}
+		split = append(split, fields)
+	}
+
+	// Values from src are set directly on dst, so we use a copy that nothing else can change.
+	src = proto.Clone(src)
+	for _, fields := range split {
+		if err := applyPath(dst, src, fields); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+/*
+FilterByFieldMask clears every field in msg that is not in mask. This is how a read mask(AIP-157) is applied.
+Paths follow the same rules as ApplyFieldMask(). A nil or empty mask or FullMask("*") leaves msg unchanged.
+*/
+func FilterByFieldMask(msg proto.Message, mask *fieldmaskpb.FieldMask) error {
+	filtered := msg.ProtoReflect().New().Interface()
+	if err := ApplyFieldMask(filtered, msg, mask); err != nil {
+		return err
+	}
+	proto.Reset(msg)
+	proto.Merge(msg, filtered)
+	return nil
+}
+
+// maskPath validates fqPath from a field mask against md and returns the fields in it.
+func maskPath(md protoreflect.MessageDescriptor, fqPath string) ([]string, error) {
+	fields := FQPathSplit(fqPath)
+	fd, err := checkPath(md, fields)
+	if err != nil {
+		return nil, err
+	}
+	for x, field := range fields {
+		pp, _ := parsePart(field)
+		if !pp.hasSelector {
+			continue
+		}
+		if x != len(fields)-1 || !fd.IsMap() {
+			return nil, Errorf(ErrBadPath, "field mask path(%s) can only have a selector on the last field and it must be a map key", fqPath)
+		}
+		if _, err := pp.mapKey(fd); err != nil {
+			return nil, err
+		}
+	}
+	return fields, nil
+}
+
+// applyPath copies the field at fields from src to dst. If it is not set in src, it is cleared in dst.
+func applyPath(dst, src proto.Message, fields []string) error {
+	pp, _ := parsePart(fields[len(fields)-1])
+
+	val, ok, err := maskValue(src, fields, pp)
+	if err != nil {
+		return err
+	}
+
+	dm, err := getLastMessage(dst, fields, ok, nil)
+	if err != nil {
+		if e, isErr := err.(Error); !ok && isErr && e.Code == ErrIntermdiateNotSet {
+			// An intermediate message is not set in dst, so there is nothing to clear.
+			return nil
+		}
+		return err
+	}
+	fd := partField(dm.Descriptor(), pp.name)
+	if fd == nil {
+		return Errorf(ErrBadFieldName, "field(%s) could not be found", strings.Join(fields, "."))
+	}
+
+	if !pp.hasSelector {
+		if ok {
+			dm.Set(fd, val)
+		} else {
+			dm.Clear(fd)
+		}
+		return nil
+	}
+
+	k, err := pp.mapKey(fd)
+	if err != nil {
+		return err
+	}
+	switch {
+	case ok:
+		dm.Mutable(fd).Map().Set(k, val)
+	case dm.Has(fd):
+		dm.Mutable(fd).Map().Clear(k)
+	}
+	return nil
+}
+
+// maskValue returns the value in src for the path in fields, whose last part is pp. ok is false if it is not set.
+func maskValue(src proto.Message, fields []string, pp pathPart) (val protoreflect.Value, ok bool, err error) {
+	sm, err := getLastMessage(src, fields, false, nil)
+	if err != nil {
+		if e, isErr := err.(Error); isErr && e.Code == ErrIntermdiateNotSet {
+			return protoreflect.Value{}, false, nil
+		}
+		return protoreflect.Value{}, false, err
+	}
+
+	fd := partField(sm.Descriptor(), pp.name)
+	if fd == nil {
+		return protoreflect.Value{}, false, Errorf(ErrBadFieldName, "field(%s) could not be found", strings.Join(fields, "."))
+	}
+	if !sm.Has(fd) {
+		return protoreflect.Value{}, false, nil
+	}
+	if !pp.hasSelector {
+		return sm.Get(fd), true, nil
+	}
+
+	k, err := pp.mapKey(fd)
+	if err != nil {
+		return protoreflect.Value{}, false, err
+	}
+	val = sm.Get(fd).Map().Get(k)
+	return val, val.IsValid(), nil
+}
@@ -0,0 +1,247 @@
+package prototools
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+
+	pb "github.com/johnsiilver/prototools/sample"
+)
+
+func TestFieldMaskFromDiff(t *testing.T) {
+	oldMsg := &pb.Customer{
+		Name:    "old",
+		Orders:  []*pb.Order{{Id: "1"}},
+		Labels:  map[string]string{"env": "dev"},
+		ById:    map[int64]*pb.Order{1: {Id: "a"}},
+		Payment: &pb.Payment{Method: &pb.Payment_Voucher{Voucher: "v"}},
+	}
+	newMsg := &pb.Customer{
+		Name:    "new",
+		Orders:  []*pb.Order{{Id: "2"}, {Id: "3"}},
+		Labels:  map[string]string{"env": "dev", "new": "x"},
+		ById:    map[int64]*pb.Order{1: {Id: "b"}},
+		Payment: &pb.Payment{Id: "p", Method: &pb.Payment_Voucher{Voucher: "v"}},
+	}
+
+	mask, err := FieldMaskFromDiff(oldMsg, newMsg)
+	if err != nil {
+		t.Fatalf("TestFieldMaskFromDiff: got err == %s, want err == nil", err)
+	}
+	want := []string{"name", "orders", `labels["new"]`, "by_id[1]", "payment.id"}
+	if diff := cmp.Diff(want, mask.Paths); diff != "" {
+		t.Errorf("TestFieldMaskFromDiff: -want/+got:\n%s", diff)
+	}
+
+	got := proto.Clone(oldMsg)
+	if err := ApplyFieldMask(got, newMsg, mask); err != nil {
+		t.Fatalf("TestFieldMaskFromDiff: ApplyFieldMask() got err == %s, want err == nil", err)
+	}
+	if diff := cmp.Diff(newMsg, got, protocmp.Transform()); diff != "" {
+		t.Errorf("TestFieldMaskFromDiff: ApplyFieldMask() did not make old equal new: -want/+got:\n%s", diff)
+	}
+}
+
+func TestApplyFieldMask(t *testing.T) {
+	src := &pb.Layer0{
+		Vint32: 5,
+		Layer1: &pb.Layer1{Vstring: "src", Supported: &pb.Supported{Vint64: 64}},
+	}
+
+	// legacyCopied is an empty Legacy after the nickname and the count of the details are copied from newLegacy().
+	legacyCopied := &pb.Legacy{}
+	proto.SetExtension(legacyCopied, pb.E_Nickname, "bob")
+	proto.SetExtension(legacyCopied, pb.E_Details, &pb.LegacyDetails{Count: proto.Int32(3)})
+	// legacyCleared is newLegacy() without the nickname and the scores.
+	legacyCleared := newLegacy()
+	proto.ClearExtension(legacyCleared, pb.E_Nickname)
+	proto.ClearExtension(legacyCleared, pb.E_Scores)
+
+	tests := []struct {
+		desc  string
+		dst   proto.Message
+		src   proto.Message
+		paths []string
+		want  proto.Message
+		err   bool
+	}{
+		{
+			desc:  "nested field creates intermediates",
+			dst:   &pb.Layer0{Vint32: 1},
+			src:   src,
+			paths: []string{"layer1.supported.vint64"},
+			want:  &pb.Layer0{Vint32: 1, Layer1: &pb.Layer1{Supported: &pb.Supported{Vint64: 64}}},
+		},
+		{
+			desc:  "unset in src clears dst",
+			dst:   &pb.Layer0{Ee: pb.Layer0_EE_WHATEVER, Layer1: &pb.Layer1{Vstring: "dst"}},
+			src:   &pb.Layer0{},
+			paths: []string{"ee", "layer1.vstring", "layer1.supported.vint64"},
+			want:  &pb.Layer0{Layer1: &pb.Layer1{}},
+		},
+		{
+			desc:  "message is replaced",
+			dst:   &pb.Layer0{Layer1: &pb.Layer1{Vstring: "dst"}},
+			src:   src,
+			paths: []string{"layer1"},
+			want:  &pb.Layer0{Layer1: &pb.Layer1{Vstring: "src", Supported: &pb.Supported{Vint64: 64}}},
+		},
+		{
+			desc:  "map key",
+			dst:   &pb.Customer{Labels: map[string]string{"a": "1", "b": "2"}},
+			src:   &pb.Customer{Labels: map[string]string{"a": "new", "c": "3"}},
+			paths: []string{`labels["a"]`, `labels["b"]`},
+			want:  &pb.Customer{Labels: map[string]string{"a": "new"}},
+		},
+		{
+			desc:  "extensions",
+			dst:   &pb.Legacy{},
+			src:   newLegacy(),
+			paths: []string{"(r3.nickname)", "(r3.details).count"},
+			want:  legacyCopied,
+		},
+		{
+			desc:  "extensions unset in src clear dst",
+			dst:   newLegacy(),
+			src:   &pb.Legacy{},
+			paths: []string{"(r3.nickname)", "(r3.scores)"},
+			want:  legacyCleared,
+		},
+		{
+			desc:  "full mask",
+			dst:   &pb.Layer0{Ee: pb.Layer0_EE_WHATEVER},
+			src:   src,
+			paths: []string{FullMask},
+			want:  src,
+		},
+		{
+			desc: "empty mask is the populated fields",
+			dst:  &pb.Layer0{Ee: pb.Layer0_EE_WHATEVER, Layer1: &pb.Layer1{Vstring: "dst"}},
+			src:  src,
+			want: &pb.Layer0{
+				Vint32: 5,
+				Ee:     pb.Layer0_EE_WHATEVER,
+				Layer1: &pb.Layer1{Vstring: "src", Supported: &pb.Supported{Vint64: 64}},
+			},
+		},
+		{
+			desc:  "error: bad field",
+			dst:   &pb.Layer0{},
+			src:   src,
+			paths: []string{"vint32", "nope"},
+			err:   true,
+		},
+		{
+			desc:  "error: index selector",
+			dst:   &pb.Customer{},
+			src:   &pb.Customer{},
+			paths: []string{"orders[0].id"},
+			err:   true,
+		},
+		{
+			desc:  "error: full mask with other paths",
+			dst:   &pb.Layer0{},
+			src:   src,
+			paths: []string{FullMask, "vint32"},
+			err:   true,
+		},
+		{
+			desc:  "error: different types",
+			dst:   &pb.Layer1{},
+			src:   src,
+			paths: []string{"vstring"},
+			err:   true,
+		},
+	}
+
+	for _, test := range tests {
+		var mask *fieldmaskpb.FieldMask
+		if test.paths != nil {
+			mask = &fieldmaskpb.FieldMask{Paths: test.paths}
+		}
+		before := proto.Clone(test.dst)
+		err := ApplyFieldMask(test.dst, test.src, mask)
+		switch {
+		case err == nil && test.err:
+			t.Errorf("TestApplyFieldMask(%s): got err == nil, want err != nil", test.desc)
+			continue
+		case err != nil && !test.err:
+			t.Errorf("TestApplyFieldMask(%s): got err == %s, want err == nil", test.desc, err)
+			continue
+		case err != nil:
+			if !proto.Equal(before, test.dst) {
+				t.Errorf("TestApplyFieldMask(%s): dst was changed on error", test.desc)
+			}
+			continue
+		}
+		if diff := cmp.Diff(test.want, test.dst, protocmp.Transform()); diff != "" {
+			t.Errorf("TestApplyFieldMask(%s): -want/+got:\n%s", test.desc, diff)
+		}
+	}
+
+	// src must not share memory with dst.
+	dst := &pb.Layer0{}
+	if err := ApplyFieldMask(dst, src, &fieldmaskpb.FieldMask{Paths: []string{"layer1"}}); err != nil {
+		t.Fatal(err)
+	}
+	dst.Layer1.Vstring = "changed"
+	if src.Layer1.Vstring != "src" {
+		t.Errorf("TestApplyFieldMask: changing dst changed src")
+	}
+}
+
+func TestFilterByFieldMask(t *testing.T) {
+	msg := &pb.Customer{
+		Name:    "name",
+		Tags:    []string{"a"},
+		Labels:  map[string]string{"a": "1", "b": "2"},
+		Payment: &pb.Payment{Id: "p", Method: &pb.Payment_Voucher{Voucher: "v"}},
+	}
+	mask := &fieldmaskpb.FieldMask{Paths: []string{"tags", `labels["b"]`, "payment.voucher"}}
+	if err := FilterByFieldMask(msg, mask); err != nil {
+		t.Fatalf("TestFilterByFieldMask: got err == %s, want err == nil", err)
+	}
+	want := &pb.Customer{
+		Tags:    []string{"a"},
+		Labels:  map[string]string{"b": "2"},
+		Payment: &pb.Payment{Method: &pb.Payment_Voucher{Voucher: "v"}},
+	}
+	if diff := cmp.Diff(want, msg, protocmp.Transform()); diff != "" {
+		t.Errorf("TestFilterByFieldMask: -want/+got:\n%s", diff)
+	}
+
+	legacy := newLegacy()
+	if err := FilterByFieldMask(legacy, &fieldmaskpb.FieldMask{Paths: []string{"name", "(r3.nickname)"}}); err != nil {
+		t.Fatalf("TestFilterByFieldMask(extension): got err == %s, want err == nil", err)
+	}
+	wantLegacy := &pb.Legacy{Name: proto.String("root")}
+	proto.SetExtension(wantLegacy, pb.E_Nickname, "bob")
+	if diff := cmp.Diff(wantLegacy, legacy, protocmp.Transform()); diff != "" {
+		t.Errorf("TestFilterByFieldMask(extension): -want/+got:\n%s", diff)
+	}
+}
+
+func TestMaskPathFor(t *testing.T) {
+	tests := []struct {
+		desc   string
+		md     protoreflect.MessageDescriptor
+		fqPath string
+		want   string
+	}{
+		{desc: "field", md: (&pb.Customer{}).ProtoReflect().Descriptor(), fqPath: "payment.id", want: "payment.id"},
+		{desc: "list entry", md: (&pb.Customer{}).ProtoReflect().Descriptor(), fqPath: "orders[1].id", want: "orders"},
+		{desc: "map entry", md: (&pb.Customer{}).ProtoReflect().Descriptor(), fqPath: `by_id[1].id`, want: `by_id[1]`},
+		{desc: "extension", md: (&pb.Legacy{}).ProtoReflect().Descriptor(), fqPath: "(r3.details).count", want: "(r3.details).count"},
+		{desc: "extension list entry", md: (&pb.Legacy{}).ProtoReflect().Descriptor(), fqPath: "(r3.scores)[1]", want: "(r3.scores)"},
+	}
+
+	for _, test := range tests {
+		if got := maskPathFor(test.md, test.fqPath); got != test.want {
+			t.Errorf("TestMaskPathFor(%s): got %q, want %q", test.desc, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,742 @@
+package prototools
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+/*
+Predicate is a compiled filter expression made by Filter(). It is safe to use from many goroutines.
+*/
+type Predicate struct {
+	md   protoreflect.MessageDescriptor
+	expr string
+	root filterNode
+}
+
+/*
+Filter compiles expr into a Predicate for messages described by md. The syntax is that of AIP-160
+(https://google.aip.dev/160), so a list endpoint can take its "filter" parameter as is:
+
+	p, err := prototools.Filter((&pb.Layer0{}).ProtoReflect().Descriptor(), `layer1.supported.vint32 > 5 AND ee = "EE_WHATEVER"`)
+	if err != nil {
+		// Return an InvalidArgument error.
+	}
+	for _, msg := range msgs {
+		ok, err := p.Match(msg)
+		...
+	}
+
+A restriction is a field path, a comparator and a value. Paths are fqPaths as GetField() reads them, so
+"orders[0].id" and `labels["env"]` work. A repeated field without a selector that is not the last field
+matches if any entry matches, so "orders.id = \"x\"" is true if any order has the id "x". As in AIP-160, the
+field after a map without a selector is a key, so `labels.env = "prod"` is `labels["env"] = "prod"`. A repeated
+field or map without a selector that is the last field only has ':', so write tags:"vip" and not tags = "vip".
+
+	╔═══════════════════════╤═══════════════════════════════════════════════════════════════╗
+	║ Syntax                │ Meaning                                                       ║
+	╠═══════════════════════╪═══════════════════════════════════════════════════════════════╣
+	║ a = 1, a == 1         │ Equal                                                         ║
+	║ a != 1                │ Not equal                                                     ║
+	║ a < 1, <=, >, >=      │ Ordered compare, for numbers, strings, Timestamp and Duration ║
+	║ a:*                   │ The field is set, or is a repeated field or map with entries  ║
+	║ tags:"vip"            │ A repeated field has an entry equal to the value              ║
+	║ labels:env            │ A map has the key                                             ║
+	║ x AND y, x && y, x y  │ Both are true                                                 ║
+	║ x OR y, x || y        │ Either is true                                                ║
+	║ NOT x, -x, !x         │ x is false                                                    ║
+	║ (x)                   │ Grouping                                                      ║
+	╚═══════════════════════╧═══════════════════════════════════════════════════════════════╝
+
+As in AIP-160, OR binds tighter than AND, so "a = 1 AND b = 2 OR c = 3" is "a = 1 AND (b = 2 OR c = 3)". This
+is also true of && and ||, which are only other spellings of AND and OR. Use parentheses when in doubt.
+
+Values are strings in double or single quotes, or bare words such as 5, true or EE_WHATEVER. They are read
+for the field's kind the same way as SetFieldFromString(), so an enum can be written with any spelling
+ForwardLookup has or its number, and a Timestamp is an RFC 3339 time. A value with a ":" in it, like a
+time, must be quoted. A "*" in a string value is a wildcard for any number of characters, aka name = "J*".
+Google.protobuf wrappers are compared as the values they wrap.
+
+An empty expr matches every message. expr is checked against md: a field that does not exist is an error with
+the code ErrBadFieldName, a value that is not of the field's type or a comparator the type does not have is
+ErrTypeMismatch and bad syntax is ErrParse. Paths cannot step into a google.protobuf.Any.
+
+When a path cannot be followed in a message because a list entry or map key is not there, the restriction is
+false. Like GetField(), a message that is not set reads as a message with default values.
+*/
+func Filter(md protoreflect.MessageDescriptor, expr string) (*Predicate, error) {
+	p := &Predicate{md: md, expr: expr}
+	toks, err := lexFilter(expr)
+	if err != nil {
+		return nil, err
+	}
+	if len(toks) == 1 {
+		return p, nil
+	}
+
+	fp := &filterParser{md: md, expr: expr, toks: toks}
+	root, err := fp.expression()
+	if err != nil {
+		return nil, err
+	}
+	if tok := fp.peek(); tok.kind != tokEOF {
+		return nil, fp.errorf(tok, "unexpected %q", tok.text)
+	}
+	p.root = root
+	return p, nil
+}
+
+// Match returns true if msg matches the filter. msg must be of the type the Predicate was made for.
+func (p *Predicate) Match(msg proto.Message) (bool, error) {
+	if got := msg.ProtoReflect().Descriptor().FullName(); got != p.md.FullName() {
+		return false, Errorf(ErrTypeMismatch, "filter is for %s messages, got a %s", p.md.FullName(), got)
+	}
+	if p.root == nil {
+		return true, nil
+	}
+	return p.root.eval(msg)
+}
+
+// String returns the expression the Predicate was compiled from.
+func (p *Predicate) String() string {
+	return p.expr
+}
+
+// filterNode is a node in a compiled filter.
+type filterNode interface {
+	eval(msg proto.Message) (bool, error)
+}
+
+type andNode []filterNode
+
+func (a andNode) eval(msg proto.Message) (bool, error) {
+	for _, n := range a {
+		ok, err := n.eval(msg)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+type orNode []filterNode
+
+func (o orNode) eval(msg proto.Message) (bool, error) {
+	for _, n := range o {
+		ok, err := n.eval(msg)
+		if err != nil || ok {
+			return ok, err
+		}
+	}
+	return false, nil
+}
+
+type notNode struct {
+	n filterNode
+}
+
+func (n notNode) eval(msg proto.Message) (bool, error) {
+	ok, err := n.n.eval(msg)
+	return !ok, err
+}
+
+// hasNode is true if the field called name is set in any message at parent, which is the root message if
+// parent is "". The field is found by name so that messages with an equivalent descriptor, such as a
+// dynamicpb.Message, work.
+type hasNode struct {
+	parent string
+	name   string
+}
+
+func (h hasNode) eval(msg proto.Message) (bool, error) {
+	if h.parent == "" {
+		return h.has(msg.ProtoReflect()), nil
+	}
+	matches, err := filterMatches(msg, h.parent)
+	if err != nil {
+		return false, err
+	}
+	for _, m := range matches {
+		if m.IsNil() {
+			continue
+		}
+		if h.has(m.Value.(proto.Message).ProtoReflect()) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (h hasNode) has(ref protoreflect.Message) bool {
+	if !ref.IsValid() {
+		return false
+	}
+	fd := partField(ref.Descriptor(), h.name)
+	return fd != nil && ref.Has(fd)
+}
+
+// existsNode is true if path, which ends in a selector, can be followed.
+type existsNode struct {
+	path string
+}
+
+func (e existsNode) eval(msg proto.Message) (bool, error) {
+	matches, err := filterMatches(msg, e.path)
+	return len(matches) > 0, err
+}
+
+// compareNode is true if any value at path compares to value with op.
+type compareNode struct {
+	path  string
+	op    string
+	value interface{}
+}
+
+func (c compareNode) eval(msg proto.Message) (bool, error) {
+	matches, err := filterMatches(msg, c.path)
+	if err != nil {
+		return false, err
+	}
+	for _, m := range matches {
+		v := m.Value
+		if m.Kind == protoreflect.MessageKind {
+			if m.WellKnown == nil {
+				continue
+			}
+			v = m.WellKnown
+		}
+		if c.compare(filterValue(v)) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// compare returns true if v compares to c.value with c.op. v is of the same type as c.value.
+func (c compareNode) compare(v interface{}) bool {
+	var cmp int
+	switch want := c.value.(type) {
+	case string:
+		got := v.(string)
+		if strings.Contains(want, "*") && c.op != "<" && c.op != "<=" && c.op != ">" && c.op != ">=" {
+			return globMatch(want, got) == (c.op != "!=")
+		}
+		cmp = strings.Compare(got, want)
+	case int64:
+		cmp = compareOrdered(v.(int64) < want, v.(int64) > want)
+	case uint64:
+		cmp = compareOrdered(v.(uint64) < want, v.(uint64) > want)
+	case float64:
+		cmp = compareOrdered(v.(float64) < want, v.(float64) > want)
+	case time.Duration:
+		cmp = compareOrdered(v.(time.Duration) < want, v.(time.Duration) > want)
+	case time.Time:
+		cmp = compareOrdered(v.(time.Time).Before(want), v.(time.Time).After(want))
+	case []byte:
+		cmp = compareOrdered(false, !bytes.Equal(v.([]byte), want))
+	default:
+		// bool and protoreflect.EnumNumber can only be equal or not.
+		cmp = compareOrdered(false, v != c.value)
+	}
+
+	switch c.op {
+	case "=", ":":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	}
+	return false
+}
+
+func compareOrdered(less, more bool) int {
+	switch {
+	case less:
+		return -1
+	case more:
+		return 1
+	}
+	return 0
+}
+
+// filterMatches is GetAll(), except that a path that cannot be followed in msg has no matches.
+func filterMatches(msg proto.Message, path string) ([]Match, error) {
+	matches, err := GetAll(msg, path)
+	if err != nil {
+		if e, ok := err.(Error); ok && missingCode(e.Code) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return matches, nil
+}
+
+// filterValue converts the Go value of a field to the type a filter compares it as. Smaller numbers
+// become their 64 bit versions.
+func filterValue(v interface{}) interface{} {
+	switch x := v.(type) {
+	case int32:
+		return int64(x)
+	case uint32:
+		return uint64(x)
+	case float32:
+		return float64(x)
+	}
+	return v
+}
+
+// globMatch returns true if s matches pattern, where "*" in pattern matches any number of characters.
+func globMatch(pattern, s string) bool {
+	parts := strings.Split(pattern, "*")
+	if !strings.HasPrefix(s, parts[0]) {
+		return false
+	}
+	s = s[len(parts[0]):]
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(s, part)
+		if i == -1 {
+			return false
+		}
+		s = s[i+len(part):]
+	}
+	return strings.HasSuffix(s, parts[len(parts)-1])
+}
+
+type tokKind int8
+
+const (
+	tokEOF tokKind = iota
+	// tokText is a field path, keyword or a value that is not quoted.
+	tokText
+	// tokString is a quoted value, text is without the quotes.
+	tokString
+	tokLParen
+	tokRParen
+	tokAnd
+	tokOr
+	tokNot
+	tokComparator
+)
+
+type filterToken struct {
+	kind tokKind
+	text string
+	pos  int
+}
+
+// comparators are the comparators of a restriction, longest first so that "<=" is not read as "<".
+var comparators = []string{"==", "!=", "<=", ">=", "=", "<", ">", ":"}
+
+// lexFilter splits expr into tokens. The last token is always tokEOF.
+func lexFilter(expr string) ([]filterToken, error) {
+	var toks []filterToken
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+			continue
+		case c == '(':
+			toks = append(toks, filterToken{kind: tokLParen, text: "(", pos: i})
+			i++
+			continue
+		case c == ')':
+			toks = append(toks, filterToken{kind: tokRParen, text: ")", pos: i})
+			i++
+			continue
+		case strings.HasPrefix(expr[i:], "&&"):
+			toks = append(toks, filterToken{kind: tokAnd, text: "&&", pos: i})
+			i += 2
+			continue
+		case strings.HasPrefix(expr[i:], "||"):
+			toks = append(toks, filterToken{kind: tokOr, text: "||", pos: i})
+			i += 2
+			continue
+		case c == '!' && !strings.HasPrefix(expr[i:], "!="):
+			toks = append(toks, filterToken{kind: tokNot, text: "!", pos: i})
+			i++
+			continue
+		case c == '"' || c == '\'':
+			s, n, err := unquoteFilter(expr[i:])
+			if err != nil {
+				return nil, Errorf(ErrParse, "filter(%s) has a bad string at offset %d: %s", expr, i, err)
+			}
+			toks = append(toks, filterToken{kind: tokString, text: s, pos: i})
+			i += n
+			continue
+		}
+
+		if op := comparatorAt(expr[i:]); op != "" {
+			toks = append(toks, filterToken{kind: tokComparator, text: op, pos: i})
+			i += len(op)
+			continue
+		}
+
+		n, err := textLen(expr[i:])
+		if err != nil {
+			return nil, Errorf(ErrParse, "filter(%s) has bad text at offset %d: %s", expr, i, err)
+		}
+		toks = append(toks, filterToken{kind: tokText, text: expr[i : i+n], pos: i})
+		i += n
+	}
+	return append(toks, filterToken{kind: tokEOF, pos: len(expr)}), nil
+}
+
+func comparatorAt(s string) string {
+	for _, op := range comparators {
+		if strings.HasPrefix(s, op) {
+			return op
+		}
+	}
+	return ""
+}
+
+// textLen returns the length of the text token at the start of s. Selectors, aka `labels["a b"]`, and
+// extension names after a ".", aka "layer1.(my.pkg.ext)", are part of the text.
+func textLen(s string) (int, error) {
+	i := 0
+	for i < len(s) {
+		c := s[i]
+		switch {
+		case c == '[' || (c == '(' && i > 0 && s[i-1] == '.'):
+			end := byte(']')
+			if c == '(' {
+				end = ')'
+			}
+			n, err := groupLen(s[i:], end)
+			if err != nil {
+				return 0, err
+			}
+			i += n
+			continue
+		case strings.IndexByte(" \t\n\r()\"'!=<>:&|", c) != -1:
+			if i == 0 {
+				return 0, fmt.Errorf("unexpected %q", c)
+			}
+			return i, nil
+		}
+		i++
+	}
+	return i, nil
+}
+
+// groupLen returns the length of the group at the start of s, which ends at the first end that is not
+// in a double quoted string.
+func groupLen(s string, end byte) (int, error) {
+	quoted := false
+	for i := 1; i < len(s); i++ {
+		switch {
+		case quoted && s[i] == '\\':
+			i++
+		case s[i] == '"':
+			quoted = !quoted
+		case !quoted && s[i] == end:
+			return i + 1, nil
+		}
+	}
+	return 0, fmt.Errorf("%q has no closing %q", s, end)
+}
+
+// unquoteFilter reads the quoted string at the start of s and returns it without quotes, along with
+// how many bytes of s it used. Single quoted strings have the same escapes as double quoted ones.
+func unquoteFilter(s string) (string, int, error) {
+	q := s[0]
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case q:
+			body := s[1:i]
+			if q == '\'' {
+				body = strings.Replace(strings.Replace(body, `\'`, `'`, -1), `"`, `\"`, -1)
+			}
+			u, err := strconv.Unquote(`"` + body + `"`)
+			return u, i + 1, err
+		}
+	}
+	return "", 0, fmt.Errorf("%s is not closed", s)
+}
+
+// filterParser is a recursive descent parser for the AIP-160 grammar:
+//
+//	expression = sequence { AND sequence }
+//	sequence   = factor { factor }
+//	factor     = term { OR term }
+//	term       = [ NOT | "-" ] simple
+//	simple     = restriction | "(" expression ")"
+type filterParser struct {
+	md   protoreflect.MessageDescriptor
+	expr string
+	toks []filterToken
+	i    int
+}
+
+func (p *filterParser) peek() filterToken {
+	return p.toks[p.i]
+}
+
+func (p *filterParser) next() filterToken {
+	tok := p.toks[p.i]
+	if tok.kind != tokEOF {
+		p.i++
+	}
+	return tok
+}
+
+func (p *filterParser) errorf(tok filterToken, msg string, i ...interface{}) error {
+	return Errorf(ErrParse, "filter(%s) at offset %d: %s", p.expr, tok.pos, fmt.Sprintf(msg, i...))
+}
+
+func isKeyword(tok filterToken, kind tokKind, word string) bool {
+	return tok.kind == kind || (tok.kind == tokText && tok.text == word)
+}
+
+func (p *filterParser) expression() (filterNode, error) {
+	var and andNode
+	for {
+		n, err := p.sequence()
+		if err != nil {
+			return nil, err
+		}
+		and = append(and, n...)
+		if !isKeyword(p.peek(), tokAnd, "AND") {
+			break
+		}
+		p.next()
+	}
+	if len(and) == 1 {
+		return and[0], nil
+	}
+	return and, nil
+}
+
+func (p *filterParser) sequence() (andNode, error) {
+	var seq andNode
+	for {
+		n, err := p.factor()
+		if err != nil {
+			return nil, err
+		}
+		seq = append(seq, n)
+
+		switch tok := p.peek(); {
+		case tok.kind == tokEOF, tok.kind == tokRParen, isKeyword(tok, tokAnd, "AND"):
+			return seq, nil
+		}
+	}
+}
+
+func (p *filterParser) factor() (filterNode, error) {
+	var or orNode
+	for {
+		n, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+		or = append(or, n)
+		if !isKeyword(p.peek(), tokOr, "OR") {
+			break
+		}
+		p.next()
+	}
+	if len(or) == 1 {
+		return or[0], nil
+	}
+	return or, nil
+}
+
+func (p *filterParser) term() (filterNode, error) {
+	tok := p.peek()
+	switch {
+	case isKeyword(tok, tokNot, "NOT"), tok.kind == tokText && tok.text == "-":
+		p.next()
+	case tok.kind == tokText && strings.HasPrefix(tok.text, "-"):
+		p.toks[p.i].text = tok.text[1:]
+		p.toks[p.i].pos++
+	default:
+		return p.simple()
+	}
+	n, err := p.simple()
+	if err != nil {
+		return nil, err
+	}
+	return notNode{n}, nil
+}
+
+func (p *filterParser) simple() (filterNode, error) {
+	tok := p.next()
+	switch {
+	case tok.kind == tokLParen:
+		n, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		if end := p.next(); end.kind != tokRParen {
+			return nil, p.errorf(end, "expected ')'")
+		}
+		return n, nil
+	case tok.kind == tokEOF:
+		return nil, p.errorf(tok, "unexpected end of filter")
+	case tok.kind != tokText || tok.text == "AND" || tok.text == "OR" || tok.text == "NOT":
+		return nil, p.errorf(tok, "expected a field, got %q", tok.text)
+	}
+
+	op := p.next()
+	if op.kind != tokComparator {
+		return nil, p.errorf(tok, "%q must be followed by a comparator, searching every field is not supported", tok.text)
+	}
+	arg := p.next()
+	if arg.kind != tokText && arg.kind != tokString {
+		return nil, p.errorf(arg, "%q must be followed by a value", op.text)
+	}
+	if op.text == "==" {
+		op.text = "="
+	}
+	return p.restriction(tok.text, op.text, arg)
+}
+
+// restriction compiles a restriction on the field at fqPath, checking it against the descriptor.
+func (p *filterParser) restriction(fqPath, op string, arg filterToken) (filterNode, error) {
+	fields := FQPathSplit(fqPath)
+	if len(checkableFields(p.md, fields)) != len(fields) {
+		return nil, Errorf(ErrBadPath, "field(%s) is in a google.protobuf.Any, which filters cannot look into", fqPath)
+	}
+
+	fields, err := filterPath(p.md, fqPath, fields)
+	if err != nil {
+		return nil, err
+	}
+	fd, err := checkPath(p.md, fields)
+	if err != nil {
+		return nil, err
+	}
+	pp, err := parsePart(fields[len(fields)-1])
+	if err != nil {
+		return nil, err
+	}
+	path := strings.Join(fields, ".")
+
+	switch {
+	case op == ":" && arg.kind == tokText && arg.text == "*":
+		if pp.hasSelector {
+			return existsNode{path: path}, nil
+		}
+		return hasNode{parent: strings.Join(fields[:len(fields)-1], "."), name: pp.name}, nil
+	case fd.IsMap() && !pp.hasSelector:
+		if op != ":" {
+			return nil, Errorf(ErrTypeMismatch, "field(%s) is a map, which only has ':' to test for a key, aka %s:key", fqPath, fqPath)
+		}
+		k, err := stringValue(nil, fd.MapKey(), fqPath, arg.text)
+		if err != nil {
+			return nil, typeErr(err)
+		}
+		return existsNode{path: fmt.Sprintf("%s[%s]", path, keySelector(k.MapKey()))}, nil
+	case fd.IsList() && !pp.hasSelector:
+		if op != ":" {
+			return nil, Errorf(ErrTypeMismatch, "field(%s) is a repeated field, which only has ':' to test for an entry, aka %s:value", fqPath, fqPath)
+		}
+		path += "[" + Wildcard + "]"
+	}
+
+	v, err := filterLiteral(fd, fqPath, arg.text)
+	if err != nil {
+		return nil, err
+	}
+	switch op {
+	case "<", "<=", ">", ">=":
+		switch v.(type) {
+		case bool, protoreflect.EnumNumber, []byte:
+			return nil, Errorf(ErrTypeMismatch, "field(%s) is of type %s, which does not have %q", fqPath, kindName(valueDesc(fd)), op)
+		}
+	}
+	return compareNode{path: path, op: op, value: v}, nil
+}
+
+// filterPath changes the fields of a restriction into the fields of a GetAll() path. A repeated field of messages
+// without a selector that is not the last field gets a wildcard, so it matches on any entry. A map without a
+// selector is followed by a key, as in AIP-160, which becomes its selector, so labels.env is labels["env"].
+func filterPath(md protoreflect.MessageDescriptor, fqPath string, fields []string) ([]string, error) {
+	path := make([]string, 0, len(fields))
+	for x := 0; x < len(fields); x++ {
+		field := fields[x]
+		pp, err := parsePart(field)
+		if err != nil || x == len(fields)-1 {
+			// checkPath() reports the errors.
+			return append(path, fields[x:]...), nil
+		}
+		fd := partField(md, pp.name)
+		if fd == nil {
+			return append(path, fields[x:]...), nil
+		}
+		switch {
+		case fd.IsMap() && !pp.hasSelector:
+			x++
+			k, err := stringValue(nil, fd.MapKey(), fqPath, fields[x])
+			if err != nil {
+				return nil, typeErr(err)
+			}
+			field = fmt.Sprintf("%s[%s]", field, keySelector(k.MapKey()))
+		case fd.IsList() && !pp.hasSelector:
+			field += "[" + Wildcard + "]"
+		}
+		path = append(path, field)
+		if valueDesc(fd).Kind() != protoreflect.MessageKind {
+			return append(path, fields[x+1:]...), nil
+		}
+		md = valueDesc(fd).Message()
+	}
+	return path, nil
+}
+
+// filterLiteral parses s into the value a field of fd is compared with, of the type filterValue() returns.
+func filterLiteral(fd protoreflect.FieldDescriptor, fqPath, s string) (interface{}, error) {
+	vd := valueDesc(fd)
+	if vd.Kind() == protoreflect.MessageKind {
+		switch name := vd.Message().FullName(); {
+		case name == timestampName:
+			t, ok := parseTime(s)
+			if !ok {
+				return nil, typeErr(parseErr(fqPath, vd, s, "not an RFC 3339 time"))
+			}
+			return t, nil
+		case name == durationName:
+			d, err := time.ParseDuration(strings.TrimSpace(s))
+			if err != nil {
+				return nil, typeErr(parseErr(fqPath, vd, s, err.Error()))
+			}
+			return d, nil
+		case wrapperNames[name]:
+			return filterLiteral(vd.Message().Fields().ByName("value"), fqPath, s)
+		}
+		return nil, Errorf(ErrTypeMismatch, "field(%s) is a %s, which can only be tested for presence, aka %s:*", fqPath, vd.Message().FullName(), fqPath)
+	}
+
+	v, err := stringValue(nil, fd, fqPath, s)
+	if err != nil {
+		return nil, typeErr(err)
+	}
+	return filterValue(v.Interface()), nil
+}
+
+// typeErr changes an ErrParse from parsing a value into an ErrTypeMismatch, as in a filter it means the
+// value was not of the field's type.
+func typeErr(err error) error {
+	if e, ok := err.(Error); ok && e.Code == ErrParse {
+		e.Code = ErrTypeMismatch
+		return e
+	}
+	return err
+}
@@ -0,0 +1,169 @@
+package prototools
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/johnsiilver/prototools/sample"
+)
+
+func TestFilter(t *testing.T) {
+	layer0 := &pb.Layer0{
+		Vint32: 3,
+		Ee:     pb.Layer0_EE_WHATEVER,
+		Layer1: &pb.Layer1{
+			Vstring:   "hello world",
+			Supported: &pb.Supported{Vint32: 10, Vdouble: 2.5, Vbool: true, Ev: pb.EnumValues_EV_Not_Ok, VTime: 1614834367},
+		},
+	}
+	customer := newCustomer()
+	customer.Labels = map[string]string{"env": "prod"}
+	customer.ById = map[int64]*pb.Order{5: {Id: "five"}}
+
+	tests := []struct {
+		desc string
+		msg  proto.Message
+		expr string
+		want bool
+	}{
+		{desc: "empty", msg: layer0, expr: " ", want: true},
+		{desc: "request example", msg: layer0, expr: `layer1.supported.vint32 > 5 && ee == "EE_WHATEVER"`, want: true},
+		{desc: "AND keyword", msg: layer0, expr: `layer1.supported.vint32 > 5 AND ee = EE_UNKNOWN`},
+		{desc: "implicit AND", msg: layer0, expr: `vint32 = 3 layer1.supported.vbool = true`, want: true},
+		{desc: "OR", msg: layer0, expr: `vint32 = 4 OR vint32 <= 3`, want: true},
+		{desc: "OR binds tighter than AND", msg: layer0, expr: `vint32 = 3 AND vint32 = 4 OR vint32 = 5`},
+		{desc: "parentheses", msg: layer0, expr: `(vint32 = 3 AND vint32 = 4) OR vint32 = 3`, want: true},
+		{desc: "NOT", msg: layer0, expr: `NOT vint32 = 3`},
+		{desc: "minus", msg: layer0, expr: `-vint32 = 4`, want: true},
+		{desc: "bang group", msg: layer0, expr: `!(vint32 = 4 || vint32 = 5)`, want: true},
+		{desc: "negative number", msg: layer0, expr: `vint32 > -1`, want: true},
+		{desc: "not equal", msg: layer0, expr: `layer1.vstring != "hello world"`},
+		{desc: "string order", msg: layer0, expr: `layer1.vstring >= 'hello'`, want: true},
+		{desc: "wildcard", msg: layer0, expr: `layer1.vstring = "hel*wor*"`, want: true},
+		{desc: "wildcard miss", msg: layer0, expr: `layer1.vstring = "*moon"`},
+		{desc: "double", msg: layer0, expr: `layer1.supported.vdouble < 2.6`, want: true},
+		{desc: "JSON enum spelling", msg: layer0, expr: `layer1.supported.ev = evNotOk`, want: true},
+		{desc: "titled enum spelling", msg: layer0, expr: `layer1.supported.ev = "Not Ok"`, want: true},
+		{desc: "enum number", msg: layer0, expr: `layer1.supported.ev = 2`, want: true},
+		{desc: "_time field", msg: layer0, expr: `layer1.supported.v_time < "2021-03-05"`, want: true},
+		{desc: "unset message reads as defaults", msg: &pb.Layer0{}, expr: `layer1.supported.vint32 = 0`, want: true},
+		{desc: "presence", msg: layer0, expr: `layer1:*`, want: true},
+		{desc: "presence unset", msg: &pb.Layer0{}, expr: `layer1:*`},
+		{desc: "presence nested unset", msg: &pb.Layer0{}, expr: `layer1.supported:*`},
+		{desc: "repeated has", msg: customer, expr: `tags:"vip"`, want: true},
+		{desc: "repeated has miss", msg: customer, expr: `tags:vop`},
+		{desc: "repeated presence", msg: customer, expr: `orders:*`, want: true},
+		{desc: "any entry", msg: customer, expr: `orders.lines.sku = "sku2"`, want: true},
+		{desc: "any entry miss", msg: customer, expr: `orders.lines.quantity > 3`},
+		{desc: "index", msg: customer, expr: `orders[1].id = order1`, want: true},
+		{desc: "index out of range", msg: customer, expr: `orders[5].id = order1`},
+		{desc: "not index out of range", msg: customer, expr: `NOT orders[5].id = order1`, want: true},
+		{desc: "index presence", msg: customer, expr: `orders[5]:*`},
+		{desc: "map key", msg: customer, expr: `labels:env`, want: true},
+		{desc: "map key miss", msg: customer, expr: `labels:"dev"`},
+		{desc: "map int key", msg: customer, expr: `by_id:5`, want: true},
+		{desc: "map value", msg: customer, expr: `labels["env"] = "prod"`, want: true},
+		{desc: "map key traversal", msg: customer, expr: `labels.env = "prod"`, want: true},
+		{desc: "map key traversal miss", msg: customer, expr: `labels.dev = "prod"`},
+		{desc: "map key traversal presence", msg: customer, expr: `labels.env:*`, want: true},
+		{desc: "map int key traversal", msg: customer, expr: `by_id.5.id = "five"`, want: true},
+		{desc: "timestamp", msg: newWellKnown(), expr: `vtimestamp > "2021-03-04T05:06:07Z"`, want: true},
+		{desc: "repeated timestamp", msg: newWellKnown(), expr: `l_timestamp[0] >= "2021-03-04T06:00:00Z"`, want: true},
+		{desc: "repeated timestamp has", msg: newWellKnown(), expr: `l_timestamp:"2021-03-04T06:06:07.0000005Z"`, want: true},
+		{desc: "duration", msg: newWellKnown(), expr: `vduration = 1m30s`, want: true},
+		{desc: "wrapper", msg: newWellKnown(), expr: `vint64_value < 0 AND vstring_value = hello`, want: true},
+		{desc: "unset wrapper", msg: &pb.WellKnown{}, expr: `vint64_value = 0`},
+	}
+
+	for _, test := range tests {
+		p, err := Filter(test.msg.ProtoReflect().Descriptor(), test.expr)
+		if err != nil {
+			t.Errorf("TestFilter(%s): got err == %s, want err == nil", test.desc, err)
+			continue
+		}
+		got, err := p.Match(test.msg)
+		if err != nil {
+			t.Errorf("TestFilter(%s): Match got err == %s, want err == nil", test.desc, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("TestFilter(%s): got %v, want %v", test.desc, got, test.want)
+		}
+	}
+}
+
+func TestFilterErrors(t *testing.T) {
+	tests := []struct {
+		desc string
+		msg  proto.Message
+		expr string
+		code ErrCode
+	}{
+		{desc: "unknown field", msg: &pb.Layer0{}, expr: `layer1.nope = 1`, code: ErrBadFieldName},
+		{desc: "not a message", msg: &pb.Layer0{}, expr: `vint32.a = 1`, code: ErrIntermediateNotMessage},
+		{desc: "string for int", msg: &pb.Layer0{}, expr: `vint32 = "abc"`, code: ErrTypeMismatch},
+		{desc: "int out of range", msg: &pb.Layer0{}, expr: `vint32 = 3000000000`, code: ErrValueOutOfRange},
+		{desc: "not an enum value", msg: &pb.Layer0{}, expr: `ee = EV_Ok`, code: ErrTypeMismatch},
+		{desc: "ordered enum", msg: &pb.Layer0{}, expr: `ee > EE_UNKNOWN`, code: ErrTypeMismatch},
+		{desc: "ordered bool", msg: &pb.Supported{}, expr: `vbool < true`, code: ErrTypeMismatch},
+		{desc: "message compare", msg: &pb.Layer0{}, expr: `layer1 = 1`, code: ErrTypeMismatch},
+		{desc: "map compare", msg: &pb.Customer{}, expr: `labels = "env"`, code: ErrTypeMismatch},
+		{desc: "map key of the wrong type", msg: &pb.Customer{}, expr: `by_id.id = "five"`, code: ErrTypeMismatch},
+		{desc: "repeated compare", msg: &pb.Customer{}, expr: `tags = "vip"`, code: ErrTypeMismatch},
+		{desc: "repeated ordered compare", msg: &pb.WellKnown{}, expr: `l_timestamp >= "2021-03-04T06:00:00Z"`, code: ErrTypeMismatch},
+		{desc: "bad time", msg: &pb.WellKnown{}, expr: `vtimestamp > yesterday`, code: ErrTypeMismatch},
+		{desc: "into an Any", msg: &pb.WellKnown{}, expr: `vany.vint32 = 1`, code: ErrBadPath},
+		{desc: "no comparator", msg: &pb.Layer0{}, expr: `vint32`, code: ErrParse},
+		{desc: "no value", msg: &pb.Layer0{}, expr: `vint32 =`, code: ErrParse},
+		{desc: "unclosed paren", msg: &pb.Layer0{}, expr: `(vint32 = 1`, code: ErrParse},
+		{desc: "extra paren", msg: &pb.Layer0{}, expr: `vint32 = 1)`, code: ErrParse},
+		{desc: "unclosed string", msg: &pb.Layer0{}, expr: `layer1.vstring = "a`, code: ErrParse},
+		{desc: "dangling AND", msg: &pb.Layer0{}, expr: `vint32 = 1 AND`, code: ErrParse},
+	}
+
+	for _, test := range tests {
+		_, err := Filter(test.msg.ProtoReflect().Descriptor(), test.expr)
+		checkCode(t, "TestFilterErrors", test.desc, err, test.code)
+	}
+
+	p, err := Filter((&pb.Layer0{}).ProtoReflect().Descriptor(), "vint32 = 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = p.Match(&pb.Supported{})
+	checkCode(t, "TestFilterErrors", "wrong message type", err, ErrTypeMismatch)
+}
+
+func TestFilterDynamic(t *testing.T) {
+	reg := sampleRegistry(t)
+	dyn := toDynamic(t, reg, newCustomer())
+
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{expr: `name = "J*" AND orders.lines.price > 3 AND NOT payment:*`, want: true},
+		{expr: `name = "J*" AND payment:*`},
+		{expr: `tags:vip AND orders[0].lines[-1].sku = sku1`, want: true},
+	}
+
+	// A Predicate compiled from the dynamic descriptor works on the dynamic and the generated message.
+	for _, test := range tests {
+		p, err := Filter(dyn.ProtoReflect().Descriptor(), test.expr)
+		if err != nil {
+			t.Errorf("TestFilterDynamic(%s): got err == %s, want err == nil", test.expr, err)
+			continue
+		}
+		for _, msg := range []proto.Message{dyn, newCustomer()} {
+			got, err := p.Match(msg)
+			if err != nil {
+				t.Errorf("TestFilterDynamic(%s): Match(%T) got err == %s, want err == nil", test.expr, msg, err)
+				continue
+			}
+			if got != test.want {
+				t.Errorf("TestFilterDynamic(%s): Match(%T) got %v, want %v", test.expr, msg, got, test.want)
+			}
+		}
+	}
+}
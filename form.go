@@ -0,0 +1,356 @@
+package prototools
+
+import (
+	"fmt"
+	"io/ioutil"
+	"mime/multipart"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// FormErrors is returned by BindForm() and BindMultipartForm() when one or more form keys could not be bound.
+// It maps the form key to the error for that key.
+type FormErrors map[string]error
+
+// Error implements error.Error().
+func (f FormErrors) Error() string {
+	keys := make([]string, 0, len(f))
+	for k := range f {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	b := &strings.Builder{}
+	for i, k := range keys {
+		if i > 0 {
+			b.WriteString("; ")
+		}
+		fmt.Fprintf(b, "form key(%s): %s", k, f[k])
+	}
+	return b.String()
+}
+
+type bindOpts struct {
+	ignoreUnknown bool
+	onOneofChange func(OneofChange)
+}
+
+// updateOptions returns the options for UpdateProtoField() that bind a single value.
+func (o bindOpts) updateOptions() []UpdateOption {
+	options := []UpdateOption{CreateIntermediates()}
+	if o.onOneofChange != nil {
+		options = append(options, OnOneofChange(o.onOneofChange))
+	}
+	return options
+}
+
+// BindOption is an option for BindForm() and BindMultipartForm().
+type BindOption func(o *bindOpts)
+
+// IgnoreUnknownKeys has form keys that do not match a field ignored instead of reported as an error.
+// This is useful when a form has inputs that are not part of the message, such as a CSRF token or a submit button.
+func IgnoreUnknownKeys() BindOption {
+	return func(o *bindOpts) {
+		o.ignoreUnknown = true
+	}
+}
+
+// BindOnOneofChange has f called when binding a key clears a different member of a oneof, the same as
+// OnOneofChange() does for UpdateProtoField(). Without it, a form can switch the member that is set without
+// the caller knowing.
+func BindOnOneofChange(f func(OneofChange)) BindOption {
+	return func(o *bindOpts) {
+		o.onOneofChange = f
+	}
+}
+
+/*
+BindForm sets fields in msg from values, which is usually http.Request.Form after a call to ParseForm().
+Each key is an fqPath. Each field in the path can use the proto name or the JSON name of the field, so
+"by_id[5].id" and "byId[5].id" are both valid keys. Keys of maps with string keys do not need to be
+quoted, so labels[env] is the same as labels["env"]. Intermediate messages that are not set are created, as
+UpdateProtoField() does with CreateIntermediates(), so "lines[0].sku" works on a message without lines.
+
+Values are parsed with the same rules as SetFieldFromString(). A key for a repeated field without an index
+can have multiple values, which replace the content of the field in the order they were sent. All other keys
+must have a single value, more than one is an error with the code ErrValueOutOfRange. An empty value for a field that is not a string or bytes field is ignored, as that
+is what a browser sends for an input that was left blank.
+
+Keys are bound in sorted order. A key that fails does not stop other keys from being bound. If any key
+fails, this returns a FormErrors that has an error for each key that failed.
+*/
+func BindForm(msg proto.Message, values url.Values, options ...BindOption) error {
+	opts := bindOpts{}
+	for _, o := range options {
+		o(&opts)
+	}
+
+	errs := FormErrors{}
+	md := msg.ProtoReflect().Descriptor()
+	for _, key := range sortedFormKeys(values) {
+		fqPath, fd, err := formPath(md, key)
+		if err != nil {
+			if e, ok := err.(Error); ok && e.Code == ErrBadFieldName && opts.ignoreUnknown {
+				continue
+			}
+			errs[key] = err
+			continue
+		}
+		if err := bindValues(msg, key, fqPath, fd, values[key], opts); err != nil {
+			errs[key] = err
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+/*
+BindMultipartForm is like BindForm(), but binds a multipart form, usually http.Request.MultipartForm after a
+call to ParseMultipartForm(). form.Value is bound as BindForm() would. Each file in form.File is read into the
+bytes field that matches its key. A repeated bytes field without an index receives every file sent with that key.
+*/
+func BindMultipartForm(msg proto.Message, form *multipart.Form, options ...BindOption) error {
+	opts := bindOpts{}
+	for _, o := range options {
+		o(&opts)
+	}
+
+	errs := FormErrors{}
+	if err := BindForm(msg, form.Value, options...); err != nil {
+		fe, ok := err.(FormErrors)
+		if !ok {
+			return err
+		}
+		errs = fe
+	}
+
+	md := msg.ProtoReflect().Descriptor()
+	keys := make([]string, 0, len(form.File))
+	for k := range form.File {
+		keys = append(keys, k)
+	}
+	sortFormKeys(keys)
+
+	for _, key := range keys {
+		fqPath, fd, err := formPath(md, key)
+		if err != nil {
+			if e, ok := err.(Error); ok && e.Code == ErrBadFieldName && opts.ignoreUnknown {
+				continue
+			}
+			errs[key] = err
+			continue
+		}
+		if err := bindFiles(msg, key, fqPath, fd, form.File[key], opts); err != nil {
+			errs[key] = err
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// bindValues parses vals into the field fd at fqPath. key is the form key and is used in error messages.
+func bindValues(msg proto.Message, key, fqPath string, fd protoreflect.FieldDescriptor, vals []string, opts bindOpts) error {
+	if isWholeList(fqPath, fd) {
+		return bindList(msg, fqPath, fd, opts, func(m protoreflect.Message) ([]protoreflect.Value, error) {
+			list := make([]protoreflect.Value, 0, len(vals))
+			for _, s := range vals {
+				if skipEmpty(fd, s) {
+					continue
+				}
+				val, err := stringValue(m, fd, fqPath, s)
+				if err != nil {
+					return nil, err
+				}
+				list = append(list, val)
+			}
+			return list, nil
+		})
+	}
+
+	if len(vals) != 1 {
+		return Errorf(ErrValueOutOfRange, "form key(%s) has %d values, but field(%s) can only hold one", key, len(vals), fqPath)
+	}
+	if skipEmpty(fd, vals[0]) {
+		return nil
+	}
+	return SetFieldFromString(msg, fqPath, vals[0], opts.updateOptions()...)
+}
+
+// bindFiles reads files into the bytes field fd at fqPath. key is the form key and is used in error messages.
+func bindFiles(msg proto.Message, key, fqPath string, fd protoreflect.FieldDescriptor, files []*multipart.FileHeader, opts bindOpts) error {
+	if valueDesc(fd).Kind() != protoreflect.BytesKind {
+		return Errorf(ErrTypeMismatch, "form key(%s) is a file, but field(%s) is a %s, not bytes", key, fqPath, kindName(valueDesc(fd)))
+	}
+
+	contents := make([][]byte, 0, len(files))
+	for _, fh := range files {
+		b, err := readFile(fh)
+		if err != nil {
+			return Errorf(ErrParse, "form key(%s) file(%s) could not be read: %s", key, fh.Filename, err)
+		}
+		contents = append(contents, b)
+	}
+
+	if isWholeList(fqPath, fd) {
+		return bindList(msg, fqPath, fd, opts, func(protoreflect.Message) ([]protoreflect.Value, error) {
+			list := make([]protoreflect.Value, 0, len(contents))
+			for _, b := range contents {
+				list = append(list, protoreflect.ValueOfBytes(b))
+			}
+			return list, nil
+		})
+	}
+
+	if len(contents) != 1 {
+		return Errorf(ErrValueOutOfRange, "form key(%s) has %d files, but field(%s) can only hold one", key, len(contents), fqPath)
+	}
+	return UpdateProtoField(msg, fqPath, contents[0], opts.updateOptions()...)
+}
+
+// bindList replaces the content of the repeated field fd at fqPath with the values from conv. conv is
+// passed the message that holds the field. If conv returns an error, the field is not changed.
+func bindList(msg proto.Message, fqPath string, fd protoreflect.FieldDescriptor, opts bindOpts, conv func(m protoreflect.Message) ([]protoreflect.Value, error)) error {
+	var changes []OneofChange
+	m, _, _, err := lastField(msg, fqPath, true, &changes, nil)
+	if err != nil {
+		return err
+	}
+	if opts.onOneofChange != nil {
+		for _, change := range changes {
+			opts.onOneofChange(change)
+		}
+	}
+	vals, err := conv(m)
+	if err != nil {
+		return err
+	}
+
+	l := m.Mutable(fd).List()
+	l.Truncate(0)
+	for _, val := range vals {
+		l.Append(val)
+	}
+	return nil
+}
+
+func readFile(fh *multipart.FileHeader) ([]byte, error) {
+	f, err := fh.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ioutil.ReadAll(f)
+}
+
+// isWholeList returns true if fqPath refers to an entire repeated field instead of an entry in it.
+func isWholeList(fqPath string, fd protoreflect.FieldDescriptor) bool {
+	if !fd.IsList() {
+		return false
+	}
+	fields := FQPathSplit(fqPath)
+	pp, _ := parsePart(fields[len(fields)-1])
+	return !pp.hasSelector
+}
+
+// skipEmpty returns true if s is an empty value that should not be bound to the field fd.
+func skipEmpty(fd protoreflect.FieldDescriptor, s string) bool {
+	if s != "" {
+		return false
+	}
+	switch valueDesc(fd).Kind() {
+	case protoreflect.StringKind, protoreflect.BytesKind:
+		return false
+	}
+	return true
+}
+
+// formPath converts a form key into an fqPath that only uses proto names and returns the descriptor of the
+// last field. Each field in the key can be the proto name or the JSON name of the field. Keys for maps with
+// string keys are quoted if they are not already.
+func formPath(md protoreflect.MessageDescriptor, key string) (string, protoreflect.FieldDescriptor, error) {
+	root := md
+	fields := FQPathSplit(key)
+	for x, field := range fields {
+		pp, err := parsePart(field)
+		if err != nil {
+			return "", nil, err
+		}
+		fd := fieldByName(md, pp.name)
+		if fd == nil {
+			return "", nil, Errorf(ErrBadFieldName, "field(%s) could not be found", strings.Join(fields[0:x+1], "."))
+		}
+
+		part := string(fd.Name())
+		if pp.hasSelector {
+			sel := pp.selector
+			if fd.IsMap() && fd.MapKey().Kind() == protoreflect.StringKind {
+				if _, err := strconv.Unquote(sel); err != nil {
+					sel = strconv.Quote(sel)
+				}
+			}
+			part += "[" + sel + "]"
+		}
+		fields[x] = part
+
+		vd := valueDesc(fd)
+		if vd.Kind() != protoreflect.MessageKind {
+			// checkPath() will report an error if this isn't the last field.
+			break
+		}
+		md = vd.Message()
+	}
+
+	fqPath := strings.Join(fields, ".")
+	fd, err := checkPath(root, FQPathSplit(fqPath))
+	if err != nil {
+		return "", nil, err
+	}
+	return fqPath, fd, nil
+}
+
+func sortedFormKeys(values url.Values) []string {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sortFormKeys(keys)
+	return keys
+}
+
+// sortFormKeys sorts keys so that the entries of a list are bound in order. CreateIntermediates only appends
+// to a list at its length, so "orders[2].id" must be bound before "orders[10].id", which sort.Strings() does
+// not do.
+func sortFormKeys(keys []string) {
+	sort.Slice(keys, func(i, j int) bool { return formKeyLess(keys[i], keys[j]) })
+}
+
+// formKeyLess compares the form keys a and b part by part. Selectors that are both integers compare as
+// numbers, everything else compares as strings.
+func formKeyLess(a, b string) bool {
+	as, bs := FQPathSplit(a), FQPathSplit(b)
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		if as[i] == bs[i] {
+			continue
+		}
+		x, errX := parsePart(as[i])
+		y, errY := parsePart(bs[i])
+		if errX == nil && errY == nil && x.name == y.name {
+			xi, errX := strconv.Atoi(x.selector)
+			yi, errY := strconv.Atoi(y.selector)
+			if errX == nil && errY == nil && xi != yi {
+				return xi < yi
+			}
+		}
+		return as[i] < bs[i]
+	}
+	return len(as) < len(bs)
+}
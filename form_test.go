@@ -0,0 +1,216 @@
+package prototools
+
+import (
+	"bytes"
+	"errors"
+	"mime/multipart"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	pb "github.com/johnsiilver/prototools/sample"
+)
+
+func TestBindForm(t *testing.T) {
+	// manyOrders has more than 10 entries, so a string sort of the keys would put orders[10] before orders[2].
+	manyOrders, manyWant := url.Values{}, &pb.Customer{}
+	for i := 0; i < 12; i++ {
+		id := "order" + strconv.Itoa(i)
+		manyOrders.Set("orders["+strconv.Itoa(i)+"].id", id)
+		manyWant.Orders = append(manyWant.Orders, &pb.Order{Id: id})
+	}
+
+	tests := []struct {
+		desc    string
+		msg     proto.Message
+		values  url.Values
+		options []BindOption
+		want    proto.Message
+		// errs are the form keys we expect to fail and the code for each.
+		errs map[string]ErrCode
+	}{
+		{
+			desc: "proto names with intermediate messages",
+			msg:  &pb.Layer0{},
+			values: url.Values{
+				"vint32":                  {"3"},
+				"ee":                      {"EE_WHATEVER"},
+				"layer1.vstring":          {"hello"},
+				"layer1.supported.v_time": {"2021-03-04T05:06:07Z"},
+				"layer1.supported.vbool":  {"on"},
+			},
+			want: &pb.Layer0{
+				Vint32: 3,
+				Ee:     pb.Layer0_EE_WHATEVER,
+				Layer1: &pb.Layer1{
+					Vstring:   "hello",
+					Supported: &pb.Supported{VTime: 1614834367, Vbool: true},
+				},
+			},
+		},
+		{
+			desc: "JSON names and repeated keys",
+			msg:  &pb.Kinds{LInt32: []int32{9, 9, 9}},
+			values: url.Values{
+				"lInt32":  {"1", "", "2"},
+				"lString": {"a", ""},
+				"vint64":  {""},
+			},
+			want: &pb.Kinds{LInt32: []int32{1, 2}, LString: []string{"a", ""}},
+		},
+		{
+			desc: "indexes and map keys",
+			msg:  &pb.Customer{Orders: []*pb.Order{{}}, Tags: []string{"a", "b"}},
+			values: url.Values{
+				"orders[0].id":        {"7"},
+				"tags[-1]":            {"c"},
+				"labels[env]":         {"prod"},
+				`labels["a.b"]`:       {"quoted"},
+				"payment.card.number": {"4111"},
+			},
+			want: &pb.Customer{
+				Orders:  []*pb.Order{{Id: "7"}},
+				Tags:    []string{"a", "c"},
+				Labels:  map[string]string{"env": "prod", "a.b": "quoted"},
+				Payment: &pb.Payment{Method: &pb.Payment_Card{Card: &pb.Card{Number: "4111"}}},
+			},
+		},
+		{
+			desc:   "creates entries through indexes",
+			msg:    &pb.Customer{},
+			values: url.Values{"orders[0].lines[0].sku": {"s"}, "byId[3].id": {"three"}},
+			want: &pb.Customer{
+				Orders: []*pb.Order{{Lines: []*pb.Line{{Sku: "s"}}}},
+				ById:   map[int64]*pb.Order{3: {Id: "three"}},
+			},
+		},
+		{
+			desc:   "list entries are created in index order",
+			msg:    &pb.Customer{},
+			values: manyOrders,
+			want:   manyWant,
+		},
+		{
+			desc: "errors are collected per key",
+			msg:  &pb.Kinds{},
+			values: url.Values{
+				"vint32":  {"x"},
+				"vbool":   {"true", "false"},
+				"nope":    {"1"},
+				"lUint32": {"1", "-1"},
+				"vstring": {"good"},
+			},
+			want: &pb.Kinds{Vstring: "good"},
+			errs: map[string]ErrCode{
+				"vint32":  ErrParse,
+				"vbool":   ErrValueOutOfRange,
+				"nope":    ErrBadFieldName,
+				"lUint32": ErrParse,
+			},
+		},
+		{
+			desc:   "huge index is not allocated",
+			msg:    &pb.Customer{},
+			values: url.Values{"orders[999999999].id": {"x"}, "name": {"n"}},
+			want:   &pb.Customer{Name: "n"},
+			errs:   map[string]ErrCode{"orders[999999999].id": ErrIndexOutOfRange},
+		},
+		{
+			desc:    "IgnoreUnknownKeys",
+			msg:     &pb.Kinds{},
+			values:  url.Values{"csrf": {"token"}, "vstring": {"hi"}},
+			options: []BindOption{IgnoreUnknownKeys()},
+			want:    &pb.Kinds{Vstring: "hi"},
+		},
+	}
+
+	for _, test := range tests {
+		err := BindForm(test.msg, test.values, test.options...)
+		switch {
+		case err == nil && len(test.errs) > 0:
+			t.Errorf("TestBindForm(%s): got err == nil, want err != nil", test.desc)
+		case err != nil && len(test.errs) == 0:
+			t.Errorf("TestBindForm(%s): got err == %s, want err == nil", test.desc, err)
+		case err != nil:
+			var fe FormErrors
+			if !errors.As(err, &fe) {
+				t.Errorf("TestBindForm(%s): got err type %T, want FormErrors", test.desc, err)
+				break
+			}
+			got := map[string]ErrCode{}
+			for k, e := range fe {
+				var ce Error
+				if errors.As(e, &ce) {
+					got[k] = ce.Code
+				}
+			}
+			if diff := cmp.Diff(test.errs, got); diff != "" {
+				t.Errorf("TestBindForm(%s): error codes -want/+got:\n%s", test.desc, diff)
+			}
+		}
+		if diff := cmp.Diff(test.want, test.msg, protocmp.Transform()); diff != "" {
+			t.Errorf("TestBindForm(%s): -want/+got:\n%s", test.desc, diff)
+		}
+	}
+}
+
+func TestBindFormOneof(t *testing.T) {
+	msg := &pb.Customer{Payment: &pb.Payment{Method: &pb.Payment_Voucher{Voucher: "free"}}}
+
+	var changes []OneofChange
+	err := BindForm(msg, url.Values{"payment.card.number": {"4111"}}, BindOnOneofChange(func(c OneofChange) {
+		changes = append(changes, c)
+	}))
+	if err != nil {
+		t.Fatalf("TestBindFormOneof: got err == %s, want err == nil", err)
+	}
+	want := []OneofChange{{Oneof: "payment.method", Cleared: "payment.voucher", Set: "payment.card"}}
+	if diff := cmp.Diff(want, changes); diff != "" {
+		t.Errorf("TestBindFormOneof: -want/+got:\n%s", diff)
+	}
+}
+
+func TestBindMultipartForm(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w := multipart.NewWriter(buf)
+	w.WriteField("vstring", "hello")
+	for _, file := range []struct{ key, name, content string }{
+		{"vbytes", "a.txt", "single"},
+		{"lBytes", "b.txt", "one"},
+		{"lBytes", "c.txt", "two"},
+		{"vint32", "d.txt", "not bytes"},
+	} {
+		fw, err := w.CreateFormFile(file.key, file.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fw.Write([]byte(file.content))
+	}
+	w.Close()
+
+	form, err := multipart.NewReader(buf, w.Boundary()).ReadForm(1 << 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer form.RemoveAll()
+
+	got := &pb.Kinds{}
+	err = BindMultipartForm(got, form)
+
+	var fe FormErrors
+	if !errors.As(err, &fe) || len(fe) != 1 || fe["vint32"] == nil {
+		t.Errorf("TestBindMultipartForm: got err == %v, want error for only form key vint32", err)
+	}
+	want := &pb.Kinds{
+		Vstring: "hello",
+		Vbytes:  []byte("single"),
+		LBytes:  [][]byte{[]byte("one"), []byte("two")},
+	}
+	if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+		t.Errorf("TestBindMultipartForm: -want/+got:\n%s", diff)
+	}
+}
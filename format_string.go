@@ -0,0 +1,28 @@
+// Code generated by "stringer -type=Format -trimprefix=Format"; DO NOT EDIT.
+
+package prototools
+
+import "strconv"
+
+func _() {
+	// An "invalid array index" compiler error signifies that the constant values have changed.
+	// Re-run the stringer command to generate them again.
+	var x [1]struct{}
+	_ = x[FormatUnknown-0]
+	_ = x[FormatWire-1]
+	_ = x[FormatJSON-2]
+	_ = x[FormatText-3]
+	_ = x[FormatYAML-4]
+}
+
+const _Format_name = "UnknownWireJSONTextYAML"
+
+var _Format_index = [...]uint8{0, 7, 11, 15, 19, 23}
+
+func (i Format) String() string {
+	idx := int(i) - 0
+	if i < 0 || idx >= len(_Format_index)-1 {
+		return "Format(" + strconv.FormatInt(int64(i), 10) + ")"
+	}
+	return _Format_name[_Format_index[idx]:_Format_index[idx+1]]
+}
@@ -0,0 +1,118 @@
+package prototools
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Wildcard is the selector that matches every entry in a repeated field or map, aka "orders[*]".
+const Wildcard = "*"
+
+// Match is a value found by GetAll().
+type Match struct {
+	// Path is the fqPath to the value with every wildcard replaced by the concrete index or key,
+	// aka "orders[1].lines[0].price". This can be passed to GetField() or UpdateProtoField().
+	Path string
+
+	FieldValue
+}
+
+/*
+GetAll is like GetField(), except that any selector can be a wildcard, "[*]", which matches
+every entry of a repeated field or every value of a map. It returns a Match for each value that
+was found, in order. List entries are in index order and map entries are sorted by key.
+
+	// Gets the price of every line in every order.
+	matches, err := GetAll(msg, "orders[*].lines[*].price")
+
+A path without wildcards returns a single Match, the same as GetField(). The path is checked against
+the message's descriptor, so a bad field name is an error even if a wildcard matched nothing. Fields after
+a google.protobuf.Any can only be checked against the messages that are found.
+*/
+func GetAll(msg proto.Message, fqPath string) ([]Match, error) {
+	fields := FQPathSplit(fqPath)
+	md := msg.ProtoReflect().Descriptor()
+	if _, err := checkPath(md, checkableFields(md, fields)); err != nil {
+		return nil, err
+	}
+
+	matches := []Match{}
+	if err := getAll(msg, fields, nil, &matches); err != nil {
+		return nil, err
+	}
+	return matches, nil
+}
+
+// getAll expands the first field in fields against msg and recurses on the rest. prefix is the concrete
+// path to msg.
+func getAll(msg proto.Message, fields []string, prefix []string, matches *[]Match) error {
+	pp, err := parsePart(fields[0])
+	if err != nil {
+		return err
+	}
+	if msg, err = unpackAny(msg, fields[0], strings.Join(prefix, ".")); err != nil {
+		return err
+	}
+
+	parts := []string{fields[0]}
+	if pp.hasSelector && pp.selector == Wildcard {
+		parts, err = expandWildcard(msg.ProtoReflect(), pp)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, part := range parts {
+		path := append(prefix[:len(prefix):len(prefix)], part)
+		fqPath := strings.Join(path, ".")
+
+		fv, err := partValue(msg, part, fqPath)
+		if err != nil {
+			return err
+		}
+		if len(fields) == 1 {
+			*matches = append(*matches, Match{Path: fqPath, FieldValue: fv})
+			continue
+		}
+
+		if fv.Kind != protoreflect.MessageKind {
+			return Errorf(ErrIntermediateNotMessage, "field(%s) should be a message, was a %s", fqPath, fv.Kind)
+		}
+		if err := notMessageErr(fv, fqPath); err != nil {
+			return err
+		}
+		if err := getAll(fv.Value.(proto.Message), fields[1:], path, matches); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// expandWildcard returns a concrete path part for every entry of the list or map that pp refers to.
+func expandWildcard(ref protoreflect.Message, pp pathPart) ([]string, error) {
+	fd := partField(ref.Descriptor(), pp.name)
+	if fd == nil {
+		return nil, Errorf(ErrBadFieldName, "field(%s) could not be found", pp.name)
+	}
+
+	switch {
+	case fd.IsList():
+		l := ref.Get(fd).List()
+		parts := make([]string, 0, l.Len())
+		for i := 0; i < l.Len(); i++ {
+			parts = append(parts, fmt.Sprintf("%s[%d]", pp.name, i))
+		}
+		return parts, nil
+	case fd.IsMap():
+		keys := sortedMapKeys(ref.Get(fd).Map())
+		parts := make([]string, 0, len(keys))
+		for _, k := range keys {
+			parts = append(parts, fmt.Sprintf("%s[%s]", pp.name, keySelector(k)))
+		}
+		return parts, nil
+	}
+	return nil, Errorf(ErrBadPath, "field(%s) is not a repeated field or map and cannot have a wildcard", pp.name)
+}
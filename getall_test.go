@@ -0,0 +1,115 @@
+package prototools
+
+import (
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+
+	pb "github.com/johnsiilver/prototools/sample"
+)
+
+func TestGetAll(t *testing.T) {
+	msg := newCustomer()
+	msg.Labels = map[string]string{"zone": "b", "env": "prod"}
+	msg.ById = map[int64]*pb.Order{
+		42: {Lines: []*pb.Line{{Sku: "sku42"}}},
+		7:  {Lines: []*pb.Line{{Sku: "sku7a"}, {Sku: "sku7b"}}},
+	}
+
+	type result struct {
+		Path  string
+		Value interface{}
+	}
+
+	tests := []struct {
+		desc   string
+		fqPath string
+		want   []result
+		err    bool
+	}{
+		{
+			desc:   "nested lists",
+			fqPath: "orders[*].lines[*].price",
+			want: []result{
+				{"orders[0].lines[0].price", 1.5},
+				{"orders[0].lines[1].price", 2.5},
+				{"orders[1].lines[0].price", 3.5},
+			},
+		},
+		{
+			desc:   "wildcard then index",
+			fqPath: "orders[*].lines[-1].sku",
+			want: []result{
+				{"orders[0].lines[-1].sku", "sku1"},
+				{"orders[1].lines[-1].sku", "sku2"},
+			},
+		},
+		{
+			desc:   "map values",
+			fqPath: "labels[*]",
+			want: []result{
+				{`labels["env"]`, "prod"},
+				{`labels["zone"]`, "b"},
+			},
+		},
+		{
+			desc:   "map of messages then list",
+			fqPath: "by_id[*].lines[*].sku",
+			want: []result{
+				{"by_id[7].lines[0].sku", "sku7a"},
+				{"by_id[7].lines[1].sku", "sku7b"},
+				{"by_id[42].lines[0].sku", "sku42"},
+			},
+		},
+		{
+			desc:   "no wildcard",
+			fqPath: "name",
+			want:   []result{{"name", "John"}},
+		},
+		{
+			desc:   "error: bad field after wildcard",
+			fqPath: "orders[*].what",
+			err:    true,
+		},
+		{
+			desc:   "error: wildcard on non-list",
+			fqPath: "name[*]",
+			err:    true,
+		},
+	}
+
+	for _, test := range tests {
+		matches, err := GetAll(msg, test.fqPath)
+		switch {
+		case err == nil && test.err:
+			t.Errorf("TestGetAll(%s): got err == nil, want err != nil", test.desc)
+			continue
+		case err != nil && !test.err:
+			t.Errorf("TestGetAll(%s): got err == %s, want err == nil", test.desc, err)
+			continue
+		case err != nil:
+			continue
+		}
+
+		got := []result{}
+		for _, m := range matches {
+			got = append(got, result{m.Path, m.Value})
+
+			fv, err := GetField(msg, m.Path)
+			if err != nil {
+				t.Errorf("TestGetAll(%s): GetField(%s) got err == %s, want err == nil", test.desc, m.Path, err)
+				continue
+			}
+			if diff := pretty.Compare(m.Value, fv.Value); diff != "" {
+				t.Errorf("TestGetAll(%s): GetField(%s) differs from match: -want/+got:\n%s", test.desc, m.Path, diff)
+			}
+		}
+		if diff := pretty.Compare(test.want, got); diff != "" {
+			t.Errorf("TestGetAll(%s): -want/+got:\n%s", test.desc, diff)
+		}
+	}
+
+	if matches, err := GetAll(&pb.Customer{}, "orders[*].lines[*].sku"); err != nil || len(matches) != 0 {
+		t.Errorf("TestGetAll(empty): got %d matches, err == %v, want 0 matches, err == nil", len(matches), err)
+	}
+}
@@ -0,0 +1,190 @@
+package prototools
+
+import (
+	"reflect"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// listValues converts values into values that can be stored in the repeated field at fqPath. This
+// only looks at msg's descriptor, so it does not change msg.
+func listValues(msg proto.Message, fqPath string, values []interface{}) ([]protoreflect.Value, error) {
+	fields := FQPathSplit(fqPath)
+	fd, err := checkPath(msg.ProtoReflect().Descriptor(), fields)
+	if err != nil {
+		return nil, err
+	}
+	if pp, _ := parsePart(fields[len(fields)-1]); pp.hasSelector {
+		return nil, Errorf(ErrBadPath, "field(%s) must be a repeated field without a selector", fqPath)
+	}
+	if !fd.IsList() {
+		return nil, Errorf(ErrBadPath, "field(%s) is not a repeated field", fqPath)
+	}
+
+	vals := make([]protoreflect.Value, 0, len(values))
+	for _, value := range values {
+		val, err := protoValue(fd, value)
+		if err != nil {
+			return nil, err
+		}
+		vals = append(vals, val)
+	}
+	return vals, nil
+}
+
+// listField returns the list at fqPath. listValues() must have been called first to validate fqPath.
+func listField(msg proto.Message, fqPath string, createMessages bool) (protoreflect.List, error) {
+	m, fd, _, err := lastField(msg, fqPath, createMessages, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return m.Mutable(fd).List(), nil
+}
+
+// AppendField appends values to the repeated field at fqPath. Intermediate messages that are not set are
+// created. Each value follows the same rules as UpdateProtoField(). If any value cannot be stored in the
+// field, nothing is appended.
+func AppendField(msg proto.Message, fqPath string, values ...interface{}) error {
+	vals, err := listValues(msg, fqPath, values)
+	if err != nil {
+		return err
+	}
+	l, err := listField(msg, fqPath, true)
+	if err != nil {
+		return err
+	}
+	for _, val := range vals {
+		l.Append(val)
+	}
+	return nil
+}
+
+// InsertField inserts values into the repeated field at fqPath so that the first value is at index "at".
+// "at" can be the length of the list, which appends the values, or negative, which counts back from the end
+// of the list, so -1 inserts before the last entry. Intermediate messages that are not set are created.
+func InsertField(msg proto.Message, fqPath string, at int, values ...interface{}) error {
+	vals, err := listValues(msg, fqPath, values)
+	if err != nil {
+		return err
+	}
+	l, err := listField(msg, fqPath, true)
+	if err != nil {
+		return err
+	}
+
+	i := l.Len()
+	if at != l.Len() {
+		var ok bool
+		i, ok = listIndex(at, l.Len())
+		if !ok {
+			return Errorf(ErrIndexOutOfRange, "field(%s) has %d entries, index %d is out of range", fqPath, l.Len(), at)
+		}
+	}
+
+	insertList(l, i, vals)
+	return nil
+}
+
+// insertList inserts vals into l so that the first value is at index i. i must be in the range [0, l.Len()].
+func insertList(l protoreflect.List, i int, vals []protoreflect.Value) {
+	if len(vals) == 0 {
+		return
+	}
+
+	// protoreflect.List has no insert, so we grow the list and shift the tail down.
+	oldLen := l.Len()
+	for range vals {
+		l.Append(l.NewElement())
+	}
+	for x := oldLen - 1; x >= i; x-- {
+		l.Set(x+len(vals), l.Get(x))
+	}
+	for x, val := range vals {
+		l.Set(i+x, val)
+	}
+}
+
+// RemoveField removes the entry at index from the repeated field at fqPath. A negative index counts
+// back from the end of the list.
+func RemoveField(msg proto.Message, fqPath string, index int) error {
+	if _, err := listValues(msg, fqPath, nil); err != nil {
+		return err
+	}
+	l, err := listField(msg, fqPath, false)
+	if err != nil {
+		return err
+	}
+	i, ok := listIndex(index, l.Len())
+	if !ok {
+		return Errorf(ErrIndexOutOfRange, "field(%s) has %d entries, index %d is out of range", fqPath, l.Len(), index)
+	}
+	removeList(l, i)
+	return nil
+}
+
+// removeList removes the entry at index i from l. i must be in range.
+func removeList(l protoreflect.List, i int) {
+	for x := i; x < l.Len()-1; x++ {
+		l.Set(x, l.Get(x+1))
+	}
+	l.Truncate(l.Len() - 1)
+}
+
+// SetList replaces the content of the repeated field at fqPath with values, which must be a slice.
+// Each entry follows the same rules as UpdateProtoField(). Intermediate messages that are not set are created.
+// If any entry cannot be stored in the field, the field is not changed.
+func SetList(msg proto.Message, fqPath string, values interface{}) error {
+	rv := reflect.ValueOf(values)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return Errorf(ErrTypeMismatch, "field(%s) can only be set to a slice, you sent a %T", fqPath, values)
+	}
+	entries := make([]interface{}, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		entries[i] = rv.Index(i).Interface()
+	}
+
+	vals, err := listValues(msg, fqPath, entries)
+	if err != nil {
+		return err
+	}
+	l, err := listField(msg, fqPath, true)
+	if err != nil {
+		return err
+	}
+	l.Truncate(0)
+	for _, val := range vals {
+		l.Append(val)
+	}
+	return nil
+}
+
+// ClearField clears the field at fqPath, which can be any kind of field. If the last field has a map key
+// selector, aka `labels["env"]`, only that key is removed. Use RemoveField() to remove an entry of a
+// repeated field. If an intermediate message is not set, there is nothing to clear and this returns nil.
+func ClearField(msg proto.Message, fqPath string) error {
+	var anys anyStack
+	m, fd, pp, err := lastField(msg, fqPath, false, nil, &anys)
+	if err != nil {
+		if e, ok := err.(Error); ok && e.Code == ErrIntermdiateNotSet {
+			return nil
+		}
+		return err
+	}
+
+	if !pp.hasSelector {
+		m.Clear(fd)
+		return anys.repack()
+	}
+	if !fd.IsMap() {
+		return Errorf(ErrBadPath, "field(%s) can only have a selector if it is a map", fqPath)
+	}
+	k, err := pp.mapKey(fd)
+	if err != nil {
+		return err
+	}
+	if m.Has(fd) {
+		m.Mutable(fd).Map().Clear(k)
+	}
+	return anys.repack()
+}
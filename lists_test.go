@@ -0,0 +1,202 @@
+package prototools
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	pb "github.com/johnsiilver/prototools/sample"
+)
+
+func TestListMutations(t *testing.T) {
+	tests := []struct {
+		desc   string
+		start  func() *pb.Customer
+		mutate func(c *pb.Customer) error
+		want   func(c *pb.Customer)
+		err    bool
+	}{
+		{
+			desc:   "AppendField scalars",
+			mutate: func(c *pb.Customer) error { return AppendField(c, "tags", "a", "b") },
+			want:   func(c *pb.Customer) { c.Tags = append(c.Tags, "a", "b") },
+		},
+		{
+			desc:   "AppendField message in nested list",
+			mutate: func(c *pb.Customer) error { return AppendField(c, "orders[1].lines", &pb.Line{Sku: "new"}) },
+			want:   func(c *pb.Customer) { c.Orders[1].Lines = append(c.Orders[1].Lines, &pb.Line{Sku: "new"}) },
+		},
+		{
+			desc:   "AppendField creates map entries",
+			start:  func() *pb.Customer { return &pb.Customer{} },
+			mutate: func(c *pb.Customer) error { return AppendField(c, "by_id[1].lines", &pb.Line{Sku: "new"}) },
+			want: func(c *pb.Customer) {
+				c.ById = map[int64]*pb.Order{1: {Lines: []*pb.Line{{Sku: "new"}}}}
+			},
+		},
+		{
+			desc:   "error: AppendField wrong type",
+			mutate: func(c *pb.Customer) error { return AppendField(c, "tags", "a", 1) },
+			err:    true,
+		},
+		{
+			desc:   "error: AppendField wrong message type",
+			mutate: func(c *pb.Customer) error { return AppendField(c, "orders", &pb.Line{}) },
+			err:    true,
+		},
+		{
+			desc:   "error: AppendField not a list",
+			mutate: func(c *pb.Customer) error { return AppendField(c, "name", "a") },
+			err:    true,
+		},
+		{
+			desc:   "InsertField at start",
+			mutate: func(c *pb.Customer) error { return InsertField(c, "tags", 0, "a", "b") },
+			want:   func(c *pb.Customer) { c.Tags = []string{"a", "b", "new", "vip"} },
+		},
+		{
+			desc:   "InsertField before last",
+			mutate: func(c *pb.Customer) error { return InsertField(c, "tags", -1, "a") },
+			want:   func(c *pb.Customer) { c.Tags = []string{"new", "a", "vip"} },
+		},
+		{
+			desc:   "InsertField at end",
+			mutate: func(c *pb.Customer) error { return InsertField(c, "tags", 2, "a") },
+			want:   func(c *pb.Customer) { c.Tags = []string{"new", "vip", "a"} },
+		},
+		{
+			desc:   "error: InsertField out of range",
+			mutate: func(c *pb.Customer) error { return InsertField(c, "tags", 3, "a") },
+			err:    true,
+		},
+		{
+			desc:   "RemoveField first",
+			mutate: func(c *pb.Customer) error { return RemoveField(c, "orders[0].lines", 0) },
+			want:   func(c *pb.Customer) { c.Orders[0].Lines = c.Orders[0].Lines[1:] },
+		},
+		{
+			desc:   "RemoveField last",
+			mutate: func(c *pb.Customer) error { return RemoveField(c, "tags", -1) },
+			want:   func(c *pb.Customer) { c.Tags = []string{"new"} },
+		},
+		{
+			desc:   "error: RemoveField out of range",
+			mutate: func(c *pb.Customer) error { return RemoveField(c, "tags", 2) },
+			err:    true,
+		},
+		{
+			desc:   "SetList",
+			mutate: func(c *pb.Customer) error { return SetList(c, "tags", []string{"x", "y", "z"}) },
+			want:   func(c *pb.Customer) { c.Tags = []string{"x", "y", "z"} },
+		},
+		{
+			desc:   "error: SetList not a list",
+			start:  func() *pb.Customer { return &pb.Customer{} },
+			mutate: func(c *pb.Customer) error { return SetList(c, "payment.card.number", []string{"x"}) },
+			err:    true,
+		},
+		{
+			desc:   "error: SetList not a slice",
+			mutate: func(c *pb.Customer) error { return SetList(c, "tags", "x") },
+			err:    true,
+		},
+		{
+			desc:   "ClearField list",
+			mutate: func(c *pb.Customer) error { return ClearField(c, "orders") },
+			want:   func(c *pb.Customer) { c.Orders = nil },
+		},
+		{
+			desc:   "ClearField scalar",
+			mutate: func(c *pb.Customer) error { return ClearField(c, "orders[0].id") },
+			want:   func(c *pb.Customer) { c.Orders[0].Id = "" },
+		},
+		{
+			desc:   "ClearField map key",
+			start:  func() *pb.Customer { return &pb.Customer{Labels: map[string]string{"a": "1", "b": "2"}} },
+			mutate: func(c *pb.Customer) error { return ClearField(c, `labels["a"]`) },
+			want:   func(c *pb.Customer) { delete(c.Labels, "a") },
+		},
+		{
+			desc:   "ClearField intermediate not set",
+			mutate: func(c *pb.Customer) error { return ClearField(c, "payment.card.number") },
+			want:   func(c *pb.Customer) {},
+		},
+	}
+
+	for _, test := range tests {
+		start := newCustomer
+		if test.start != nil {
+			start = test.start
+		}
+
+		got := start()
+		err := test.mutate(got)
+		switch {
+		case err == nil && test.err:
+			t.Errorf("TestListMutations(%s): got err == nil, want err != nil", test.desc)
+			continue
+		case err != nil && !test.err:
+			t.Errorf("TestListMutations(%s): got err == %s, want err == nil", test.desc, err)
+			continue
+		case err != nil:
+			if diff := cmp.Diff(start(), got, protocmp.Transform()); diff != "" {
+				t.Errorf("TestListMutations(%s): message changed on error: -want/+got:\n%s", test.desc, diff)
+			}
+			continue
+		}
+
+		want := start()
+		test.want(want)
+		if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+			t.Errorf("TestListMutations(%s): -want/+got:\n%s", test.desc, diff)
+		}
+	}
+}
+
+func TestListMutationsIntermediates(t *testing.T) {
+	tests := []struct {
+		desc   string
+		mutate func(f *pb.Folder) error
+		want   *pb.Folder
+		err    bool
+	}{
+		{
+			desc:   "AppendField creates intermediate messages",
+			mutate: func(f *pb.Folder) error { return AppendField(f, "parent.notes", "a") },
+			want:   &pb.Folder{Parent: &pb.Folder{Notes: []string{"a"}}},
+		},
+		{
+			desc:   "error: AppendField bad value does not create intermediate messages",
+			mutate: func(f *pb.Folder) error { return AppendField(f, "parent.notes", 1) },
+			err:    true,
+		},
+		{
+			desc:   "SetList creates intermediate messages",
+			mutate: func(f *pb.Folder) error { return SetList(f, "parent.parent.notes", []string{"x"}) },
+			want:   &pb.Folder{Parent: &pb.Folder{Parent: &pb.Folder{Notes: []string{"x"}}}},
+		},
+	}
+
+	for _, test := range tests {
+		got := &pb.Folder{}
+		err := test.mutate(got)
+		switch {
+		case err == nil && test.err:
+			t.Errorf("TestListMutationsIntermediates(%s): got err == nil, want err != nil", test.desc)
+			continue
+		case err != nil && !test.err:
+			t.Errorf("TestListMutationsIntermediates(%s): got err == %s, want err == nil", test.desc, err)
+			continue
+		case err != nil:
+			if diff := cmp.Diff(&pb.Folder{}, got, protocmp.Transform()); diff != "" {
+				t.Errorf("TestListMutationsIntermediates(%s): message changed on error: -want/+got:\n%s", test.desc, diff)
+			}
+			continue
+		}
+
+		if diff := cmp.Diff(test.want, got, protocmp.Transform()); diff != "" {
+			t.Errorf("TestListMutationsIntermediates(%s): -want/+got:\n%s", test.desc, diff)
+		}
+	}
+}
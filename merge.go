@@ -0,0 +1,133 @@
+package prototools
+
+import (
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// Conflict details a field that was changed differently by both sides of a Merge3().
+type Conflict struct {
+	// FQPath is the path to the field that both sides changed.
+	FQPath string
+	// Base, Ours and Theirs are the values of the field in each message, nil if the field is not set.
+	// The types are the same as FieldValue.Value, so a repeated field is a slice.
+	Base, Ours, Theirs interface{}
+}
+
+/*
+Merge3 does a three-way merge of ours and theirs, which are both edits of base. Changes that only one side made
+are applied to a copy of base, which is returned as merged. If both sides made the same change, it is applied once.
+
+Changes are found with FieldMaskFromDiff(), which sets how fine grained a merge can be: fields in messages are merged
+one by one, entries in maps are merged by key and repeated fields are merged as a whole. So if both sides change the
+same repeated field in different ways, that is a conflict, even if they changed different entries.
+
+If both sides change a field in different ways, that is a conflict and merged has the value from ours.
+The conflicts are returned in the order of the changes in theirs. base can be nil, which is the same as an
+empty message. It is an error if the messages are not all the same type.
+*/
+func Merge3(base, ours, theirs proto.Message) (merged proto.Message, conflicts []Conflict, err error) {
+	if base == nil {
+		base = ours.ProtoReflect().New().Interface()
+	}
+
+	oursMask, err := FieldMaskFromDiff(base, ours)
+	if err != nil {
+		return nil, nil, err
+	}
+	theirsMask, err := FieldMaskFromDiff(base, theirs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	merged = proto.Clone(base)
+	if err := applyPaths(merged, ours, oursMask.Paths); err != nil {
+		return nil, nil, err
+	}
+
+	seen := map[string]bool{}
+	var apply []string
+	for _, tp := range theirsMask.Paths {
+		conflict := false
+		for _, op := range oursMask.Paths {
+			p, ok := overlap(op, tp)
+			if !ok {
+				continue
+			}
+			same, err := equalAt(ours, theirs, p)
+			if err != nil {
+				return nil, nil, err
+			}
+			if same {
+				continue
+			}
+			conflict = true
+			if seen[p] {
+				continue
+			}
+			seen[p] = true
+			conflicts = append(conflicts, Conflict{
+				FQPath: p,
+				Base:   valueAt(base, p),
+				Ours:   valueAt(ours, p),
+				Theirs: valueAt(theirs, p),
+			})
+		}
+		if !conflict {
+			apply = append(apply, tp)
+		}
+	}
+
+	if err := applyPaths(merged, theirs, apply); err != nil {
+		return nil, nil, err
+	}
+	return merged, conflicts, nil
+}
+
+// applyPaths is ApplyFieldMask() with paths, except that no paths does nothing.
+func applyPaths(dst, src proto.Message, paths []string) error {
+	if len(paths) == 0 {
+		return nil
+	}
+	return ApplyFieldMask(dst, src, &fieldmaskpb.FieldMask{Paths: paths})
+}
+
+// overlap returns the shorter of two field mask paths if one is the other or is inside of the other.
+func overlap(a, b string) (string, bool) {
+	if len(b) < len(a) {
+		a, b = b, a
+	}
+	if a == b || strings.HasPrefix(b, a+".") || strings.HasPrefix(b, a+"[") {
+		return a, true
+	}
+	return "", false
+}
+
+// equalAt returns true if a and b have the same value at the field mask path p.
+func equalAt(a, b proto.Message, p string) (bool, error) {
+	mask := &fieldmaskpb.FieldMask{Paths: []string{p}}
+	fa, fb := a.ProtoReflect().New().Interface(), b.ProtoReflect().New().Interface()
+	if err := ApplyFieldMask(fa, a, mask); err != nil {
+		return false, err
+	}
+	if err := ApplyFieldMask(fb, b, mask); err != nil {
+		return false, err
+	}
+	return proto.Equal(fa, fb), nil
+}
+
+// valueAt returns the value at the field mask path p in msg or nil if it is not set.
+func valueAt(msg proto.Message, p string) interface{} {
+	fields := FQPathSplit(p)
+	pp, _ := parsePart(fields[len(fields)-1])
+	if _, ok, err := maskValue(msg, fields, pp); err != nil || !ok {
+		return nil
+	}
+	fv, err := GetField(msg, p)
+	if err != nil {
+		return nil
+	}
+	return fv.Value
+}
@@ -0,0 +1,88 @@
+package prototools
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	pb "github.com/johnsiilver/prototools/sample"
+)
+
+func TestMerge3(t *testing.T) {
+	base := &pb.Customer{
+		Name:    "base",
+		Tags:    []string{"a"},
+		Labels:  map[string]string{"env": "dev", "team": "x"},
+		Payment: &pb.Payment{Id: "p1"},
+	}
+
+	tests := []struct {
+		desc         string
+		ours, theirs *pb.Customer
+		want         *pb.Customer
+		conflicts    []Conflict
+	}{
+		{
+			desc:   "changes to different fields",
+			ours:   &pb.Customer{Name: "ours", Tags: []string{"a"}, Labels: map[string]string{"env": "dev", "team": "x"}, Payment: &pb.Payment{Id: "p1"}},
+			theirs: &pb.Customer{Name: "base", Tags: []string{"a", "b"}, Labels: map[string]string{"env": "prod", "team": "x"}, Payment: &pb.Payment{Id: "p1"}},
+			want:   &pb.Customer{Name: "ours", Tags: []string{"a", "b"}, Labels: map[string]string{"env": "prod", "team": "x"}, Payment: &pb.Payment{Id: "p1"}},
+		},
+		{
+			desc:   "different map keys and nested fields",
+			ours:   &pb.Customer{Name: "base", Tags: []string{"a"}, Labels: map[string]string{"env": "dev"}, Payment: &pb.Payment{Id: "p1", Method: &pb.Payment_Voucher{Voucher: "n"}}},
+			theirs: &pb.Customer{Name: "base", Tags: []string{"a"}, Labels: map[string]string{"env": "dev", "team": "x", "new": "y"}, Payment: &pb.Payment{Id: "p2"}},
+			want:   &pb.Customer{Name: "base", Tags: []string{"a"}, Labels: map[string]string{"env": "dev", "new": "y"}, Payment: &pb.Payment{Id: "p2", Method: &pb.Payment_Voucher{Voucher: "n"}}},
+		},
+		{
+			desc:   "same change on both sides",
+			ours:   &pb.Customer{Name: "same", Tags: []string{"a"}, Labels: map[string]string{"env": "dev", "team": "x"}, Payment: &pb.Payment{Id: "p1"}},
+			theirs: &pb.Customer{Name: "same", Tags: []string{"a"}, Labels: map[string]string{"env": "dev", "team": "x"}, Payment: &pb.Payment{Id: "p1"}},
+			want:   &pb.Customer{Name: "same", Tags: []string{"a"}, Labels: map[string]string{"env": "dev", "team": "x"}, Payment: &pb.Payment{Id: "p1"}},
+		},
+		{
+			desc:   "conflicts",
+			ours:   &pb.Customer{Name: "ours", Tags: []string{"o"}, Labels: map[string]string{"env": "ours", "team": "x"}, Payment: &pb.Payment{Id: "p1"}},
+			theirs: &pb.Customer{Name: "theirs", Tags: []string{"t"}, Labels: map[string]string{"team": "x"}},
+			want:   &pb.Customer{Name: "ours", Tags: []string{"o"}, Labels: map[string]string{"env": "ours", "team": "x"}},
+			conflicts: []Conflict{
+				{FQPath: "name", Base: "base", Ours: "ours", Theirs: "theirs"},
+				{FQPath: "tags", Base: []string{"a"}, Ours: []string{"o"}, Theirs: []string{"t"}},
+				{FQPath: `labels["env"]`, Base: "dev", Ours: "ours"},
+			},
+		},
+		{
+			desc:   "message removed on one side and changed on the other",
+			ours:   &pb.Customer{Name: "base", Tags: []string{"a"}, Labels: map[string]string{"env": "dev", "team": "x"}, Payment: &pb.Payment{Id: "p2"}},
+			theirs: &pb.Customer{Name: "base", Tags: []string{"a"}, Labels: map[string]string{"env": "dev", "team": "x"}},
+			want:   &pb.Customer{Name: "base", Tags: []string{"a"}, Labels: map[string]string{"env": "dev", "team": "x"}, Payment: &pb.Payment{Id: "p2"}},
+			conflicts: []Conflict{
+				{FQPath: "payment", Base: &pb.Payment{Id: "p1"}, Ours: &pb.Payment{Id: "p2"}},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		got, conflicts, err := Merge3(base, test.ours, test.theirs)
+		if err != nil {
+			t.Errorf("TestMerge3(%s): got err == %s, want err == nil", test.desc, err)
+			continue
+		}
+		if diff := cmp.Diff(test.want, got, protocmp.Transform()); diff != "" {
+			t.Errorf("TestMerge3(%s): merged -want/+got:\n%s", test.desc, diff)
+		}
+		if diff := cmp.Diff(test.conflicts, conflicts, protocmp.Transform()); diff != "" {
+			t.Errorf("TestMerge3(%s): conflicts -want/+got:\n%s", test.desc, diff)
+		}
+	}
+
+	if !proto.Equal(base, &pb.Customer{Name: "base", Tags: []string{"a"}, Labels: map[string]string{"env": "dev", "team": "x"}, Payment: &pb.Payment{Id: "p1"}}) {
+		t.Errorf("TestMerge3: base was changed")
+	}
+
+	if _, _, err := Merge3(&pb.Layer0{}, &pb.Layer0{}, &pb.Layer1{}); err == nil {
+		t.Errorf("TestMerge3(different types): got err == nil, want err != nil")
+	}
+}
@@ -0,0 +1,88 @@
+package prototools
+
+import (
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// OneofInfo details the oneof that a field is a member of.
+type OneofInfo struct {
+	// Name is the name of the oneof as seen in the proto file.
+	Name string
+	// Active is set if this field is the member of the oneof that is set.
+	Active bool
+	// Desc is the descriptor for the oneof.
+	Desc protoreflect.OneofDescriptor
+}
+
+// oneofInfo returns the OneofInfo for fd in ref. If fd is not a member of a oneof, this returns nil.
+// proto3 optional fields are in a synthetic oneof, which we do not count as a oneof.
+func oneofInfo(ref protoreflect.Message, fd protoreflect.FieldDescriptor) *OneofInfo {
+	od := fd.ContainingOneof()
+	if od == nil || od.IsSynthetic() {
+		return nil
+	}
+	return &OneofInfo{
+		Name:   string(od.Name()),
+		Active: ref.WhichOneof(od) == fd,
+		Desc:   od,
+	}
+}
+
+// OneofChange details a change in which member of a oneof is set.
+type OneofChange struct {
+	// Oneof is the fqPath to the oneof, aka "payment.method".
+	Oneof string
+	// Cleared is the fqPath to the member that was set and has been cleared.
+	Cleared string
+	// Set is the fqPath to the member that is now set.
+	Set string
+}
+
+// clearOneof clears any member of fd's oneof that is set in ref and is not fd. fqPath is the path to fd.
+// If a member was cleared, this returns the change and true.
+func clearOneof(ref protoreflect.Message, fd protoreflect.FieldDescriptor, fqPath string) (OneofChange, bool) {
+	od := fd.ContainingOneof()
+	if od == nil || od.IsSynthetic() {
+		return OneofChange{}, false
+	}
+	current := ref.WhichOneof(od)
+	if current == nil || current == fd {
+		return OneofChange{}, false
+	}
+	ref.Clear(current)
+
+	prefix := ""
+	if i := strings.LastIndex(fqPath, "."); i != -1 {
+		prefix = fqPath[:i+1]
+	}
+	return OneofChange{
+		Oneof:   prefix + string(od.Name()),
+		Cleared: prefix + string(current.Name()),
+		Set:     fqPath,
+	}, true
+}
+
+// WhichOneof returns the proto name of the member of a oneof that is set. fqPath is the path to
+// the oneof, where the last part is the name of the oneof, aka "payment.method". If no member
+// is set, this returns an empty string.
+func WhichOneof(msg proto.Message, fqPath string) (string, error) {
+	fields := FQPathSplit(fqPath)
+	msg, err := walkMessages(msg, fields[0:len(fields)-1])
+	if err != nil {
+		return "", err
+	}
+
+	ref := msg.ProtoReflect()
+	od := ref.Descriptor().Oneofs().ByName(protoreflect.Name(fields[len(fields)-1]))
+	if od == nil || od.IsSynthetic() {
+		return "", Errorf(ErrBadFieldName, "oneof(%s) could not be found", fqPath)
+	}
+	fd := ref.WhichOneof(od)
+	if fd == nil {
+		return "", nil
+	}
+	return string(fd.Name()), nil
+}
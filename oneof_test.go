@@ -0,0 +1,147 @@
+package prototools
+
+import (
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+
+	pb "github.com/johnsiilver/prototools/sample"
+)
+
+func TestOneofFieldValue(t *testing.T) {
+	msg := &pb.Customer{
+		Payment: &pb.Payment{Id: "pay", Method: &pb.Payment_Voucher{Voucher: "free"}},
+	}
+
+	tests := []struct {
+		desc   string
+		fqPath string
+		want   *OneofInfo
+	}{
+		{desc: "not a oneof", fqPath: "payment.id"},
+		{desc: "active member", fqPath: "payment.voucher", want: &OneofInfo{Name: "method", Active: true}},
+		{desc: "inactive member", fqPath: "payment.credits", want: &OneofInfo{Name: "method"}},
+	}
+
+	for _, test := range tests {
+		fv, err := GetField(msg, test.fqPath)
+		if err != nil {
+			t.Errorf("TestOneofFieldValue(%s): got err == %s, want err == nil", test.desc, err)
+			continue
+		}
+		switch {
+		case test.want == nil && fv.Oneof != nil:
+			t.Errorf("TestOneofFieldValue(%s): got Oneof == %+v, want nil", test.desc, fv.Oneof)
+		case test.want == nil:
+		case fv.Oneof == nil:
+			t.Errorf("TestOneofFieldValue(%s): got Oneof == nil, want %+v", test.desc, test.want)
+		case fv.Oneof.Name != test.want.Name || fv.Oneof.Active != test.want.Active:
+			t.Errorf("TestOneofFieldValue(%s): got Oneof == %+v, want %+v", test.desc, fv.Oneof, test.want)
+		}
+	}
+}
+
+func TestWhichOneof(t *testing.T) {
+	tests := []struct {
+		desc   string
+		msg    *pb.Customer
+		fqPath string
+		want   string
+		err    bool
+	}{
+		{
+			desc:   "set",
+			msg:    &pb.Customer{Payment: &pb.Payment{Method: &pb.Payment_Credits{Credits: 10}}},
+			fqPath: "payment.method",
+			want:   "credits",
+		},
+		{
+			desc:   "not set",
+			msg:    &pb.Customer{Payment: &pb.Payment{}},
+			fqPath: "payment.method",
+			want:   "",
+		},
+		{
+			desc:   "intermediate not set",
+			msg:    &pb.Customer{},
+			fqPath: "payment.method",
+			want:   "",
+		},
+		{
+			desc:   "error: not a oneof",
+			msg:    &pb.Customer{},
+			fqPath: "payment.id",
+			err:    true,
+		},
+	}
+
+	for _, test := range tests {
+		got, err := WhichOneof(test.msg, test.fqPath)
+		switch {
+		case err == nil && test.err:
+			t.Errorf("TestWhichOneof(%s): got err == nil, want err != nil", test.desc)
+			continue
+		case err != nil && !test.err:
+			t.Errorf("TestWhichOneof(%s): got err == %s, want err == nil", test.desc, err)
+			continue
+		case err != nil:
+			continue
+		}
+		if got != test.want {
+			t.Errorf("TestWhichOneof(%s): got %q, want %q", test.desc, got, test.want)
+		}
+	}
+}
+
+func TestGetFieldsSkipUnsetOneofs(t *testing.T) {
+	msg := &pb.Payment{Id: "pay", Method: &pb.Payment_Credits{Credits: 10}}
+
+	fvs, err := GetFields(msg, "", SkipUnsetOneofs())
+	if err != nil {
+		t.Fatalf("TestGetFieldsSkipUnsetOneofs: got err == %s, want err == nil", err)
+	}
+	got := []string{}
+	for _, fv := range fvs {
+		got = append(got, string(fv.FieldDesc.Name()))
+	}
+	if diff := pretty.Compare([]string{"id", "credits"}, got); diff != "" {
+		t.Errorf("TestGetFieldsSkipUnsetOneofs: -want/+got:\n%s", diff)
+	}
+}
+
+func TestUpdateProtoFieldOneof(t *testing.T) {
+	msg := &pb.Customer{Payment: &pb.Payment{Method: &pb.Payment_Voucher{Voucher: "free"}}}
+
+	changes := []OneofChange{}
+	report := OnOneofChange(func(c OneofChange) { changes = append(changes, c) })
+
+	if err := UpdateProtoField(msg, "payment.voucher", "cheap", report); err != nil {
+		t.Fatalf("TestUpdateProtoFieldOneof(same member): got err == %s, want err == nil", err)
+	}
+	if len(changes) != 0 {
+		t.Errorf("TestUpdateProtoFieldOneof(same member): got changes %+v, want none", changes)
+	}
+
+	if err := UpdateProtoField(msg, "payment.credits", int64(5), report); err != nil {
+		t.Fatalf("TestUpdateProtoFieldOneof(new member): got err == %s, want err == nil", err)
+	}
+	want := []OneofChange{{Oneof: "payment.method", Cleared: "payment.voucher", Set: "payment.credits"}}
+	if diff := pretty.Compare(want, changes); diff != "" {
+		t.Errorf("TestUpdateProtoFieldOneof(new member): -want/+got:\n%s", diff)
+	}
+	if msg.Payment.GetCredits() != 5 || msg.Payment.GetVoucher() != "" {
+		t.Errorf("TestUpdateProtoFieldOneof(new member): got %v, want credits == 5", msg.Payment)
+	}
+	// Creating an intermediate message that is a member of the oneof also clears the member that is set.
+	changes = changes[:0]
+	if err := UpdateProtoField(msg, "payment.card.number", "4111", report, CreateIntermediates()); err != nil {
+		t.Fatalf("TestUpdateProtoFieldOneof(intermediate member): got err == %s, want err == nil", err)
+	}
+	want = []OneofChange{{Oneof: "payment.method", Cleared: "payment.credits", Set: "payment.card"}}
+	if diff := pretty.Compare(want, changes); diff != "" {
+		t.Errorf("TestUpdateProtoFieldOneof(intermediate member): -want/+got:\n%s", diff)
+	}
+	if msg.Payment.GetCard().GetNumber() != "4111" || msg.Payment.GetCredits() != 0 {
+		t.Errorf("TestUpdateProtoFieldOneof(intermediate member): got %v, want card number == 4111", msg.Payment)
+	}
+}
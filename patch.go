@@ -0,0 +1,688 @@
+package prototools
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+/*
+ApplyJSONMergePatch applies an RFC 7386 JSON merge patch to msg. Keys in the patch can be the proto name or the
+JSON name of a field. A null clears the field. An object merges into a message or map field, with a null
+for a map key removing that key. Anything else replaces the field, including arrays, which replace all of a
+repeated field.
+
+Values are stored with the same rules as UpdateProtoField(), so a number that overflows a field is an error
+instead of being truncated. Strings are parsed with the same rules as SetFieldFromString(), which means
+int64 values, enum names, bytes in base64 and timestamps in RFC 3339 can be strings as protojson writes them.
+
+The patch is applied to a copy of msg, so if this returns an error msg is unchanged.
+*/
+func ApplyJSONMergePatch(msg proto.Message, patch []byte) error {
+	patched := proto.Clone(msg)
+	if err := mergePatch(patched.ProtoReflect(), patch, ""); err != nil {
+		return err
+	}
+	proto.Reset(msg)
+	proto.Merge(msg, patched)
+	return nil
+}
+
+/*
+ApplyJSONPatch applies an RFC 6902 JSON Patch to msg. The "add", "remove", "replace", "move", "copy" and
+"test" operations are supported.
+
+Paths are JSON pointers (RFC 6901), where each field can be the proto name or the JSON name, so "/layer1/vstring"
+and "/lineItems/0/sku" are both valid. Entries of a repeated field are selected by index, with "-" being the
+end of the list for "add". Entries of maps are selected by key. A field in a message that is not set cannot be
+the target of a path, add the message first. Values follow the same rules as ApplyJSONMergePatch(). A null
+value clears the field.
+
+Operations are applied in order to a copy of msg, so if any operation fails, msg is unchanged. The error says
+which operation failed. A "test" that fails returns an error with the code ErrPatchTestFailed.
+*/
+func ApplyJSONPatch(msg proto.Message, patch []byte) error {
+	var ops []patchOp
+	if err := json.Unmarshal(patch, &ops); err != nil {
+		return Errorf(ErrParse, "JSON patch must be an array of operations: %s", err)
+	}
+
+	patched := proto.Clone(msg)
+	for i, op := range ops {
+		if err := applyOp(patched, op); err != nil {
+			e, ok := err.(Error)
+			if !ok {
+				e = Error{Code: ErrUnknown, Msg: err.Error()}
+			}
+			return Errorf(e.Code, "patch operation %d(%s %s): %s", i, op.Op, op.Path, e.Msg)
+		}
+	}
+	proto.Reset(msg)
+	proto.Merge(msg, patched)
+	return nil
+}
+
+// patchOp is a single JSON Patch operation.
+type patchOp struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from"`
+	Value json.RawMessage `json:"value"`
+}
+
+// applyOp applies a single JSON Patch operation to msg.
+func applyOp(msg proto.Message, op patchOp) error {
+	md := msg.ProtoReflect().Descriptor()
+	p, err := resolvePointer(md, op.Path)
+	if err != nil {
+		return err
+	}
+
+	switch op.Op {
+	case "add", "replace", "test":
+		if op.Value == nil {
+			return Errorf(ErrParse, "operation must have a value")
+		}
+		// A null for a field that isn't an entry in a list or map means the field is not set.
+		if jsonKind(op.Value) == 'n' && !p.root && !p.selector && !p.appendEntry {
+			if op.Op != "test" {
+				return removePointer(msg, p)
+			}
+			tested := proto.Clone(msg)
+			if err := removePointer(tested, p); err != nil {
+				return err
+			}
+			return testEqual(msg, tested, op)
+		}
+	}
+
+	switch op.Op {
+	case "add":
+		return addPointer(msg, p, jsonConv(op.Value), false)
+	case "replace":
+		return addPointer(msg, p, jsonConv(op.Value), true)
+	case "remove":
+		return removePointer(msg, p)
+	case "copy", "move":
+		from, err := resolvePointer(md, op.From)
+		if err != nil {
+			return err
+		}
+		if op.Op == "move" {
+			if op.From == op.Path {
+				return nil
+			}
+			if strings.HasPrefix(op.Path, op.From+"/") {
+				return Errorf(ErrBadPath, "cannot move %s into itself", op.From)
+			}
+		}
+		// We read from a copy so the value does not share memory with msg.
+		val, whole, fd, err := readPointer(proto.Clone(msg), from)
+		if err != nil {
+			return err
+		}
+		if op.Op == "move" {
+			if err := removePointer(msg, from); err != nil {
+				return err
+			}
+		}
+		return addPointer(msg, p, copyConv(val, whole, fd), false)
+	case "test":
+		tested := proto.Clone(msg)
+		if err := addPointer(tested, p, jsonConv(op.Value), true); err != nil {
+			return err
+		}
+		return testEqual(msg, tested, op)
+	}
+	return Errorf(ErrParse, "unknown operation(%s)", op.Op)
+}
+
+// testEqual returns an error if msg and tested are not equal.
+func testEqual(msg, tested proto.Message, op patchOp) error {
+	if !proto.Equal(msg, tested) {
+		return Errorf(ErrPatchTestFailed, "value at %s is not %s", op.Path, op.Value)
+	}
+	return nil
+}
+
+// patchPointer is a JSON pointer resolved against a message descriptor.
+type patchPointer struct {
+	// root is set if the pointer is the whole message.
+	root bool
+	// fqPath is the pointer as an fqPath. If appendEntry is set, this is the path of the list.
+	fqPath string
+	// fd is the last field in the pointer.
+	fd protoreflect.FieldDescriptor
+	// selector is set if the pointer selects an entry of a repeated field or map.
+	selector bool
+	// appendEntry is set if the pointer ends in "-", which is past the end of a repeated field.
+	appendEntry bool
+}
+
+// patchField returns the field in md called name, which can be the proto name or the JSON name of the field.
+// Patches are often written by hand from a Go struct, so a name that ProtoName() converts, aka "Name", is also
+// found. This is only for patches, paths elsewhere do not allow it.
+func patchField(md protoreflect.MessageDescriptor, name string) protoreflect.FieldDescriptor {
+	if fd := fieldByName(md, name); fd != nil {
+		return fd
+	}
+	return md.Fields().ByName(protoreflect.Name(ProtoName(name)))
+}
+
+// pointerIndex returns true if s is an array index as RFC 6901 allows it, which is "0" or a number without
+// a leading zero or a sign.
+func pointerIndex(s string) bool {
+	if s == "" || strings.TrimLeft(s, "0123456789") != "" {
+		return false
+	}
+	return s == "0" || s[0] != '0'
+}
+
+// resolvePointer converts the JSON pointer ptr into a patchPointer for the message described by md.
+func resolvePointer(md protoreflect.MessageDescriptor, ptr string) (patchPointer, error) {
+	if ptr == "" {
+		return patchPointer{root: true}, nil
+	}
+	if !strings.HasPrefix(ptr, "/") {
+		return patchPointer{}, Errorf(ErrBadPath, "JSON pointer(%s) must start with a /", ptr)
+	}
+
+	tokens := strings.Split(ptr[1:], "/")
+	for i, tok := range tokens {
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(tok)
+	}
+
+	p := patchPointer{}
+	var parts []string
+	for i := 0; i < len(tokens); i++ {
+		if md == nil {
+			return patchPointer{}, Errorf(ErrIntermediateNotMessage, "field(%s) should be a message, was a %s", strings.Join(parts, "."), p.fd.Kind())
+		}
+		fd := patchField(md, tokens[i])
+		if fd == nil {
+			return patchPointer{}, Errorf(ErrBadFieldName, "field(%s) could not be found", strings.Join(append(parts, tokens[i]), "."))
+		}
+		p.fd, p.selector, md = fd, false, nil
+
+		part := string(fd.Name())
+		if (fd.IsList() || fd.IsMap()) && i+1 < len(tokens) {
+			i++
+			sel := tokens[i]
+			switch {
+			case fd.IsList() && sel == "-":
+				if i != len(tokens)-1 {
+					return patchPointer{}, Errorf(ErrBadPath, "JSON pointer(%s) can only use - at the end", ptr)
+				}
+				p.appendEntry = true
+			case fd.IsList():
+				if !pointerIndex(sel) {
+					return patchPointer{}, Errorf(ErrBadPath, "JSON pointer(%s) has an index(%s) that is not a non-negative integer without leading zeros", ptr, sel)
+				}
+				part += "[" + sel + "]"
+				p.selector = true
+			default:
+				if fd.MapKey().Kind() == protoreflect.StringKind {
+					sel = strconv.Quote(sel)
+				}
+				part += "[" + sel + "]"
+				p.selector = true
+			}
+			if vd := valueDesc(fd); vd.Kind() == protoreflect.MessageKind {
+				md = vd.Message()
+			}
+		} else if !fd.IsList() && !fd.IsMap() && fd.Kind() == protoreflect.MessageKind {
+			md = fd.Message()
+		}
+		parts = append(parts, part)
+	}
+	p.fqPath = strings.Join(parts, ".")
+	return p, nil
+}
+
+// patchFunc returns the value to store in field fd of msg. whole is set if the value is for all of a repeated
+// field or map instead of a single value. fd is nil if the value replaces all of msg. path is used in error messages.
+type patchFunc func(msg protoreflect.Message, fd protoreflect.FieldDescriptor, whole bool, path string) (protoreflect.Value, error)
+
+// jsonConv returns a patchFunc that converts raw.
+func jsonConv(raw json.RawMessage) patchFunc {
+	return func(msg protoreflect.Message, fd protoreflect.FieldDescriptor, whole bool, path string) (protoreflect.Value, error) {
+		switch {
+		case fd == nil:
+			n := msg.New()
+			if err := mergePatch(n, raw, ""); err != nil {
+				return protoreflect.Value{}, err
+			}
+			return protoreflect.ValueOfMessage(n), nil
+		case whole:
+			return jsonWhole(msg, fd, raw, path)
+		}
+		return jsonSingle(msg, fd, raw, path)
+	}
+}
+
+// copyConv returns a patchFunc that returns val, which was read from field from. whole is set if val is
+// all of a repeated field or map.
+func copyConv(val protoreflect.Value, whole bool, from protoreflect.FieldDescriptor) patchFunc {
+	return func(_ protoreflect.Message, fd protoreflect.FieldDescriptor, toWhole bool, path string) (protoreflect.Value, error) {
+		if fd == nil || whole != toWhole || !sameType(from, fd, whole) {
+			return protoreflect.Value{}, Errorf(ErrTypeMismatch, "field(%s) cannot hold a value from field(%s)", path, from.FullName())
+		}
+		return val, nil
+	}
+}
+
+// sameType returns true if values of a can be stored in b. If whole is set, this is for all of a repeated
+// field or map, otherwise it is for a single value.
+func sameType(a, b protoreflect.FieldDescriptor, whole bool) bool {
+	if whole {
+		if a.IsList() != b.IsList() || a.IsMap() != b.IsMap() {
+			return false
+		}
+		if a.IsMap() && a.MapKey().Kind() != b.MapKey().Kind() {
+			return false
+		}
+	}
+	va, vb := valueDesc(a), valueDesc(b)
+	switch {
+	case va.Kind() != vb.Kind():
+		return false
+	case va.Kind() == protoreflect.MessageKind:
+		return va.Message().FullName() == vb.Message().FullName()
+	case va.Kind() == protoreflect.EnumKind:
+		return va.Enum().FullName() == vb.Enum().FullName()
+	}
+	return true
+}
+
+// addPointer does an "add" operation, or a "replace" operation if replace is set, storing the value from conv at p.
+func addPointer(msg proto.Message, p patchPointer, conv patchFunc, replace bool) error {
+	if p.root {
+		val, err := conv(msg.ProtoReflect(), nil, false, "")
+		if err != nil {
+			return err
+		}
+		proto.Reset(msg)
+		proto.Merge(msg, val.Message().Interface())
+		return nil
+	}
+
+	m, fd, pp, err := lastField(msg, p.fqPath, false, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case p.appendEntry:
+		if replace {
+			return Errorf(ErrBadPath, "field(%s) has no entry at -, it can only be used to add", p.fqPath)
+		}
+		l := m.Mutable(fd).List()
+		val, err := conv(m, fd, false, fmt.Sprintf("%s[%d]", p.fqPath, l.Len()))
+		if err != nil {
+			return err
+		}
+		l.Append(val)
+		return nil
+	case !pp.hasSelector && (fd.IsList() || fd.IsMap()):
+		val, err := conv(m, fd, true, p.fqPath)
+		if err != nil {
+			return err
+		}
+		setWhole(m, fd, val)
+		return nil
+	case pp.hasSelector && fd.IsList() && !replace:
+		index, err := pp.index()
+		if err != nil {
+			return err
+		}
+		if index > m.Get(fd).List().Len() {
+			return Errorf(ErrIndexOutOfRange, "field(%s) has %d entries, index %d is out of range", p.fqPath, m.Get(fd).List().Len(), index)
+		}
+		val, err := conv(m, fd, false, p.fqPath)
+		if err != nil {
+			return err
+		}
+		insertList(m.Mutable(fd).List(), index, []protoreflect.Value{val})
+		return nil
+	case pp.hasSelector && fd.IsMap() && replace:
+		k, err := pp.mapKey(fd)
+		if err != nil {
+			return err
+		}
+		if !m.Get(fd).Map().Has(k) {
+			return Errorf(ErrKeyNotFound, "map field(%s) does not have key %s", p.fqPath, pp.selector)
+		}
+	}
+
+	// Single fields, entries of repeated fields being replaced and map entries use the same setter as UpdateProtoField().
+	return updateField(msg, p.fqPath, nil, func(m protoreflect.Message, fd protoreflect.FieldDescriptor) (protoreflect.Value, error) {
+		return conv(m, fd, false, p.fqPath)
+	})
+}
+
+// setWhole sets all of the repeated field or map fd in m to val.
+func setWhole(m protoreflect.Message, fd protoreflect.FieldDescriptor, val protoreflect.Value) {
+	empty := false
+	if fd.IsList() {
+		empty = val.List().Len() == 0
+	} else {
+		empty = val.Map().Len() == 0
+	}
+	if empty {
+		m.Clear(fd)
+		return
+	}
+	m.Set(fd, val)
+}
+
+// removePointer does a "remove" operation at p.
+func removePointer(msg proto.Message, p patchPointer) error {
+	if p.root {
+		proto.Reset(msg)
+		return nil
+	}
+	if p.appendEntry {
+		return Errorf(ErrBadPath, "field(%s) has no entry at -, it can only be used to add", p.fqPath)
+	}
+
+	m, fd, pp, err := lastField(msg, p.fqPath, false, nil, nil)
+	if err != nil {
+		return err
+	}
+	switch {
+	case !pp.hasSelector:
+		m.Clear(fd)
+	case fd.IsList():
+		index, err := pp.index()
+		if err != nil {
+			return err
+		}
+		l := m.Get(fd).List()
+		if index >= l.Len() {
+			return Errorf(ErrIndexOutOfRange, "field(%s) has %d entries, index %d is out of range", p.fqPath, l.Len(), index)
+		}
+		removeList(m.Mutable(fd).List(), index)
+	default:
+		k, err := pp.mapKey(fd)
+		if err != nil {
+			return err
+		}
+		if !m.Get(fd).Map().Has(k) {
+			return Errorf(ErrKeyNotFound, "map field(%s) does not have key %s", p.fqPath, pp.selector)
+		}
+		m.Mutable(fd).Map().Clear(k)
+	}
+	return nil
+}
+
+// readPointer reads the value at p for a "copy" or "move" operation. whole is set if the value is all of a
+// repeated field or map. fd is the field the value was read from.
+func readPointer(msg proto.Message, p patchPointer) (val protoreflect.Value, whole bool, fd protoreflect.FieldDescriptor, err error) {
+	if p.root || p.appendEntry {
+		return protoreflect.Value{}, false, nil, Errorf(ErrBadPath, "cannot copy or move from JSON pointer(%s)", p.fqPath)
+	}
+
+	m, fd, pp, err := lastField(msg, p.fqPath, false, nil, nil)
+	if err != nil {
+		return protoreflect.Value{}, false, nil, err
+	}
+	switch {
+	case !pp.hasSelector:
+		if fd.Kind() == protoreflect.MessageKind && !fd.IsList() && !fd.IsMap() && !m.Has(fd) {
+			return protoreflect.Value{}, false, nil, Errorf(ErrIntermdiateNotSet, "message field(%s) is not set", p.fqPath)
+		}
+		return m.Get(fd), fd.IsList() || fd.IsMap(), fd, nil
+	case fd.IsList():
+		index, err := pp.index()
+		if err != nil {
+			return protoreflect.Value{}, false, nil, err
+		}
+		l := m.Get(fd).List()
+		if index >= l.Len() {
+			return protoreflect.Value{}, false, nil, Errorf(ErrIndexOutOfRange, "field(%s) has %d entries, index %d is out of range", p.fqPath, l.Len(), index)
+		}
+		return l.Get(index), false, fd, nil
+	}
+	k, err := pp.mapKey(fd)
+	if err != nil {
+		return protoreflect.Value{}, false, nil, err
+	}
+	v := m.Get(fd).Map().Get(k)
+	if !v.IsValid() {
+		return protoreflect.Value{}, false, nil, Errorf(ErrKeyNotFound, "map field(%s) does not have key %s", p.fqPath, pp.selector)
+	}
+	return v, false, fd, nil
+}
+
+// mergePatch applies the JSON merge patch in raw, which must be an object, to m. path is the path to m and
+// is used in error messages.
+func mergePatch(m protoreflect.Message, raw json.RawMessage, path string) error {
+	obj, err := jsonObject(raw, path)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range sortedJSONKeys(obj) {
+		val := obj[key]
+		fd := patchField(m.Descriptor(), key)
+		if fd == nil {
+			return Errorf(ErrBadFieldName, "field(%s) could not be found", joinField(path, key))
+		}
+		fp := joinField(path, string(fd.Name()))
+
+		switch {
+		case jsonKind(val) == 'n':
+			m.Clear(fd)
+		case fd.IsMap():
+			if err := mergeMap(m, fd, val, fp); err != nil {
+				return err
+			}
+		case !fd.IsList() && fd.Kind() == protoreflect.MessageKind && jsonKind(val) == '{':
+			if err := mergePatch(m.Mutable(fd).Message(), val, fp); err != nil {
+				return err
+			}
+		case fd.IsList():
+			v, err := jsonWhole(m, fd, val, fp)
+			if err != nil {
+				return err
+			}
+			setWhole(m, fd, v)
+		default:
+			err := updateField(m.Interface(), string(fd.Name()), nil, func(m protoreflect.Message, fd protoreflect.FieldDescriptor) (protoreflect.Value, error) {
+				return jsonSingle(m, fd, val, fp)
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// mergeMap applies the JSON merge patch in raw, which must be an object, to the map field fd in m.
+func mergeMap(m protoreflect.Message, fd protoreflect.FieldDescriptor, raw json.RawMessage, path string) error {
+	obj, err := jsonObject(raw, path)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range sortedJSONKeys(obj) {
+		val := obj[key]
+		k, err := jsonMapKey(fd, key)
+		if err != nil {
+			return err
+		}
+		kp := path + "[" + keySelector(k) + "]"
+
+		switch {
+		case jsonKind(val) == 'n':
+			if m.Has(fd) {
+				m.Mutable(fd).Map().Clear(k)
+			}
+		case fd.MapValue().Kind() == protoreflect.MessageKind && jsonKind(val) == '{' && m.Get(fd).Map().Has(k):
+			if err := mergePatch(m.Mutable(fd).Map().Mutable(k).Message(), val, kp); err != nil {
+				return err
+			}
+		default:
+			v, err := jsonSingle(m, fd, val, kp)
+			if err != nil {
+				return err
+			}
+			m.Mutable(fd).Map().Set(k, v)
+		}
+	}
+	return nil
+}
+
+// jsonWhole converts raw into a value for all of the repeated field or map fd in m.
+func jsonWhole(m protoreflect.Message, fd protoreflect.FieldDescriptor, raw json.RawMessage, path string) (protoreflect.Value, error) {
+	if fd.IsMap() {
+		obj, err := jsonObject(raw, path)
+		if err != nil {
+			return protoreflect.Value{}, err
+		}
+		mp := m.NewField(fd).Map()
+		for _, key := range sortedJSONKeys(obj) {
+			k, err := jsonMapKey(fd, key)
+			if err != nil {
+				return protoreflect.Value{}, err
+			}
+			v, err := jsonSingle(m, fd, obj[key], path+"["+keySelector(k)+"]")
+			if err != nil {
+				return protoreflect.Value{}, err
+			}
+			mp.Set(k, v)
+		}
+		return protoreflect.ValueOfMap(mp), nil
+	}
+
+	if jsonKind(raw) != '[' {
+		return protoreflect.Value{}, Errorf(ErrTypeMismatch, "field(%s) is a repeated field, the value must be a JSON array", path)
+	}
+	var arr []json.RawMessage
+	if err := json.Unmarshal(raw, &arr); err != nil {
+		return protoreflect.Value{}, Errorf(ErrParse, "field(%s) could not be parsed: %s", path, err)
+	}
+	l := m.NewField(fd).List()
+	for i, e := range arr {
+		v, err := jsonSingle(m, fd, e, fmt.Sprintf("%s[%d]", path, i))
+		if err != nil {
+			return protoreflect.Value{}, err
+		}
+		l.Append(v)
+	}
+	return protoreflect.ValueOfList(l), nil
+}
+
+// jsonSingle converts raw into a single value for field fd in m. If fd is a repeated field or map, this is
+// the value of an entry.
+func jsonSingle(m protoreflect.Message, fd protoreflect.FieldDescriptor, raw json.RawMessage, path string) (protoreflect.Value, error) {
+	vd := valueDesc(fd)
+
+	switch jsonKind(raw) {
+	case 'n':
+		return protoreflect.Value{}, Errorf(ErrTypeMismatch, "field(%s) cannot be set to null", path)
+	case '{':
+		if vd.Kind() != protoreflect.MessageKind {
+			return protoreflect.Value{}, Errorf(ErrTypeMismatch, "field(%s) is of type %s, it cannot be set from a JSON object", path, kindName(vd))
+		}
+		n := newFieldMessage(m, fd)
+		if err := mergePatch(n, raw, path); err != nil {
+			return protoreflect.Value{}, err
+		}
+		return protoreflect.ValueOfMessage(n), nil
+	case '[':
+		return protoreflect.Value{}, Errorf(ErrTypeMismatch, "field(%s) is of type %s, it cannot be set from a JSON array", path, kindName(vd))
+	case '"':
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return protoreflect.Value{}, Errorf(ErrParse, "field(%s) could not be parsed: %s", path, err)
+		}
+		return stringValue(m, fd, path, s)
+	case 't', 'f':
+		var b bool
+		if err := json.Unmarshal(raw, &b); err != nil {
+			return protoreflect.Value{}, Errorf(ErrParse, "field(%s) could not be parsed: %s", path, err)
+		}
+		return protoValue(fd, b)
+	}
+
+	var n json.Number
+	if err := json.Unmarshal(raw, &n); err != nil {
+		return protoreflect.Value{}, Errorf(ErrParse, "field(%s) could not be parsed: %s", path, err)
+	}
+	switch {
+	case vd.Kind() == protoreflect.FloatKind || vd.Kind() == protoreflect.DoubleKind:
+		f, err := n.Float64()
+		if err != nil {
+			return protoreflect.Value{}, Errorf(ErrParse, "field(%s) could not parse %s: %s", path, n, err)
+		}
+		return protoValue(fd, f)
+	case isInteger(vd.Kind()):
+		if i, err := n.Int64(); err == nil {
+			return protoValue(fd, i)
+		}
+		if u, err := strconv.ParseUint(n.String(), 10, 64); err == nil {
+			return protoValue(fd, u)
+		}
+		return protoreflect.Value{}, Errorf(ErrParse, "field(%s) is of type %s, %s is not an integer it can hold", path, kindName(vd), n)
+	}
+	return protoreflect.Value{}, Errorf(ErrTypeMismatch, "field(%s) is of type %s, it cannot be set from a JSON number", path, kindName(vd))
+}
+
+// jsonObject unmarshals raw, which must be a JSON object.
+func jsonObject(raw json.RawMessage, path string) (map[string]json.RawMessage, error) {
+	if jsonKind(raw) != '{' {
+		if path == "" {
+			return nil, Errorf(ErrTypeMismatch, "the patch must be a JSON object")
+		}
+		return nil, Errorf(ErrTypeMismatch, "field(%s) must be set with a JSON object", path)
+	}
+	obj := map[string]json.RawMessage{}
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return nil, Errorf(ErrParse, "field(%s) could not be parsed: %s", path, err)
+	}
+	return obj, nil
+}
+
+// jsonMapKey converts a JSON object key into a key for the map field fd.
+func jsonMapKey(fd protoreflect.FieldDescriptor, key string) (protoreflect.MapKey, error) {
+	pp := pathPart{name: string(fd.Name()), hasSelector: true, selector: key}
+	if fd.MapKey().Kind() == protoreflect.StringKind {
+		pp.selector = strconv.Quote(key)
+	}
+	return pp.mapKey(fd)
+}
+
+// jsonKind returns the first character of the JSON value in raw, which says what kind of value it is.
+// A number returns its first digit or '-'.
+func jsonKind(raw json.RawMessage) byte {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 {
+		return 0
+	}
+	return raw[0]
+}
+
+func sortedJSONKeys(obj map[string]json.RawMessage) []string {
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// joinField adds field to the fqPath in path.
+func joinField(path, field string) string {
+	if path == "" {
+		return field
+	}
+	return path + "." + field
+}
@@ -0,0 +1,193 @@
+package prototools
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	pb "github.com/johnsiilver/prototools/sample"
+)
+
+func TestApplyJSONMergePatch(t *testing.T) {
+	tests := []struct {
+		desc  string
+		msg   proto.Message
+		patch string
+		want  proto.Message
+		code  ErrCode
+	}{
+		{
+			desc:  "proto and JSON names with nested merge",
+			msg:   &pb.Layer0{Vint32: 1, Layer1: &pb.Layer1{Vstring: "keep", Supported: &pb.Supported{Vint64: 2}}},
+			patch: `{"vint32": 5, "ee": "EE_WHATEVER", "layer1": {"supported": {"vTime": "1614834367", "vint64": null}}}`,
+			want: &pb.Layer0{
+				Vint32: 5,
+				Ee:     pb.Layer0_EE_WHATEVER,
+				Layer1: &pb.Layer1{Vstring: "keep", Supported: &pb.Supported{VTime: 1614834367}},
+			},
+		},
+		{
+			desc:  "null clears a message",
+			msg:   &pb.Layer0{Layer1: &pb.Layer1{Vstring: "gone"}},
+			patch: `{"layer1": null}`,
+			want:  &pb.Layer0{},
+		},
+		{
+			desc:  "arrays replace and maps merge",
+			msg:   &pb.Customer{Tags: []string{"a", "b"}, Labels: map[string]string{"a": "1", "b": "2"}, ById: map[int64]*pb.Order{1: {Id: "x", Lines: []*pb.Line{{Sku: "s"}}}}},
+			patch: `{"tags": ["c"], "labels": {"a": null, "c": "3"}, "byId": {"1": {"id": "y"}, "2": {"id": "z"}}, "orders": [{"id": "o"}]}`,
+			want: &pb.Customer{
+				Tags:   []string{"c"},
+				Labels: map[string]string{"b": "2", "c": "3"},
+				ById:   map[int64]*pb.Order{1: {Id: "y", Lines: []*pb.Line{{Sku: "s"}}}, 2: {Id: "z"}},
+				Orders: []*pb.Order{{Id: "o"}},
+			},
+		},
+		{
+			desc:  "oneof member replaces the other",
+			msg:   &pb.Payment{Method: &pb.Payment_Voucher{Voucher: "v"}},
+			patch: `{"credits": 10}`,
+			want:  &pb.Payment{Method: &pb.Payment_Credits{Credits: 10}},
+		},
+		{
+			desc:  "all kinds",
+			msg:   &pb.Kinds{},
+			patch: `{"vbool": true, "vuint64": 18446744073709551615, "vfloat": 1.5, "vbytes": "aGk=", "venum": 2, "lDouble": [1, 2.5]}`,
+			want:  &pb.Kinds{Vbool: true, Vuint64: 18446744073709551615, Vfloat: 1.5, Vbytes: []byte("hi"), Venum: pb.EnumValues_EV_Not_Ok, LDouble: []float64{1, 2.5}},
+		},
+		{desc: "error: overflow", msg: &pb.Kinds{}, patch: `{"vint32": 2147483648}`, code: ErrValueOutOfRange},
+		{desc: "error: float to int", msg: &pb.Kinds{}, patch: `{"vint32": 1.5}`, code: ErrParse},
+		{desc: "error: unknown field", msg: &pb.Kinds{}, patch: `{"vstring": "ok", "nope": 1}`, code: ErrBadFieldName},
+		{desc: "error: not an object", msg: &pb.Kinds{}, patch: `[1]`, code: ErrTypeMismatch},
+		{desc: "error: object for scalar", msg: &pb.Kinds{}, patch: `{"vint32": {}}`, code: ErrTypeMismatch},
+		{desc: "error: scalar for list", msg: &pb.Kinds{}, patch: `{"lInt32": 1}`, code: ErrTypeMismatch},
+	}
+
+	for _, test := range tests {
+		before := proto.Clone(test.msg)
+		err := ApplyJSONMergePatch(test.msg, []byte(test.patch))
+		if !checkCode(t, "TestApplyJSONMergePatch", test.desc, err, test.code) {
+			continue
+		}
+		if err != nil {
+			if !proto.Equal(before, test.msg) {
+				t.Errorf("TestApplyJSONMergePatch(%s): message was changed on error", test.desc)
+			}
+			continue
+		}
+		if diff := cmp.Diff(test.want, test.msg, protocmp.Transform()); diff != "" {
+			t.Errorf("TestApplyJSONMergePatch(%s): -want/+got:\n%s", test.desc, diff)
+		}
+	}
+}
+
+func TestApplyJSONPatch(t *testing.T) {
+	tests := []struct {
+		desc  string
+		msg   proto.Message
+		patch string
+		want  proto.Message
+		code  ErrCode
+	}{
+		{
+			desc:  "add and replace",
+			msg:   &pb.Customer{Tags: []string{"a", "c"}, Orders: []*pb.Order{{Id: "1"}}},
+			patch: `[{"op": "add", "path": "/tags/1", "value": "b"}, {"op": "add", "path": "/tags/-", "value": "d"}, {"op": "replace", "path": "/orders/0/id", "value": "2"}, {"op": "add", "path": "/labels/a~1b", "value": "x"}, {"op": "add", "path": "/name", "value": "n"}]`,
+			want:  &pb.Customer{Name: "n", Tags: []string{"a", "b", "c", "d"}, Orders: []*pb.Order{{Id: "2"}}, Labels: map[string]string{"a/b": "x"}},
+		},
+		{
+			desc:  "add messages and whole lists",
+			msg:   &pb.Customer{},
+			patch: `[{"op": "add", "path": "/payment", "value": {"id": "p"}}, {"op": "add", "path": "/payment/voucher", "value": "n"}, {"op": "add", "path": "/orders", "value": [{"id": "1", "lines": [{"sku": "s"}]}]}]`,
+			want:  &pb.Customer{Payment: &pb.Payment{Id: "p", Method: &pb.Payment_Voucher{Voucher: "n"}}, Orders: []*pb.Order{{Id: "1", Lines: []*pb.Line{{Sku: "s"}}}}},
+		},
+		{
+			desc:  "remove",
+			msg:   &pb.Customer{Name: "n", Tags: []string{"a", "b"}, Labels: map[string]string{"a": "1", "b": "2"}},
+			patch: `[{"op": "remove", "path": "/name"}, {"op": "remove", "path": "/tags/0"}, {"op": "remove", "path": "/labels/a"}]`,
+			want:  &pb.Customer{Tags: []string{"b"}, Labels: map[string]string{"b": "2"}},
+		},
+		{
+			desc:  "move and copy",
+			msg:   &pb.Customer{Tags: []string{"a", "b"}, Orders: []*pb.Order{{Id: "1"}, {Id: "2"}}},
+			patch: `[{"op": "move", "from": "/tags/0", "path": "/tags/-"}, {"op": "copy", "from": "/orders/0", "path": "/orders/-"}, {"op": "copy", "from": "/tags/1", "path": "/name"}]`,
+			want:  &pb.Customer{Name: "a", Tags: []string{"b", "a"}, Orders: []*pb.Order{{Id: "1"}, {Id: "2"}, {Id: "1"}}},
+		},
+		{
+			desc:  "test passes",
+			msg:   &pb.Layer0{Vint32: 3, Ee: pb.Layer0_EE_WHATEVER},
+			patch: `[{"op": "test", "path": "/vint32", "value": 3}, {"op": "test", "path": "/ee", "value": "EE_WHATEVER"}, {"op": "test", "path": "/layer1", "value": null}, {"op": "replace", "path": "/vint32", "value": 4}]`,
+			want:  &pb.Layer0{Vint32: 4, Ee: pb.Layer0_EE_WHATEVER},
+		},
+		{
+			desc:  "Go field names",
+			msg:   &pb.Customer{Tags: []string{"a"}},
+			patch: `[{"op": "replace", "path": "/Tags/0", "value": "b"}, {"op": "add", "path": "/Name", "value": "n"}]`,
+			want:  &pb.Customer{Name: "n", Tags: []string{"b"}},
+		},
+		{
+			desc:  "replace the whole message",
+			msg:   &pb.Layer0{Vint32: 3},
+			patch: `[{"op": "replace", "path": "", "value": {"ee": 1}}]`,
+			want:  &pb.Layer0{Ee: pb.Layer0_EE_WHATEVER},
+		},
+		{
+			desc:  "error: test fails and nothing is applied",
+			msg:   &pb.Layer0{Vint32: 3},
+			patch: `[{"op": "replace", "path": "/vint32", "value": 4}, {"op": "test", "path": "/vint32", "value": 3}]`,
+			code:  ErrPatchTestFailed,
+		},
+		{desc: "error: move into missing map entry", msg: &pb.Customer{Orders: []*pb.Order{{Id: "1"}}}, patch: `[{"op": "move", "from": "/orders/0/id", "path": "/byId/7/id"}]`, code: ErrKeyNotFound},
+		{desc: "error: unset intermediate", msg: &pb.Layer0{}, patch: `[{"op": "add", "path": "/layer1/vstring", "value": "x"}]`, code: ErrIntermdiateNotSet},
+		{desc: "error: index past end", msg: &pb.Customer{}, patch: `[{"op": "add", "path": "/tags/1", "value": "x"}]`, code: ErrIndexOutOfRange},
+		{desc: "error: replace missing key", msg: &pb.Customer{}, patch: `[{"op": "replace", "path": "/labels/a", "value": "x"}]`, code: ErrKeyNotFound},
+		{desc: "error: negative index", msg: &pb.Customer{Tags: []string{"a"}}, patch: `[{"op": "remove", "path": "/tags/-1"}]`, code: ErrBadPath},
+		{desc: "error: leading zero index", msg: &pb.Customer{Tags: []string{"a", "b"}}, patch: `[{"op": "remove", "path": "/tags/01"}]`, code: ErrBadPath},
+		{desc: "error: signed index", msg: &pb.Customer{Tags: []string{"a", "b"}}, patch: `[{"op": "remove", "path": "/tags/+1"}]`, code: ErrBadPath},
+		{desc: "error: copy wrong type", msg: &pb.Customer{Tags: []string{"a"}}, patch: `[{"op": "copy", "from": "/tags", "path": "/name"}]`, code: ErrTypeMismatch},
+		{desc: "error: unknown op", msg: &pb.Customer{}, patch: `[{"op": "frob", "path": "/name"}]`, code: ErrParse},
+		{desc: "error: not an array", msg: &pb.Customer{}, patch: `{}`, code: ErrParse},
+		{desc: "error: bad field", msg: &pb.Customer{}, patch: `[{"op": "add", "path": "/nope", "value": 1}]`, code: ErrBadFieldName},
+	}
+
+	for _, test := range tests {
+		before := proto.Clone(test.msg)
+		err := ApplyJSONPatch(test.msg, []byte(test.patch))
+		if !checkCode(t, "TestApplyJSONPatch", test.desc, err, test.code) {
+			continue
+		}
+		if err != nil {
+			if !proto.Equal(before, test.msg) {
+				t.Errorf("TestApplyJSONPatch(%s): message was changed on error", test.desc)
+			}
+			continue
+		}
+		if diff := cmp.Diff(test.want, test.msg, protocmp.Transform()); diff != "" {
+			t.Errorf("TestApplyJSONPatch(%s): -want/+got:\n%s", test.desc, diff)
+		}
+	}
+}
+
+// checkCode checks that err has the code "code", where ErrUnknown means there should be no error.
+// It returns false if the check failed.
+func checkCode(t *testing.T, name, desc string, err error, code ErrCode) bool {
+	t.Helper()
+	switch {
+	case err == nil && code != ErrUnknown:
+		t.Errorf("%s(%s): got err == nil, want err != nil", name, desc)
+		return false
+	case err != nil && code == ErrUnknown:
+		t.Errorf("%s(%s): got err == %s, want err == nil", name, desc, err)
+		return false
+	case err != nil:
+		var e Error
+		if !errors.As(err, &e) || e.Code != code {
+			t.Errorf("%s(%s): got err == %s, want code %s", name, desc, err, code)
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,408 @@
+package prototools
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// pathPart is a single part of an fqPath, such as "orders", "orders[3]" or `labels["env"]`.
+type pathPart struct {
+	// name is the proto name of the field.
+	name string
+	// hasSelector is set if the part had a selector, aka "[3]".
+	hasSelector bool
+	// selector is the text between the "[" and "]". For a repeated field this is an index,
+	// for a map it is a key. How it is read depends on the field.
+	selector string
+}
+
+// parsePart parses a single part of an fqPath.
+func parsePart(s string) (pathPart, error) {
+	open := strings.IndexByte(s, '[')
+	if open == -1 {
+		if strings.IndexByte(s, ']') != -1 {
+			return pathPart{}, Errorf(ErrBadPath, "path part(%s) has a ']' without a '['", s)
+		}
+		return pathPart{name: s}, nil
+	}
+
+	if open == 0 {
+		return pathPart{}, Errorf(ErrBadPath, "path part(%s) has a selector without a field name", s)
+	}
+	if !strings.HasSuffix(s, "]") {
+		return pathPart{}, Errorf(ErrBadPath, "path part(%s) has a selector that does not end with ']'", s)
+	}
+	return pathPart{name: s[:open], hasSelector: true, selector: s[open+1 : len(s)-1]}, nil
+}
+
+// index returns the selector as an index into a list.
+func (p pathPart) index() (int, error) {
+	i, err := strconv.Atoi(p.selector)
+	if err != nil {
+		return 0, Errorf(ErrBadPath, "path part(%s[%s]) has an index(%s) that is not an integer", p.name, p.selector, p.selector)
+	}
+	return i, nil
+}
+
+// mapKey returns the selector as a key for the map field fd. String keys must be quoted, aka
+// `labels["env"]`, all other keys are written as Go would write the literal.
+func (p pathPart) mapKey(fd protoreflect.FieldDescriptor) (protoreflect.MapKey, error) {
+	kd := fd.MapKey()
+
+	var (
+		v   interface{}
+		err error
+	)
+	switch kd.Kind() {
+	case protoreflect.StringKind:
+		v, err = strconv.Unquote(p.selector)
+	case protoreflect.BoolKind:
+		v, err = strconv.ParseBool(p.selector)
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		var i int64
+		i, err = strconv.ParseInt(p.selector, 10, 32)
+		v = int32(i)
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		v, err = strconv.ParseInt(p.selector, 10, 64)
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		var i uint64
+		i, err = strconv.ParseUint(p.selector, 10, 32)
+		v = uint32(i)
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		v, err = strconv.ParseUint(p.selector, 10, 64)
+	default:
+		return protoreflect.MapKey{}, Errorf(ErrBadPath, "map field(%s) has a key kind(%s) we do not support", p.name, kd.Kind())
+	}
+	if err != nil {
+		return protoreflect.MapKey{}, Errorf(ErrBadPath, "map field(%s) has %s keys, key(%s) is not valid: %s", p.name, kd.Kind(), p.selector, err)
+	}
+	return protoreflect.ValueOf(v).MapKey(), nil
+}
+
+// fieldByName returns the field in md called name, which can be the proto name or the JSON name
+// of the field. It returns nil if there is no such field.
+func fieldByName(md protoreflect.MessageDescriptor, name string) protoreflect.FieldDescriptor {
+	if fd := md.Fields().ByName(protoreflect.Name(name)); fd != nil {
+		return fd
+	}
+	return md.Fields().ByJSONName(name)
+}
+
+// listIndex converts index into a position in a list of length "length". Negative indexes
+// count back from the end of the list. If the index is out of range, ok will be false.
+func listIndex(index, length int) (i int, ok bool) {
+	if index < 0 {
+		index += length
+	}
+	if index < 0 || index >= length {
+		return 0, false
+	}
+	return index, true
+}
+
+// partValue gets the value of a single fqPath part from msg. path is the fqPath up to and
+// including the part and is used in error messages.
+func partValue(msg proto.Message, part, path string) (FieldValue, error) {
+	pp, err := parsePart(part)
+	if err != nil {
+		return FieldValue{}, err
+	}
+
+	if !pp.hasSelector {
+		fv, err := fieldValue(msg, pp.name)
+		if err != nil {
+			return FieldValue{}, Errorf(ErrBadFieldName, "field(%s) could not be found", path)
+		}
+		return fv, nil
+	}
+
+	ref := msg.ProtoReflect()
+	fd := partField(ref.Descriptor(), pp.name)
+	if fd == nil {
+		return FieldValue{}, Errorf(ErrBadFieldName, "field(%s) could not be found", path)
+	}
+
+	switch {
+	case fd.IsList():
+		index, err := pp.index()
+		if err != nil {
+			return FieldValue{}, err
+		}
+		l := ref.Get(fd).List()
+		i, ok := listIndex(index, l.Len())
+		if !ok {
+			return FieldValue{}, Errorf(ErrIndexOutOfRange, "field(%s) has %d entries, index %d is out of range", path, l.Len(), index)
+		}
+		return elemValue(fd, l.Get(i)), nil
+	case fd.IsMap():
+		k, err := pp.mapKey(fd)
+		if err != nil {
+			return FieldValue{}, err
+		}
+		v := ref.Get(fd).Map().Get(k)
+		if !v.IsValid() {
+			return FieldValue{}, Errorf(ErrKeyNotFound, "map field(%s) does not have key %s", path, pp.selector)
+		}
+		return elemValue(fd, v), nil
+	}
+	return FieldValue{}, Errorf(ErrBadPath, "field(%s) is not a repeated field or map and cannot have a selector", path)
+}
+
+// lastField walks msg down fqPath and returns the message that holds the last field, the field's
+// descriptor and the last part of the path. If createMessages is set, intermediate messages that are
+// not set are created and oneof members that are cleared to do so are added to changes, if it is not nil.
+// anys is passed to getLastMessage().
+func lastField(msg proto.Message, fqPath string, createMessages bool, changes *[]OneofChange, anys *anyStack) (protoreflect.Message, protoreflect.FieldDescriptor, pathPart, error) {
+	fields := FQPathSplit(fqPath)
+	pp, err := parsePart(fields[len(fields)-1])
+	if err != nil {
+		return nil, nil, pathPart{}, err
+	}
+	if pp.name == "" {
+		return nil, nil, pathPart{}, Errorf(ErrBadPath, "path(%s) does not end in a field", fqPath)
+	}
+
+	m, err := lastMessage(msg, fields, createMessages, changes, anys)
+	if err != nil {
+		return nil, nil, pathPart{}, err
+	}
+
+	fd := partField(m.Descriptor(), pp.name)
+	if fd == nil {
+		return nil, nil, pathPart{}, Errorf(ErrBadFieldName, "field(%s) could not be found", fqPath)
+	}
+	return m, fd, pp, nil
+}
+
+// setPart sets val on the field fd in msg. If pp has a selector, val is set on that entry of the
+// list or map. path is the fqPath and is used in error messages.
+func setPart(msg protoreflect.Message, fd protoreflect.FieldDescriptor, pp pathPart, val protoreflect.Value, path string) error {
+	if !pp.hasSelector {
+		switch {
+		case fd.IsList():
+			return Errorf(ErrBadPath, "field(%s) is a repeated field, you must select an entry with an index, aka %s[0]", path, path)
+		case fd.IsMap():
+			return Errorf(ErrBadPath, "field(%s) is a map, you must select an entry with a key, aka %s[<key>]", path, path)
+		}
+		msg.Set(fd, val)
+		return nil
+	}
+
+	switch {
+	case fd.IsList():
+		index, err := pp.index()
+		if err != nil {
+			return err
+		}
+		l := msg.Mutable(fd).List()
+		i, ok := listIndex(index, l.Len())
+		if !ok {
+			return Errorf(ErrIndexOutOfRange, "field(%s) has %d entries, index %d is out of range", path, l.Len(), index)
+		}
+		l.Set(i, val)
+		return nil
+	case fd.IsMap():
+		k, err := pp.mapKey(fd)
+		if err != nil {
+			return err
+		}
+		msg.Mutable(fd).Map().Set(k, val)
+		return nil
+	}
+	return Errorf(ErrBadPath, "field(%s) is not a repeated field or map and cannot have a selector", path)
+}
+
+// valueDesc returns the descriptor for values stored in fd. This is fd, unless fd is a map,
+// in which case it is the descriptor of the map's values.
+func valueDesc(fd protoreflect.FieldDescriptor) protoreflect.FieldDescriptor {
+	if fd.IsMap() {
+		return fd.MapValue()
+	}
+	return fd
+}
+
+// elemValue converts a single value of field fd into a FieldValue. This is used for entries in
+// a list or map, where fd describes the list or map.
+func elemValue(fd protoreflect.FieldDescriptor, v protoreflect.Value) FieldValue {
+	vd := valueDesc(fd)
+	fv := FieldValue{
+		Kind:      vd.Kind(),
+		FieldDesc: fd,
+	}
+	switch vd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		fv.Value = v.Message().Interface()
+		fv.MsgDesc = vd.Message()
+		fv.WellKnown, _ = wellKnownValue(v.Message())
+	case protoreflect.EnumKind:
+		fv.Value = v.Enum()
+		fv.EnumDesc = vd.Enum().Values().ByNumber(v.Enum())
+	default:
+		fv.Value = v.Interface()
+	}
+	return fv
+}
+
+// notMessageErr returns an error if fv is a container (list or map) instead of a single message.
+func notMessageErr(fv FieldValue, path string) error {
+	switch {
+	case fv.IsList:
+		return Errorf(ErrNotMessage, "message field(%s) is a repeated field, you must select an entry with an index, aka %s[0]", path, path)
+	case fv.IsMap:
+		return Errorf(ErrNotMessage, "message field(%s) is a map, you must select an entry with a key, aka %s[<key>]", path, path)
+	}
+	return nil
+}
+
+// walkMessages walks down msg using fields, each of which must be a message, and returns the
+// last message. This is used for reads, so intermediate messages that are not set are returned
+// as nil messages whose fields are all the default values.
+func walkMessages(msg proto.Message, fields []string) (proto.Message, error) {
+	for x, field := range fields {
+		path := strings.Join(fields[0:x+1], ".")
+		var err error
+		if msg, err = unpackAny(msg, field, anyPath(fields, x)); err != nil {
+			return nil, err
+		}
+		fv, err := partValue(msg, field, path)
+		if err != nil {
+			return nil, err
+		}
+		if fv.Kind != protoreflect.MessageKind {
+			return nil, Errorf(ErrIntermediateNotMessage, "field(%s) should be a message, was a %s", path, fv.Kind)
+		}
+		if err := notMessageErr(fv, path); err != nil {
+			return nil, err
+		}
+		msg = fv.Value.(proto.Message)
+	}
+	return msg, nil
+}
+
+// keySelector formats a map key as it would be written in a selector. This is the inverse of
+// pathPart.mapKey().
+func keySelector(k protoreflect.MapKey) string {
+	if s, ok := k.Interface().(string); ok {
+		return strconv.Quote(s)
+	}
+	return k.String()
+}
+
+// sortedMapKeys returns the keys of m in a stable order. Numbers are sorted by value, strings
+// lexically and false comes before true.
+func sortedMapKeys(m protoreflect.Map) []protoreflect.MapKey {
+	keys := make([]protoreflect.MapKey, 0, m.Len())
+	m.Range(func(k protoreflect.MapKey, _ protoreflect.Value) bool {
+		keys = append(keys, k)
+		return true
+	})
+	sortMapKeys(keys)
+	return keys
+}
+
+// sortMapKeys sorts keys in the same order as sortedMapKeys().
+func sortMapKeys(keys []protoreflect.MapKey) {
+	sort.Slice(keys, func(i, j int) bool {
+		switch a := keys[i].Interface().(type) {
+		case string:
+			return a < keys[j].String()
+		case bool:
+			return !a && keys[j].Bool()
+		case int32, int64:
+			return keys[i].Int() < keys[j].Int()
+		}
+		return keys[i].Uint() < keys[j].Uint()
+	})
+}
+
+// checkPath validates that the fields of an fqPath exist in the message described by md and
+// that every field but the last is a message. Selectors must be used on repeated fields and maps
+// that are not the last field. It does not look at the content of selectors. It returns the descriptor
+// of the last field.
+func checkPath(md protoreflect.MessageDescriptor, fields []string) (protoreflect.FieldDescriptor, error) {
+	for x, field := range fields {
+		path := strings.Join(fields[0:x+1], ".")
+		pp, err := parsePart(field)
+		if err != nil {
+			return nil, err
+		}
+		fd := partField(md, pp.name)
+		if fd == nil {
+			return nil, Errorf(ErrBadFieldName, "field(%s) could not be found", path)
+		}
+		if pp.hasSelector && !fd.IsList() && !fd.IsMap() {
+			return nil, Errorf(ErrBadPath, "field(%s) is not a repeated field or map and cannot have a selector", path)
+		}
+		if x == len(fields)-1 {
+			return fd, nil
+		}
+
+		vd := valueDesc(fd)
+		if vd.Kind() != protoreflect.MessageKind {
+			return nil, Errorf(ErrIntermediateNotMessage, "field(%s) should be a message, was a %s", path, vd.Kind())
+		}
+		if !pp.hasSelector && (fd.IsList() || fd.IsMap()) {
+			return nil, Errorf(ErrNotMessage, "message field(%s) is a repeated field or map, you must use a selector, aka %s[0]", path, path)
+		}
+		md = vd.Message()
+	}
+	return nil, Errorf(ErrBadPath, "path is empty")
+}
+
+// createPart returns the message for a single fqPath part of ref, creating it if it is not set. If the part
+// selects the entry just past the end of a repeated field, a new message is appended. An index further past
+// the end is ErrIndexOutOfRange, so a path cannot make us allocate an unbounded list.
+// If it selects a map key that does not exist, the key is added with a new message. path is the fqPath up to
+// and including the part and is used in error messages. If the part is a member of a oneof and another member
+// is set, the other member is cleared and the change is added to changes, if it is not nil.
+func createPart(ref protoreflect.Message, part, path string, changes *[]OneofChange) (protoreflect.Message, error) {
+	pp, err := parsePart(part)
+	if err != nil {
+		return nil, err
+	}
+	fd := partField(ref.Descriptor(), pp.name)
+	if fd == nil {
+		return nil, Errorf(ErrBadFieldName, "field(%s) could not be found", path)
+	}
+	if vd := valueDesc(fd); vd.Kind() != protoreflect.MessageKind {
+		return nil, Errorf(ErrIntermediateNotMessage, "field(%s) should be a message, was a %s", path, vd.Kind())
+	}
+
+	if !pp.hasSelector {
+		if err := notMessageErr(FieldValue{IsList: fd.IsList(), IsMap: fd.IsMap()}, path); err != nil {
+			return nil, err
+		}
+		if change, cleared := clearOneof(ref, fd, path); cleared && changes != nil {
+			*changes = append(*changes, change)
+		}
+		return ref.Mutable(fd).Message(), nil
+	}
+
+	switch {
+	case fd.IsList():
+		index, err := pp.index()
+		if err != nil {
+			return nil, err
+		}
+		l := ref.Mutable(fd).List()
+		if index == l.Len() {
+			l.Append(l.NewElement())
+		}
+		i, ok := listIndex(index, l.Len())
+		if !ok {
+			return nil, Errorf(ErrIndexOutOfRange, "field(%s) has %d entries, index %d is out of range", path, l.Len(), index)
+		}
+		return l.Get(i).Message(), nil
+	case fd.IsMap():
+		k, err := pp.mapKey(fd)
+		if err != nil {
+			return nil, err
+		}
+		return ref.Mutable(fd).Map().Mutable(k).Message(), nil
+	}
+	return nil, Errorf(ErrBadPath, "field(%s) is not a repeated field or map and cannot have a selector", path)
+}
@@ -0,0 +1,447 @@
+package prototools
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kylelemons/godebug/pretty"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	pb "github.com/johnsiilver/prototools/sample"
+)
+
+func TestFQPathSplit(t *testing.T) {
+	tests := []struct {
+		desc   string
+		fqPath string
+		want   []string
+	}{
+		{"empty", "", []string{""}},
+		{"single", "vint32", []string{"vint32"}},
+		{"dotted", "layer1.supported.vint32", []string{"layer1", "supported", "vint32"}},
+		{"indexed", "orders[3].lines[-1].sku", []string{"orders[3]", "lines[-1]", "sku"}},
+		{"dot in selector", "orders[1.2].id", []string{"orders[1.2]", "id"}},
+		{"quoted key", `labels["a.b"].x`, []string{`labels["a.b"]`, "x"}},
+		{"quoted key with escaped quote", `labels["a\".]b"].x`, []string{`labels["a\".]b"]`, "x"}},
+		{"extension", "(my.pkg.ext).sub.(my.pkg.list)[0]", []string{"(my.pkg.ext)", "sub", "(my.pkg.list)[0]"}},
+	}
+
+	for _, test := range tests {
+		got := FQPathSplit(test.fqPath)
+		if diff := pretty.Compare(test.want, got); diff != "" {
+			t.Errorf("TestFQPathSplit(%s): -want/+got:\n%s", test.desc, diff)
+		}
+	}
+}
+
+func TestParsePart(t *testing.T) {
+	tests := []struct {
+		desc string
+		part string
+		want pathPart
+		err  bool
+	}{
+		{desc: "name only", part: "orders", want: pathPart{name: "orders"}},
+		{desc: "index", part: "orders[3]", want: pathPart{name: "orders", hasSelector: true, selector: "3"}},
+		{desc: "key", part: `labels["env"]`, want: pathPart{name: "labels", hasSelector: true, selector: `"env"`}},
+		{desc: "error: no name", part: "[3]", err: true},
+		{desc: "error: no close", part: "orders[3", err: true},
+		{desc: "error: no open", part: "orders3]", err: true},
+	}
+
+	for _, test := range tests {
+		got, err := parsePart(test.part)
+		switch {
+		case err == nil && test.err:
+			t.Errorf("TestParsePart(%s): got err == nil, want err != nil", test.desc)
+			continue
+		case err != nil && !test.err:
+			t.Errorf("TestParsePart(%s): got err == %s, want err == nil", test.desc, err)
+			continue
+		case err != nil:
+			continue
+		}
+		if got != test.want {
+			t.Errorf("TestParsePart(%s): got %+v, want %+v", test.desc, got, test.want)
+		}
+	}
+}
+
+func newCustomer() *pb.Customer {
+	return &pb.Customer{
+		Name: "John",
+		Tags: []string{"new", "vip"},
+		Orders: []*pb.Order{
+			{
+				Id: "order0",
+				Lines: []*pb.Line{
+					{Sku: "sku0", Quantity: 1, Price: 1.5},
+					{Sku: "sku1", Quantity: 2, Price: 2.5},
+				},
+			},
+			{
+				Id: "order1",
+				Lines: []*pb.Line{
+					{Sku: "sku2", Quantity: 3, Price: 3.5},
+				},
+			},
+		},
+	}
+}
+
+func TestGetFieldIndexed(t *testing.T) {
+	msg := newCustomer()
+
+	tests := []struct {
+		desc    string
+		fqPath  string
+		wantVal interface{}
+		code    ErrCode
+		err     bool
+	}{
+		{desc: "scalar in list", fqPath: "tags[1]", wantVal: "vip"},
+		{desc: "negative scalar in list", fqPath: "tags[-2]", wantVal: "new"},
+		{desc: "field in message in list", fqPath: "orders[1].id", wantVal: "order1"},
+		{desc: "nested lists", fqPath: "orders[0].lines[1].sku", wantVal: "sku1"},
+		{desc: "nested lists with negative", fqPath: "orders[-1].lines[-1].quantity", wantVal: int32(3)},
+		{desc: "error: out of range", fqPath: "orders[2].id", err: true, code: ErrIndexOutOfRange},
+		{desc: "error: negative out of range", fqPath: "tags[-3]", err: true, code: ErrIndexOutOfRange},
+		{desc: "error: no index on list", fqPath: "orders.id", err: true, code: ErrNotMessage},
+		{desc: "error: index on non-list", fqPath: "name[0]", err: true, code: ErrBadPath},
+		{desc: "error: bad field", fqPath: "orders[0].what", err: true, code: ErrBadFieldName},
+		{desc: "error: Go field name", fqPath: "Orders[0].id", err: true, code: ErrBadFieldName},
+	}
+
+	for _, test := range tests {
+		fv, err := GetField(msg, test.fqPath)
+		switch {
+		case err == nil && test.err:
+			t.Errorf("TestGetFieldIndexed(%s): got err == nil, want err != nil", test.desc)
+			continue
+		case err != nil && !test.err:
+			t.Errorf("TestGetFieldIndexed(%s): got err == %s, want err == nil", test.desc, err)
+			continue
+		case err != nil:
+			var e Error
+			if !errors.As(err, &e) || e.Code != test.code {
+				t.Errorf("TestGetFieldIndexed(%s): got err == %s, want code %s", test.desc, err, test.code)
+			}
+			continue
+		}
+
+		if fv.IsList {
+			t.Errorf("TestGetFieldIndexed(%s): got IsList == true, want false", test.desc)
+		}
+		if diff := pretty.Compare(test.wantVal, fv.Value); diff != "" {
+			t.Errorf("TestGetFieldIndexed(%s): -want/+got:\n%s", test.desc, diff)
+		}
+	}
+}
+
+func TestIndexedFieldAsStrAndGetFields(t *testing.T) {
+	msg := newCustomer()
+
+	got, _, err := FieldAsStr(msg, "orders[0].lines[1].price", false)
+	if err != nil {
+		t.Fatalf("TestIndexedFieldAsStrAndGetFields: FieldAsStr got err == %s, want err == nil", err)
+	}
+	if got != "2.50" {
+		t.Errorf("TestIndexedFieldAsStrAndGetFields: FieldAsStr got %q, want %q", got, "2.50")
+	}
+
+	if _, _, err := FieldAsStr(msg, "tags", false); err == nil {
+		t.Errorf("TestIndexedFieldAsStrAndGetFields: FieldAsStr on a list got err == nil, want err != nil")
+	}
+
+	fvs, err := GetFields(msg, "orders[1].lines[0]")
+	if err != nil {
+		t.Fatalf("TestIndexedFieldAsStrAndGetFields: GetFields got err == %s, want err == nil", err)
+	}
+	if len(fvs) != 3 {
+		t.Errorf("TestIndexedFieldAsStrAndGetFields: GetFields got %d fields, want 3", len(fvs))
+	}
+
+	if _, err := GetFields(msg, "orders"); err == nil {
+		t.Errorf("TestIndexedFieldAsStrAndGetFields: GetFields on a list got err == nil, want err != nil")
+	}
+}
+
+func TestUpdateProtoFieldIndexed(t *testing.T) {
+	tests := []struct {
+		desc   string
+		fqPath string
+		value  interface{}
+		want   func(c *pb.Customer)
+		err    bool
+	}{
+		{
+			desc:   "scalar in list",
+			fqPath: "tags[0]",
+			value:  "old",
+			want:   func(c *pb.Customer) { c.Tags[0] = "old" },
+		},
+		{
+			desc:   "field in nested list",
+			fqPath: "orders[0].lines[-1].sku",
+			value:  "changed",
+			want:   func(c *pb.Customer) { c.Orders[0].Lines[1].Sku = "changed" },
+		},
+		{
+			desc:   "error: out of range",
+			fqPath: "orders[5].id",
+			value:  "changed",
+			err:    true,
+		},
+		{
+			desc:   "error: list without index",
+			fqPath: "tags",
+			value:  "changed",
+			err:    true,
+		},
+		{
+			desc:   "error: wrong type for list",
+			fqPath: "tags[0]",
+			value:  true,
+			err:    true,
+		},
+	}
+
+	for _, test := range tests {
+		got := newCustomer()
+		err := UpdateProtoField(got, test.fqPath, test.value)
+		switch {
+		case err == nil && test.err:
+			t.Errorf("TestUpdateProtoFieldIndexed(%s): got err == nil, want err != nil", test.desc)
+			continue
+		case err != nil && !test.err:
+			t.Errorf("TestUpdateProtoFieldIndexed(%s): got err == %s, want err == nil", test.desc, err)
+			continue
+		case err != nil:
+			continue
+		}
+
+		want := proto.Clone(newCustomer()).(*pb.Customer)
+		test.want(want)
+		if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+			t.Errorf("TestUpdateProtoFieldIndexed(%s): -want/+got:\n%s", test.desc, diff)
+		}
+	}
+}
+
+func TestMapKey(t *testing.T) {
+	fields := (&pb.MapKeys{}).ProtoReflect().Descriptor().Fields()
+
+	tests := []struct {
+		desc     string
+		field    string
+		selector string
+		want     interface{}
+		err      bool
+	}{
+		{desc: "int32", field: "by_int32", selector: "-3", want: int32(-3)},
+		{desc: "uint32", field: "by_uint32", selector: "3", want: uint32(3)},
+		{desc: "uint64", field: "by_uint64", selector: "18446744073709551615", want: uint64(18446744073709551615)},
+		{desc: "bool", field: "by_bool", selector: "true", want: true},
+		{desc: "sint64", field: "by_sint64", selector: "-64", want: int64(-64)},
+		{desc: "error: int32 overflow", field: "by_int32", selector: "2147483648", err: true},
+		{desc: "error: negative uint32", field: "by_uint32", selector: "-1", err: true},
+		{desc: "error: bad bool", field: "by_bool", selector: "yes", err: true},
+	}
+
+	for _, test := range tests {
+		pp := pathPart{name: test.field, hasSelector: true, selector: test.selector}
+		got, err := pp.mapKey(fields.ByName(protoreflect.Name(test.field)))
+		switch {
+		case err == nil && test.err:
+			t.Errorf("TestMapKey(%s): got err == nil, want err != nil", test.desc)
+			continue
+		case err != nil && !test.err:
+			t.Errorf("TestMapKey(%s): got err == %s, want err == nil", test.desc, err)
+			continue
+		case err != nil:
+			continue
+		}
+		if got.Interface() != test.want {
+			t.Errorf("TestMapKey(%s): got %v(%T), want %v(%T)", test.desc, got.Interface(), got.Interface(), test.want, test.want)
+		}
+	}
+}
+
+func TestGetFieldMap(t *testing.T) {
+	msg := newCustomer()
+	msg.Labels = map[string]string{"env": "prod", "a.b": "dotted"}
+	msg.ById = map[int64]*pb.Order{42: {Id: "order42", Lines: []*pb.Line{{Sku: "sku42"}}}}
+
+	tests := []struct {
+		desc    string
+		fqPath  string
+		wantVal interface{}
+		code    ErrCode
+		err     bool
+	}{
+		{desc: "whole map", fqPath: "labels", wantVal: map[string]string{"env": "prod", "a.b": "dotted"}},
+		{desc: "string key", fqPath: `labels["env"]`, wantVal: "prod"},
+		{desc: "string key with dot", fqPath: `labels["a.b"]`, wantVal: "dotted"},
+		{desc: "message value", fqPath: "by_id[42].id", wantVal: "order42"},
+		{desc: "message value then list", fqPath: "by_id[42].lines[0].sku", wantVal: "sku42"},
+		{desc: "error: missing key", fqPath: `labels["what"]`, err: true, code: ErrKeyNotFound},
+		{desc: "error: unquoted string key", fqPath: "labels[env]", err: true, code: ErrBadPath},
+		{desc: "error: bad int key", fqPath: "by_id[a].id", err: true, code: ErrBadPath},
+		{desc: "error: no key on map", fqPath: "by_id.id", err: true, code: ErrNotMessage},
+	}
+
+	for _, test := range tests {
+		fv, err := GetField(msg, test.fqPath)
+		switch {
+		case err == nil && test.err:
+			t.Errorf("TestGetFieldMap(%s): got err == nil, want err != nil", test.desc)
+			continue
+		case err != nil && !test.err:
+			t.Errorf("TestGetFieldMap(%s): got err == %s, want err == nil", test.desc, err)
+			continue
+		case err != nil:
+			var e Error
+			if !errors.As(err, &e) || e.Code != test.code {
+				t.Errorf("TestGetFieldMap(%s): got err == %s, want code %s", test.desc, err, test.code)
+			}
+			continue
+		}
+
+		if diff := pretty.Compare(test.wantVal, fv.Value); diff != "" {
+			t.Errorf("TestGetFieldMap(%s): -want/+got:\n%s", test.desc, diff)
+		}
+	}
+
+	fv, err := GetField(msg, "by_id")
+	if err != nil {
+		t.Fatalf("TestGetFieldMap(message map): got err == %s, want err == nil", err)
+	}
+	if !fv.IsMap || fv.Kind != protoreflect.MessageKind {
+		t.Fatalf("TestGetFieldMap(message map): got IsMap == %v, Kind == %s, want IsMap == true, Kind == message", fv.IsMap, fv.Kind)
+	}
+	if m, ok := fv.Value.(protoreflect.Map); !ok || m.Len() != 1 {
+		t.Errorf("TestGetFieldMap(message map): got %T, want protoreflect.Map with 1 entry", fv.Value)
+	}
+
+	fv, err = GetField(&pb.MapKeys{BySint64: map[int64]pb.EnumValues{-1: pb.EnumValues_EV_Ok}}, "by_sint64[-1]")
+	if err != nil {
+		t.Fatalf("TestGetFieldMap(enum value): got err == %s, want err == nil", err)
+	}
+	if fv.EnumDesc == nil || fv.EnumDesc.Name() != "EV_Ok" {
+		t.Errorf("TestGetFieldMap(enum value): got EnumDesc %v, want EV_Ok", fv.EnumDesc)
+	}
+}
+
+func TestUpdateProtoFieldMap(t *testing.T) {
+	msg := newCustomer()
+	msg.ById = map[int64]*pb.Order{42: {Id: "order42"}}
+
+	if err := UpdateProtoField(msg, `labels["env"]`, "prod"); err != nil {
+		t.Fatalf("TestUpdateProtoFieldMap(new key): got err == %s, want err == nil", err)
+	}
+	if msg.Labels["env"] != "prod" {
+		t.Errorf("TestUpdateProtoFieldMap(new key): got %q, want %q", msg.Labels["env"], "prod")
+	}
+
+	if err := UpdateProtoField(msg, "by_id[42].id", "changed"); err != nil {
+		t.Fatalf("TestUpdateProtoFieldMap(message value): got err == %s, want err == nil", err)
+	}
+	if msg.ById[42].Id != "changed" {
+		t.Errorf("TestUpdateProtoFieldMap(message value): got %q, want %q", msg.ById[42].Id, "changed")
+	}
+
+	if err := UpdateProtoField(msg, `labels["env"]`, int32(1)); err == nil {
+		t.Errorf("TestUpdateProtoFieldMap(wrong type): got err == nil, want err != nil")
+	}
+	if err := UpdateProtoField(msg, "labels", "prod"); err == nil {
+		t.Errorf("TestUpdateProtoFieldMap(no key): got err == nil, want err != nil")
+	}
+	if err := UpdateProtoField(msg, "by_id[43].id", "changed"); err == nil {
+		t.Errorf("TestUpdateProtoFieldMap(missing message): got err == nil, want err != nil")
+	}
+}
+
+func TestUpdateProtoFieldCreateIntermediates(t *testing.T) {
+	tests := []struct {
+		desc   string
+		msg    proto.Message
+		fqPath string
+		value  interface{}
+		want   proto.Message
+		err    bool
+	}{
+		{
+			desc:   "singular messages",
+			msg:    &pb.Layer0{},
+			fqPath: "layer1.supported.vstring",
+			value:  "hello",
+			want:   &pb.Layer0{Layer1: &pb.Layer1{Supported: &pb.Supported{Vstring: "hello"}}},
+		},
+		{
+			desc:   "appends to lists",
+			msg:    &pb.Customer{Orders: []*pb.Order{{Id: "a"}}},
+			fqPath: "orders[1].lines[0].sku",
+			value:  "sku",
+			want: &pb.Customer{Orders: []*pb.Order{
+				{Id: "a"},
+				{Lines: []*pb.Line{{Sku: "sku"}}},
+			}},
+		},
+		{
+			desc:   "error: index past the end of the list",
+			msg:    &pb.Customer{Orders: []*pb.Order{{Id: "a"}}},
+			fqPath: "orders[2].id",
+			value:  "b",
+			err:    true,
+		},
+		{
+			desc:   "uses existing entries",
+			msg:    &pb.Customer{Orders: []*pb.Order{{Id: "a"}}},
+			fqPath: "orders[-1].id",
+			value:  "b",
+			want:   &pb.Customer{Orders: []*pb.Order{{Id: "b"}}},
+		},
+		{
+			desc:   "adds map keys",
+			msg:    &pb.Customer{},
+			fqPath: "by_id[7].id",
+			value:  "seven",
+			want:   &pb.Customer{ById: map[int64]*pb.Order{7: {Id: "seven"}}},
+		},
+		{
+			desc:   "error: negative index past the start",
+			msg:    &pb.Customer{},
+			fqPath: "orders[-1].id",
+			value:  "b",
+			err:    true,
+		},
+		{
+			desc:   "error: last selector is not created",
+			msg:    &pb.Customer{},
+			fqPath: "tags[0]",
+			value:  "a",
+			err:    true,
+		},
+	}
+
+	for _, test := range tests {
+		err := UpdateProtoField(test.msg, test.fqPath, test.value, CreateIntermediates())
+		switch {
+		case err == nil && test.err:
+			t.Errorf("TestUpdateProtoFieldCreateIntermediates(%s): got err == nil, want err != nil", test.desc)
+			continue
+		case err != nil && !test.err:
+			t.Errorf("TestUpdateProtoFieldCreateIntermediates(%s): got err == %s, want err == nil", test.desc, err)
+			continue
+		case err != nil:
+			continue
+		}
+
+		if diff := cmp.Diff(test.want, test.msg, protocmp.Transform()); diff != "" {
+			t.Errorf("TestUpdateProtoFieldCreateIntermediates(%s): -want/+got:\n%s", test.desc, diff)
+		}
+	}
+}
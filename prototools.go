@@ -9,12 +9,35 @@ Enuerators are CAPITALS_WITH_A_LEADING_WORD_FOR_UNIQUENESS_WITH_UNDERSCORES. Man
 as expected if you are not following these guidelines.
 
 There are two big things that we mostly ignore, maps and arrays. We just don't introspect them except where noted
-as that gets complicated and I don't need the capability at the moment.
+as that gets complicated and I don't need the capability at the moment. The exception is that a path can select
+a single entry of a repeated field with an index, aka "orders[3].lines[0].sku", or a single entry of a map with a key,
+aka `labels["env"]` or "by_id[42].name". Negative indexes count from the end of the list, so "orders[-1]" is
+the last order. Map keys are written as Go literals, so string keys must be quoted. GetAll() also accepts
+a wildcard selector, aka "orders[*].lines[*].price", to get every matching value.
 
-Finally, I am mostly ignoring all the "fixed" types, Any and whatever the types were before Any (my brain can't remember
-what those were called, I wouldn't even use them when I worked at Google, so not doing it here).
+Extensions, which are common in proto2, are put in a path with their full name in parentheses, aka
+"(my.pkg.ext_field).sub". They are found with ExtensionResolver, which is protoregistry.GlobalTypes unless you
+change it.
 
-This "may" work with OneOf's, but I haven't tried.
+A path can step through a google.protobuf.Any, aka "payload.customer.id" where payload is an Any. The
+message inside is unpacked by looking up its type URL in AnyResolver, which is protoregistry.GlobalTypes unless
+you change it. Writes with UpdateProtoField(), SetFieldFromString() and ClearField() pack the message back into
+the Any. The list functions (AppendField(), InsertField(), RemoveField() and SetList()), ApplyFieldMask(),
+FilterByFieldMask() and the patch functions do not step through an Any, a path into one returns ErrBadFieldName.
+The Any's own fields, type_url and value, can still be used unless the packed message has a field with the same name. I am
+still ignoring whatever the types were before Any (my brain can't remember what those were called, I wouldn't
+even use them when I worked at Google, so not doing it here).
+
+Messages don't need generated Go code. LoadDescriptorSet() reads a FileDescriptorSet (a .protoset file) and
+returns a Registry that makes dynamicpb messages, which work with everything in this package. Convert() changes
+messages between the wire format, protojson, prototext and YAML. Reader and Writer handle files of many
+length-delimited messages.
+
+Filter() compiles an AIP-160 filter, like the "filter" parameter of a List call, into a Predicate that uses the
+same paths as GetField().
+
+Fields that are part of a oneof have FieldValue.Oneof set, which tells you if that member is the one that is set.
+WhichOneof() will tell you which member of a oneof is set.
 
 You might ask yourself, why even bother if you don't do these?  Well, most of the time for what this package will get used for,
 which is data exchange for web stuff, these things don't matter. Again, fits my purpose for the moment.  If I need more
@@ -25,6 +48,7 @@ package prototools
 import (
 	"errors"
 	"fmt"
+	"math"
 	"reflect"
 	"strings"
 	"time"
@@ -55,11 +79,35 @@ const (
 	ErrIntermdiateNotSet ErrCode = 2
 	// ErrBadFieldName indicates that one the fields did not exist in the message.
 	// This is not the same as a message having a nil value, which is ErrIntermdiateNotSet.
-	ErrBadFieldName = 3
+	ErrBadFieldName ErrCode = 3
 	// ErrNotMessage indicates that a value in the path is not a message. Commonly this happens when
-	// trying to retrieve a value from a repeated message or map. You cannot pull this directly, you
-	// must get the repeated messaged and then look through each value.
-	ErrNotMessage = 4
+	// trying to retrieve a value from a repeated message or map without selecting an entry,
+	// aka "orders.id" instead of "orders[0].id".
+	ErrNotMessage ErrCode = 4
+	// ErrIndexOutOfRange indicates that an index selector, aka "orders[3]", was outside the
+	// bounds of the repeated field.
+	ErrIndexOutOfRange ErrCode = 5
+	// ErrBadPath indicates that the fqPath could not be parsed or that a selector was
+	// used on a field that does not support it.
+	ErrBadPath ErrCode = 6
+	// ErrKeyNotFound indicates that a key selector, aka `labels["env"]`, was for a key
+	// that is not in the map.
+	ErrKeyNotFound ErrCode = 7
+	// ErrTypeMismatch indicates that a value's Go type cannot be stored in a field of that Kind.
+	ErrTypeMismatch ErrCode = 8
+	// ErrValueOutOfRange indicates that a value was of a type that can be stored in a field, but
+	// the value itself cannot. An example is an int64 that overflows an int32 field or a number
+	// that isn't a value in the enum.
+	ErrValueOutOfRange ErrCode = 9
+	// ErrParse indicates that a string could not be parsed into the type of the field.
+	ErrParse ErrCode = 10
+	// ErrPatchTestFailed indicates that a "test" operation in a JSON Patch found a different value.
+	ErrPatchTestFailed ErrCode = 11
+	// ErrAnyType indicates that the message in a google.protobuf.Any could not be unpacked, usually because
+	// its type is not in AnyResolver.
+	ErrAnyType ErrCode = 12
+	// ErrTypeNotFound indicates that a message type could not be found in a Registry.
+	ErrTypeNotFound ErrCode = 13
 )
 
 // Error is our internal error types with error codes.
@@ -171,47 +219,66 @@ func ReadableProto(s string, options ...ReadableOption) string {
 // doing a string.Title() on all the words. Aka: TYPE_UNKNOWN_DEVICE become: "Unknown Device". A user
 // should not depend on the output of this string, as this may change over time without warning.
 // If the field is _time and an int64, it is assumed to be unix time(epoch) in nanoseconds. If the field is
-// a message, we protojson.Marshal() it. float or double values are printed out with 2 decimal places rounded up.
-// We only support these values: boo, string, int32, int64, float, double, enum and message. We do not supports groups (repeated).
+// a message, we protojson.Marshal() it, except for well-known types: a Timestamp is printed as a time, a
+// Duration as a time.Duration and a wrapper (StringValue, Int64Value, ...) as the value it wraps. float or double values are printed out with 2 decimal places rounded up.
+// We only support these values: boo, string, int32, int64, float, double, enum and message. We do not supports groups (repeated),
+// but you can get a single entry of a repeated field or map with a selector, aka "tags[0]" or `labels["env"]`.
 func FieldAsStr(msg proto.Message, fqPath string, pretty bool) (string, protoreflect.Kind, error) {
 	fv, err := GetField(msg, fqPath)
 	if err != nil {
 		return "", 0, err
 	}
+	if fv.IsList || fv.IsMap {
+		return "", fv.Kind, fmt.Errorf("type not supported: field(%s) is a repeated field or map", fqPath)
+	}
 
+	str, err := valueAsStr(fv, fqPath, pretty)
+	return str, fv.Kind, err
+}
+
+// valueAsStr implements FieldAsStr() for a single value. name is the name or path of the field.
+func valueAsStr(fv FieldValue, name string, pretty bool) (string, error) {
 	switch fv.Kind {
 	case protoreflect.BoolKind:
 		if pretty {
-			return strings.Title(fmt.Sprintf("%v", fv.Value)), fv.Kind, nil
+			return strings.Title(fmt.Sprintf("%v", fv.Value)), nil
 		}
-		return fmt.Sprintf("%v", fv.Value), fv.Kind, nil
+		return fmt.Sprintf("%v", fv.Value), nil
 	case protoreflect.StringKind:
-		return fv.Value.(string), fv.Kind, nil
+		return fv.Value.(string), nil
 	case protoreflect.BytesKind:
-		return fmt.Sprintf("[%d]bytes", len(fv.Value.([]byte))), fv.Kind, nil
+		return fmt.Sprintf("[%d]bytes", len(fv.Value.([]byte))), nil
 	case protoreflect.Int32Kind:
-		return fmt.Sprintf("%v", fv.Value), fv.Kind, nil
+		return fmt.Sprintf("%v", fv.Value), nil
 	case protoreflect.Int64Kind:
-		if strings.HasSuffix(fqPath, "_time") {
+		if strings.HasSuffix(name, "_time") {
 			t := time.Unix(fv.Value.(int64), 0).Truncate(0).UTC()
-			return fmt.Sprintf("%v", t), fv.Kind, nil
+			return fmt.Sprintf("%v", t), nil
 		}
-		return fmt.Sprintf("%v", fv.Value), fv.Kind, nil
+		return fmt.Sprintf("%v", fv.Value), nil
 	case protoreflect.FloatKind, protoreflect.DoubleKind:
-		return fmt.Sprintf("%.2f", fv.Value), fv.Kind, nil
+		return fmt.Sprintf("%.2f", fv.Value), nil
 	case protoreflect.EnumKind:
+		if fv.EnumDesc == nil {
+			return fmt.Sprintf("%v", fv.Value), nil
+		}
 		if pretty {
-			return prettyEnum(string(fv.EnumDesc.Name())), fv.Kind, nil
+			return prettyEnum(string(fv.EnumDesc.Name())), nil
 		}
-		return string(fv.EnumDesc.Name()), fv.Kind, nil
+		return string(fv.EnumDesc.Name()), nil
 	case protoreflect.MessageKind:
+		if m, ok := fv.Value.(proto.Message); ok {
+			if s, ok := wellKnownStr(m.ProtoReflect(), pretty); ok {
+				return s, nil
+			}
+		}
 		b, err := protojson.Marshal(fv.Value.(proto.Message))
 		if err != nil {
-			return "", fv.Kind, err
+			return "", err
 		}
-		return string(b), fv.Kind, nil
+		return string(b), nil
 	}
-	return "", fv.Kind, fmt.Errorf("type not supported")
+	return "", fmt.Errorf("type not supported")
 }
 
 func protoToTitled(s string) string {
@@ -241,9 +308,42 @@ func prettyEnum(s string) string {
 	return b.String()
 }
 
-// FQPathSplit separates fqpath at ".".
+// FQPathSplit separates fqpath at ".". A "." inside of a selector, aka "[...]", inside a quoted
+// map key, aka `labels["a.b"]`, or inside an extension name, aka "(my.pkg.ext_field)", does not cause a split.
 func FQPathSplit(fqpath string) []string {
-	return strings.Split(fqpath, ".")
+	sp := []string{}
+	depth := 0
+	start := 0
+	quoted := false
+	for i := 0; i < len(fqpath); i++ {
+		if quoted {
+			switch fqpath[i] {
+			case '\\':
+				i++
+			case '"':
+				quoted = false
+			}
+			continue
+		}
+		switch fqpath[i] {
+		case '"':
+			if depth > 0 {
+				quoted = true
+			}
+		case '[', '(':
+			depth++
+		case ']', ')':
+			if depth > 0 {
+				depth--
+			}
+		case '.':
+			if depth == 0 {
+				sp = append(sp, fqpath[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(sp, fqpath[start:])
 }
 
 // FQPathField extracts just the field's name.
@@ -262,16 +362,23 @@ type FieldValue struct {
 	// can be a nil value stored in an interface{}. That means Value != nil, but
 	// the value inside is nil (yeah, I know). Had to do this for certain reasons.
 	// There is a IsNil() method if you want to test if the stored value == nil.
-	// If the value is a list and the type is a base type, then it will be []<type>.
+	// If the value is a list and the type is a base type, then it will be []<type>, using the table in GetField().
 	// However, it it is a message, this will be []protoreflect.Message, because we have no way
 	// to get to the concrete type. You can use Kind to determine what you need to do.
 	// If it is an Enum, we leave it as a protoreflect.EnumNumber, which is really an int32.
+	// If the value is a map, it will be map[<key type>]<value type>, unless the values are messages,
+	// in which case it will be a protoreflect.Map.
 	Value interface{}
 	// Kind is the proto Kind that was stored. If IsList == true, Kind represents the underlying
-	// value stored in the list.
+	// value stored in the list. If IsMap == true, Kind represents the values stored in the map.
 	Kind protoreflect.Kind
 	// IsList is set if the Kind == MessageKind, but the message represents a repeated value.
 	IsList bool
+	// List is the protoreflect.List for the field if IsList is set. This is for callers that would
+	// rather work with the list directly than with .Value.
+	List protoreflect.List
+	// IsMap is set if the field is a map. FieldDesc.MapKey() describes the keys of the map.
+	IsMap bool
 	// FieldDesc is the field descriptor for this value.
 	FieldDesc protoreflect.FieldDescriptor
 	// EnumDesc is the enumerator descriptor if the Kind was EnumKind.
@@ -280,6 +387,12 @@ type FieldValue struct {
 	EnumDesc protoreflect.EnumValueDescriptor
 	// MsgDesc is the message descriptor if the Kind was MessageKind.
 	MsgDesc protoreflect.MessageDescriptor
+	// Oneof is set if the field is a member of a oneof. This is nil otherwise.
+	Oneof *OneofInfo
+	// WellKnown is the Go value of a single well-known type message that is set, such as a time.Time for a
+	// google.protobuf.Timestamp or the string in a google.protobuf.StringValue. See GetField() for the types.
+	// This is nil for other fields. .Value still holds the message.
+	WellKnown interface{}
 }
 
 // IsNil determins if the value stored in .Value is nil.
@@ -295,7 +408,10 @@ GetField searches into a proto to get a field value. It returns the value as
 an interface{}, the kind of the field and if the field was found. You use a "."
 notation to dive into the proto (field.field.field , where everything but the
 last must be a Message type). We use the proto file spelling, not JSON or local
-language spellings of the fields. You cannot look into groups (repeated values/array/slice...).
+language spellings of the fields. To look into a repeated field, select an entry
+with an index: "orders[3].lines[-1].sku". If the last field has an index, the FieldValue
+is for that single entry and .IsList will be false. A path can step through a google.protobuf.Any,
+see the package documentation.
 
 The following is the kind to Go type mapping:
 
@@ -315,37 +431,56 @@ The following is the kind to Go type mapping:
 	║ Message    │ MessageKind, GroupKind              ║
 	╚════════════╧═════════════════════════════════════╝
 
+If the field is a single well-known type message that is set, FieldValue.WellKnown also has its Go value:
+
+	╔═══════════════════════╤════════════════════════════════════════════╗
+	║ Well-known type       │ Go type                                    ║
+	╠═══════════════════════╪════════════════════════════════════════════╣
+	║ Timestamp             │ time.Time, in UTC                          ║
+	║ Duration              │ time.Duration                              ║
+	║ DoubleValue, ...      │ the Go type above for the wrapped kind     ║
+	║ Struct                │ map[string]interface{}                     ║
+	║ ListValue             │ []interface{}                              ║
+	║ Value                 │ interface{}, as encoding/json decodes it   ║
+	╚═══════════════════════╧════════════════════════════════════════════╝
+
 */
 func GetField(msg proto.Message, fqPath string) (FieldValue, error) {
 	fields := FQPathSplit(fqPath)
-	for x, field := range fields[0 : len(fields)-1] {
-		fv, err := fieldValue(msg, field)
-		if err != nil {
-			return FieldValue{}, Errorf(ErrBadFieldName, "field(%s) could not be found", strings.Join(fields[0:x], "."))
-		}
-		if fv.Kind != protoreflect.MessageKind {
-			return FieldValue{}, Errorf(ErrIntermediateNotMessage, "field(%s) should be a message, was a %s", strings.Join(fields[0:x], "."), fv.Kind)
-		}
-		if fv.Value == nil {
-			return FieldValue{}, Errorf(ErrIntermdiateNotSet, "message field(%s) is an empty message", strings.Join(fields[0:x], "."))
-		}
-		var ok bool
-		msg, ok = fv.Value.(proto.Message)
-		if !ok {
-			return FieldValue{}, Errorf(ErrNotMessage, "message field(%s) is not a proto.Message. Usually this means you are trying to retrieve inside a repeated field or map, which cannot be done", strings.Join(fields[0:x], "."))
-		}
+	msg, err := walkMessages(msg, fields[0:len(fields)-1])
+	if err != nil {
+		return FieldValue{}, err
 	}
-
-	fv, err := fieldValue(msg, fields[len(fields)-1])
+	msg, err = unpackAny(msg, fields[len(fields)-1], anyPath(fields, len(fields)-1))
 	if err != nil {
-		return FieldValue{}, Errorf(ErrBadFieldName, "field(%s) could not be found", fqPath)
+		return FieldValue{}, err
+	}
+	return partValue(msg, fields[len(fields)-1], fqPath)
+}
+
+type getFieldsOpts struct {
+	skipUnsetOneofs bool
+}
+
+// GetFieldsOption is an option for GetFields().
+type GetFieldsOption func(o *getFieldsOpts)
+
+// SkipUnsetOneofs causes GetFields() to not return fields that are members of a oneof, unless
+// that member is the one that is set.
+func SkipUnsetOneofs() GetFieldsOption {
+	return func(o *getFieldsOpts) {
+		o.skipUnsetOneofs = true
 	}
-	return fv, nil
 }
 
 // GetFields takes a path that must end in a Message type and returns a list of FieldValue(s) for that message. If fqPath is "", will return
 // fields of msg.
-func GetFields(msg proto.Message, fqPath string) ([]FieldValue, error) {
+func GetFields(msg proto.Message, fqPath string, options ...GetFieldsOption) ([]FieldValue, error) {
+	opts := getFieldsOpts{}
+	for _, o := range options {
+		o(&opts)
+	}
+
 	var fv FieldValue
 	var fields []string
 	if fqPath == "" {
@@ -356,30 +491,16 @@ func GetFields(msg proto.Message, fqPath string) ([]FieldValue, error) {
 		}
 	} else {
 		fields = FQPathSplit(fqPath)
-		for x, field := range fields[0 : len(fields)-1] {
-			fv, err := fieldValue(msg, field)
-			if err != nil {
-				return nil, Errorf(ErrBadFieldName, "field(%s) could not be found", strings.Join(fields[0:x], "."))
-			}
-			if fv.Kind != protoreflect.MessageKind {
-				return nil, Errorf(ErrIntermediateNotMessage, "field(%s) should be a message, was a %s", strings.Join(fields[0:x], "."), fv.Kind)
-			}
-			if fv.Value == nil {
-				return nil, Errorf(ErrIntermdiateNotSet, "message field(%s) is an empty message", strings.Join(fields[0:x], "."))
-			}
-			var ok bool
-			msg, ok = fv.Value.(proto.Message)
-			if !ok {
-				return nil, Errorf(ErrNotMessage, "message field(%s) is not a proto.Message. Usually this means you are trying to retrieve inside a repeated field or map, which cannot be done", strings.Join(fields[0:x], "."))
-			}
-		}
 		var err error
-		fv, err = fieldValue(msg, fields[len(fields)-1])
+		fv, err = GetField(msg, fqPath)
 		if err != nil {
-			return nil, Errorf(ErrBadFieldName, "field(%s) could not be found", fqPath)
+			return nil, err
 		}
 		if fv.Kind != protoreflect.MessageKind {
-			return nil, Errorf(ErrIntermediateNotMessage, "field(%s) should be a message, was a %s", strings.Join(fields, "."), fv.Kind)
+			return nil, Errorf(ErrIntermediateNotMessage, "field(%s) should be a message, was a %s", fqPath, fv.Kind)
+		}
+		if err := notMessageErr(fv, fqPath); err != nil {
+			return nil, err
 		}
 	}
 
@@ -392,6 +513,9 @@ func GetFields(msg proto.Message, fqPath string) ([]FieldValue, error) {
 		if err != nil {
 			return nil, Errorf(ErrUnknown, "field(%s) had an unknown error: %s", strings.Join(append(fields, string(desc.Name())), "."), err)
 		}
+		if opts.skipUnsetOneofs && fv.Oneof != nil && !fv.Oneof.Active {
+			continue
+		}
 		fvs = append(fvs, fv)
 	}
 	return fvs, nil
@@ -399,28 +523,55 @@ func GetFields(msg proto.Message, fqPath string) ([]FieldValue, error) {
 
 // getLastMessage will takes the path and returns the len(fqPath) -1 proto.Message. If createmessage is set, this will create the
 // message values if they are not set through the entire path except the inital message passed as "msg".
-func getLastMessage(msg proto.Message, fqPath []string, createMessages bool) (protoreflect.Message, error) {
+// If anys is not nil, the path can step through google.protobuf.Any messages and the messages unpacked from them
+// are recorded in anys, so they can be packed again after they are changed.
+func getLastMessage(msg proto.Message, fqPath []string, createMessages bool, anys *anyStack) (protoreflect.Message, error) {
+	return lastMessage(msg, fqPath, createMessages, nil, anys)
+}
+
+// lastMessage implements getLastMessage(). If a created message is a member of a oneof whose other member
+// is set, the other member is cleared and the change is added to changes, if it is not nil.
+func lastMessage(msg proto.Message, fqPath []string, createMessages bool, changes *[]OneofChange, anys *anyStack) (protoreflect.Message, error) {
 	fields := fqPath[0 : len(fqPath)-1]
 	for x, field := range fields {
-		fv, err := fieldValue(msg, field)
+		path := strings.Join(fields[0:x+1], ".")
+		if anys != nil {
+			var err error
+			if msg, err = anys.unpack(msg, field, anyPath(fields, x)); err != nil {
+				return nil, err
+			}
+		}
+		if createMessages {
+			m, err := createPart(msg.ProtoReflect(), field, path, changes)
+			if err != nil {
+				return nil, err
+			}
+			msg = m.Interface()
+			continue
+		}
+
+		fv, err := partValue(msg, field, path)
 		if err != nil {
-			return nil, Errorf(ErrBadFieldName, "field(%s) could not be found", strings.Join(fields[0:x], "."))
+			return nil, err
 		}
 		if fv.Kind != protoreflect.MessageKind {
-			return nil, Errorf(ErrIntermediateNotMessage, "field(%s) should be a message, was a %s", strings.Join(fields[0:x], "."), fv.Kind)
-		}
-		if fv.IsNil() {
-			if createMessages {
-				fieldMsg := fv.Value.(proto.Message).ProtoReflect()
-				n := fieldMsg.New()
-				msg.ProtoReflect().Set(fv.FieldDesc, protoreflect.ValueOf(n))
-				msg = n.Interface()
-				continue
-			}
-			return nil, Errorf(ErrIntermdiateNotSet, "message field(%s) is an empty message", strings.Join(fields[0:x], "."))
+			return nil, Errorf(ErrIntermediateNotMessage, "field(%s) should be a message, was a %s", path, fv.Kind)
+		}
+		if err := notMessageErr(fv, path); err != nil {
+			return nil, err
+		}
+		// A dynamic message returns an invalid, read-only message for an unset field instead of nil.
+		if fv.IsNil() || !fv.Value.(proto.Message).ProtoReflect().IsValid() {
+			return nil, Errorf(ErrIntermdiateNotSet, "message field(%s) is an empty message", path)
 		}
 		msg = fv.Value.(proto.Message)
 	}
+	if anys != nil {
+		var err error
+		if msg, err = anys.unpack(msg, fqPath[len(fqPath)-1], anyPath(fqPath, len(fields))); err != nil {
+			return nil, err
+		}
+	}
 	return msg.ProtoReflect(), nil
 }
 
@@ -435,8 +586,7 @@ func fieldValue(msg proto.Message, field string) (FieldValue, error) {
 	}
 
 	ref := msg.ProtoReflect()
-	descriptors := ref.Descriptor().Fields()
-	fd := descriptors.ByName(protoreflect.Name(field))
+	fd := partField(ref.Descriptor(), field)
 	if fd == nil {
 		return FieldValue{}, errors.New("bad field name")
 	}
@@ -445,110 +595,43 @@ func fieldValue(msg proto.Message, field string) (FieldValue, error) {
 	case fd.IsList():
 		return listFieldValue(ref, fd)
 	case fd.IsMap():
-		return FieldValue{}, errors.New("we do not currently support maps")
-	case fd.IsExtension():
-		return FieldValue{}, errors.New("we do not currently support extensions")
+		return mapFieldValue(ref, fd)
 	}
 
+	fv := FieldValue{
+		Kind:      fd.Kind(),
+		FieldDesc: fd,
+		Oneof:     oneofInfo(ref, fd),
+	}
 	switch fd.Kind() {
 	case protoreflect.MessageKind:
-		var v = ref.Get(fd).Message().Interface()
-		return FieldValue{
-			Value:     v,
-			Kind:      protoreflect.MessageKind,
-			FieldDesc: fd,
-			MsgDesc:   fd.Message(),
-		}, nil
+		fv.Value = ref.Get(fd).Message().Interface()
+		fv.MsgDesc = fd.Message()
+		if ref.Has(fd) {
+			fv.WellKnown, _ = wellKnownValue(ref.Get(fd).Message())
+		}
 	case protoreflect.EnumKind:
 		i := ref.Get(fd).Interface()
 		enumDesc := fd.Enum().Values().ByNumber(i.(protoreflect.EnumNumber))
-		return FieldValue{
-			Value:     protoreflect.ValueOfEnum(enumDesc.Number()).Interface(),
-			Kind:      protoreflect.EnumKind,
-			FieldDesc: fd,
-			EnumDesc:  enumDesc,
-		}, nil
+		fv.Value = protoreflect.ValueOfEnum(enumDesc.Number()).Interface()
+		fv.EnumDesc = enumDesc
+	default:
+		fv.Value = ref.Get(fd).Interface()
 	}
-	return FieldValue{
-		Value:     ref.Get(fd).Interface(),
-		Kind:      fd.Kind(),
-		FieldDesc: fd,
-	}, nil
+	return fv, nil
 }
 
 func listFieldValue(ref protoreflect.Message, fd protoreflect.FieldDescriptor) (FieldValue, error) {
-	// These are currently unsupported.
-	/*
-		Int32Kind    Kind = 5
-		Sint32Kind   Kind = 17
-		Uint32Kind   Kind = 13
-		Sint64Kind   Kind = 18
-		Uint64Kind   Kind = 4
-		Sfixed32Kind Kind = 15
-		Fixed32Kind  Kind = 7
-		Sfixed64Kind Kind = 16
-		Fixed64Kind  Kind = 6
-		BytesKind    Kind = 12
-		GroupKind    Kind = 10
-	*/
-
+	var l = ref.Get(fd).List()
 	fv := FieldValue{
 		Kind:      fd.Kind(),
 		IsList:    true,
 		FieldDesc: fd,
+		List:      l,
 	}
-	var l = ref.Get(fd).List()
+
 	switch fd.Kind() {
-	case protoreflect.BoolKind:
-		v := make([]bool, l.Len())
-		for i := 0; i < l.Len(); i++ {
-			entry := l.Get(i)
-			v[i] = entry.Bool()
-		}
-		fv.Value = v
-	case protoreflect.Int32Kind:
-		v := make([]int32, l.Len())
-		for i := 0; i < l.Len(); i++ {
-			entry := l.Get(i)
-			v[i] = int32(entry.Int())
-		}
-		fv.Value = v
-	case protoreflect.Int64Kind:
-		v := make([]int64, l.Len())
-		for i := 0; i < l.Len(); i++ {
-			entry := l.Get(i)
-			v[i] = int64(entry.Int())
-		}
-		fv.Value = v
-	case protoreflect.FloatKind:
-		v := make([]float32, l.Len())
-		for i := 0; i < l.Len(); i++ {
-			entry := l.Get(i)
-			v[i] = float32(entry.Float())
-		}
-		fv.Value = v
-	case protoreflect.DoubleKind:
-		v := make([]float64, l.Len())
-		for i := 0; i < l.Len(); i++ {
-			entry := l.Get(i)
-			v[i] = entry.Float()
-		}
-		fv.Value = v
-	case protoreflect.StringKind:
-		v := make([]string, l.Len())
-		for i := 0; i < l.Len(); i++ {
-			entry := l.Get(i)
-			v[i] = entry.String()
-		}
-		fv.Value = v
-	case protoreflect.EnumKind:
-		v := make([]protoreflect.EnumNumber, l.Len())
-		for i := 0; i < l.Len(); i++ {
-			entry := l.Get(i)
-			v[i] = entry.Enum()
-		}
-		fv.Value = v
-	case protoreflect.MessageKind:
+	case protoreflect.MessageKind, protoreflect.GroupKind:
 		v := make([]protoreflect.Message, l.Len())
 		for i := 0; i < l.Len(); i++ {
 			entry := l.Get(i)
@@ -556,118 +639,350 @@ func listFieldValue(ref protoreflect.Message, fd protoreflect.FieldDescriptor) (
 		}
 		fv.Value = v
 		fv.MsgDesc = fd.Message()
-	default:
+		return fv, nil
+	}
+
+	t, ok := goTypes[fd.Kind()]
+	if !ok {
 		return FieldValue{}, fmt.Errorf("we do not support a list value of this type(%s)", fd.Kind())
 	}
+	v := reflect.MakeSlice(reflect.SliceOf(t), l.Len(), l.Len())
+	for i := 0; i < l.Len(); i++ {
+		v.Index(i).Set(reflect.ValueOf(l.Get(i).Interface()))
+	}
+	fv.Value = v.Interface()
+	return fv, nil
+}
+
+func mapFieldValue(ref protoreflect.Message, fd protoreflect.FieldDescriptor) (FieldValue, error) {
+	vd := fd.MapValue()
+	fv := FieldValue{
+		Kind:      vd.Kind(),
+		IsMap:     true,
+		FieldDesc: fd,
+	}
+	m := ref.Get(fd).Map()
+
+	if vd.Kind() == protoreflect.MessageKind {
+		fv.Value = m
+		fv.MsgDesc = vd.Message()
+		return fv, nil
+	}
+
+	kt, ok := goTypes[fd.MapKey().Kind()]
+	if !ok {
+		return FieldValue{}, fmt.Errorf("we do not support a map key of this type(%s)", fd.MapKey().Kind())
+	}
+	vt, ok := goTypes[vd.Kind()]
+	if !ok {
+		return FieldValue{}, fmt.Errorf("we do not support a map value of this type(%s)", vd.Kind())
+	}
+
+	v := reflect.MakeMapWithSize(reflect.MapOf(kt, vt), m.Len())
+	m.Range(func(k protoreflect.MapKey, mv protoreflect.Value) bool {
+		v.SetMapIndex(reflect.ValueOf(k.Interface()), reflect.ValueOf(mv.Interface()))
+		return true
+	})
+	fv.Value = v.Interface()
 	return fv, nil
 }
 
+// goTypes maps scalar protoreflect.Kind values to the Go type that represents them.
+// This follows the table in GetField().
+var goTypes = map[protoreflect.Kind]reflect.Type{
+	protoreflect.BoolKind:     reflect.TypeOf(false),
+	protoreflect.EnumKind:     reflect.TypeOf(protoreflect.EnumNumber(0)),
+	protoreflect.Int32Kind:    reflect.TypeOf(int32(0)),
+	protoreflect.Sint32Kind:   reflect.TypeOf(int32(0)),
+	protoreflect.Sfixed32Kind: reflect.TypeOf(int32(0)),
+	protoreflect.Int64Kind:    reflect.TypeOf(int64(0)),
+	protoreflect.Sint64Kind:   reflect.TypeOf(int64(0)),
+	protoreflect.Sfixed64Kind: reflect.TypeOf(int64(0)),
+	protoreflect.Uint32Kind:   reflect.TypeOf(uint32(0)),
+	protoreflect.Fixed32Kind:  reflect.TypeOf(uint32(0)),
+	protoreflect.Uint64Kind:   reflect.TypeOf(uint64(0)),
+	protoreflect.Fixed64Kind:  reflect.TypeOf(uint64(0)),
+	protoreflect.FloatKind:    reflect.TypeOf(float32(0)),
+	protoreflect.DoubleKind:   reflect.TypeOf(float64(0)),
+	protoreflect.StringKind:   reflect.TypeOf(""),
+	protoreflect.BytesKind:    reflect.TypeOf([]byte(nil)),
+}
+
 type enumDescriptor interface {
 	Descriptor() protoreflect.EnumDescriptor
 	Number() protoreflect.EnumNumber
 }
 
+type updateOpts struct {
+	onOneofChange  func(OneofChange)
+	createMessages bool
+}
+
+// UpdateOption is an option for UpdateProtoField().
+type UpdateOption func(o *updateOpts)
+
+// CreateIntermediates has UpdateProtoField() create intermediate messages that are not set instead of
+// returning an error. This works through selectors: a map key that does not exist is added with a new message
+// and an index equal to the length of a repeated field appends a new message, so "orders[0].id" on a message
+// without orders creates an order. Any other index must already exist, otherwise the error has the code
+// ErrIndexOutOfRange. Selectors on the last field are not affected.
+func CreateIntermediates() UpdateOption {
+	return func(o *updateOpts) {
+		o.createMessages = true
+	}
+}
+
+// OnOneofChange has UpdateProtoField() call f if the update caused a different member of a oneof
+// to be cleared. This includes intermediate messages made by CreateIntermediates(), so setting
+// "payment.card.number" when "payment.voucher" is set reports that the voucher was cleared.
+func OnOneofChange(f func(OneofChange)) UpdateOption {
+	return func(o *updateOpts) {
+		o.onOneofChange = f
+	}
+}
+
 // UpdateProtoField updates a field in a protocol buffer message with a value.
-// The field is assumed to be the proto name format.
-// This only supports values of string, int, int32, int64 and bool. An int updates an int64.
-func UpdateProtoField(m proto.Message, fqPath string, value interface{}) error {
-	fields := FQPathSplit(fqPath)
-	if len(fields) == 0 {
-		return fmt.Errorf("cannot send a path(%s) of zero len", fqPath)
+// The field is assumed to be the proto name format. An entry in a repeated field can be
+// updated by using an index on the last field, aka "orders[0].lines[-1].sku". An entry in a map
+// can be set by using a key on the last field, aka `labels["env"]`. If the key does not exist, it is added.
+// value can be any Go type from the table in GetField(), an enum value from the generated Go code,
+// a proto.Message of the field's message type or any Go integer type. An integer can be stored in any integer or enum field as long as it fits, so an int64
+// can update an int32 field if the value is in range, but an error is returned if it would overflow.
+// A float64 can update a float field in the same way.
+// If the field is a member of a oneof, any other member of the oneof that is set is cleared.
+// Use OnOneofChange() to be told when that happens.
+// If an intermediate message is not set, this returns an error with code ErrIntermdiateNotSet, unless
+// CreateIntermediates() is passed.
+func UpdateProtoField(m proto.Message, fqPath string, value interface{}, options ...UpdateOption) error {
+	return updateField(m, fqPath, options, func(_ protoreflect.Message, fd protoreflect.FieldDescriptor) (protoreflect.Value, error) {
+		return protoValue(fd, value)
+	})
+}
+
+// valueFunc converts a value into a protoreflect.Value for field fd in msg.
+type valueFunc func(msg protoreflect.Message, fd protoreflect.FieldDescriptor) (protoreflect.Value, error)
+
+// updateField implements UpdateProtoField(), using conv to convert the value for the field.
+func updateField(m proto.Message, fqPath string, options []UpdateOption, conv valueFunc) error {
+	opts := updateOpts{}
+	for _, o := range options {
+		o(&opts)
 	}
-	fieldName := fields[len(fields)-1]
 
-	v, err := getLastMessage(m, fields, false)
+	var (
+		anys    anyStack
+		changes []OneofChange
+	)
+	v, fd, pp, err := lastField(m, fqPath, opts.createMessages, &changes, &anys)
 	if err != nil {
 		return err
 	}
 
-	fd := v.Descriptor().Fields().ByName(protoreflect.Name(fieldName))
-	if fd == nil {
-		return fmt.Errorf("field %s not found", fieldName)
+	val, err := conv(v, fd)
+	if err != nil {
+		return err
 	}
-	switch t := value.(type) {
-	case string:
-		if fd.Kind() != protoreflect.StringKind {
-			return fmt.Errorf("field %s is a %s, you sent a string", fieldName, fd.Kind())
+
+	if !pp.hasSelector {
+		if change, cleared := clearOneof(v, fd, fqPath); cleared {
+			changes = append(changes, change)
 		}
-		v.Set(fd, protoreflect.ValueOf(t))
-	case int:
-		if fd.Kind() != protoreflect.Int64Kind {
-			return fmt.Errorf("field %s is a %s, you sent a int64", fieldName, fd.Kind())
+	}
+	if err := setPart(v, fd, pp, val, fqPath); err != nil {
+		return err
+	}
+	if err := anys.repack(); err != nil {
+		return err
+	}
+	if opts.onOneofChange != nil {
+		for _, change := range changes {
+			opts.onOneofChange(change)
 		}
-		v.Set(fd, protoreflect.ValueOf(int64(t)))
+	}
+	return nil
+}
+
+// protoValue converts value into a protoreflect.Value that can be stored in the field described by fd.
+// If fd is a map, this converts to the map's value type. Any Go integer type can be stored in any
+// integer or enum field as long as the value fits.
+func protoValue(fd protoreflect.FieldDescriptor, value interface{}) (protoreflect.Value, error) {
+	fieldName := fd.Name()
+	fd = valueDesc(fd)
+
+	if v, ok, err := wellKnownProto(fd, fieldName, value); ok {
+		return v, err
+	}
+
+	switch t := value.(type) {
+	case int:
+		return intValue(fd, fieldName, int64(t), value)
+	case int8:
+		return intValue(fd, fieldName, int64(t), value)
+	case int16:
+		return intValue(fd, fieldName, int64(t), value)
+	case int32:
+		return intValue(fd, fieldName, int64(t), value)
 	case int64:
-		if fd.Kind() != protoreflect.Int64Kind {
-			return fmt.Errorf("field %s is a %s, you sent a int64", fieldName, fd.Kind())
+		return intValue(fd, fieldName, t, value)
+	case uint:
+		return uintValue(fd, fieldName, uint64(t), value)
+	case uint8:
+		return uintValue(fd, fieldName, uint64(t), value)
+	case uint16:
+		return uintValue(fd, fieldName, uint64(t), value)
+	case uint32:
+		return uintValue(fd, fieldName, uint64(t), value)
+	case uint64:
+		return uintValue(fd, fieldName, t, value)
+	case float32:
+		return floatValue(fd, fieldName, float64(t), value)
+	case float64:
+		return floatValue(fd, fieldName, t, value)
+	case string:
+		if fd.Kind() != protoreflect.StringKind {
+			return protoreflect.Value{}, mismatchErr(fd, fieldName, value)
 		}
-		v.Set(fd, protoreflect.ValueOf(t))
-	case int32:
-		switch fd.Kind() {
-		case protoreflect.Int32Kind:
-			v.Set(fd, protoreflect.ValueOf(t))
-		case protoreflect.EnumKind:
-			n := protoreflect.EnumNumber(t)
-			if exists := fd.Enum().Values().ByNumber(n); exists == nil {
-				return fmt.Errorf("field %s is an enum and %d is not a valid value", fieldName, t)
-			}
-			enum := protoreflect.ValueOfEnum(n)
-			v.Set(fd, enum)
-		default:
-			return fmt.Errorf("field %s is a %s, you sent a int32", fieldName, fd.Kind())
+		return protoreflect.ValueOfString(t), nil
+	case []byte:
+		if fd.Kind() != protoreflect.BytesKind {
+			return protoreflect.Value{}, mismatchErr(fd, fieldName, value)
 		}
+		return protoreflect.ValueOfBytes(t), nil
 	case bool:
 		if fd.Kind() != protoreflect.BoolKind {
-			return fmt.Errorf("field %s is a %s, you sent a bool", fieldName, fd.Kind())
+			return protoreflect.Value{}, mismatchErr(fd, fieldName, value)
 		}
-		v.Set(fd, protoreflect.ValueOf(t))
+		return protoreflect.ValueOfBool(t), nil
+	case protoreflect.EnumNumber:
+		return intValue(fd, fieldName, int64(t), value)
 	case enumDescriptor:
-		n := t.Number()
-		enum := protoreflect.ValueOfEnum(n)
-		v.Set(fd, enum)
-	default:
-		return fmt.Errorf("field %s cannot be set to %T, as that type isn't supported", fieldName, value)
+		if fd.Kind() != protoreflect.EnumKind || t.Descriptor().FullName() != fd.Enum().FullName() {
+			return protoreflect.Value{}, mismatchErr(fd, fieldName, value)
+		}
+		return protoreflect.ValueOfEnum(t.Number()), nil
+	case proto.Message:
+		return messageValue(fd, fieldName, t.ProtoReflect())
+	case protoreflect.Message:
+		return messageValue(fd, fieldName, t)
 	}
-	return nil
+	return protoreflect.Value{}, Errorf(ErrTypeMismatch, "field(%s) cannot be set to %T, as that type isn't supported", fieldName, value)
+}
+
+// messageValue checks that m can be stored in the message field fd.
+func messageValue(fd protoreflect.FieldDescriptor, fieldName protoreflect.Name, m protoreflect.Message) (protoreflect.Value, error) {
+	if fd.Kind() != protoreflect.MessageKind && fd.Kind() != protoreflect.GroupKind {
+		return protoreflect.Value{}, mismatchErr(fd, fieldName, m.Interface())
+	}
+	if m.Descriptor().FullName() != fd.Message().FullName() {
+		return protoreflect.Value{}, Errorf(ErrTypeMismatch, "field(%s) is a %s, you sent a %s", fieldName, fd.Message().FullName(), m.Descriptor().FullName())
+	}
+	return protoreflect.ValueOfMessage(m), nil
+}
+
+func mismatchErr(fd protoreflect.FieldDescriptor, fieldName protoreflect.Name, value interface{}) error {
+	return Errorf(ErrTypeMismatch, "field(%s) is a %s, you sent a %T", fieldName, fd.Kind(), value)
+}
+
+func rangeErr(fd protoreflect.FieldDescriptor, fieldName protoreflect.Name, value interface{}) error {
+	return Errorf(ErrValueOutOfRange, "field(%s) is a %s, value %v(%T) does not fit", fieldName, fd.Kind(), value, value)
+}
+
+// intValue converts a signed integer, i, into a value for an integer or enum field. value is the
+// original value and is used in error messages.
+func intValue(fd protoreflect.FieldDescriptor, fieldName protoreflect.Name, i int64, value interface{}) (protoreflect.Value, error) {
+	switch fd.Kind() {
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		if i < math.MinInt32 || i > math.MaxInt32 {
+			return protoreflect.Value{}, rangeErr(fd, fieldName, value)
+		}
+		return protoreflect.ValueOfInt32(int32(i)), nil
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return protoreflect.ValueOfInt64(i), nil
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		if i < 0 || i > math.MaxUint32 {
+			return protoreflect.Value{}, rangeErr(fd, fieldName, value)
+		}
+		return protoreflect.ValueOfUint32(uint32(i)), nil
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		if i < 0 {
+			return protoreflect.Value{}, rangeErr(fd, fieldName, value)
+		}
+		return protoreflect.ValueOfUint64(uint64(i)), nil
+	case protoreflect.EnumKind:
+		if i < math.MinInt32 || i > math.MaxInt32 {
+			return protoreflect.Value{}, rangeErr(fd, fieldName, value)
+		}
+		n := protoreflect.EnumNumber(i)
+		if exists := fd.Enum().Values().ByNumber(n); exists == nil {
+			return protoreflect.Value{}, Errorf(ErrValueOutOfRange, "field(%s) is an enum and %d is not a valid value", fieldName, i)
+		}
+		return protoreflect.ValueOfEnum(n), nil
+	}
+	return protoreflect.Value{}, mismatchErr(fd, fieldName, value)
+}
+
+// uintValue is like intValue, but for unsigned integers.
+func uintValue(fd protoreflect.FieldDescriptor, fieldName protoreflect.Name, u uint64, value interface{}) (protoreflect.Value, error) {
+	switch fd.Kind() {
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		if u > math.MaxUint32 {
+			return protoreflect.Value{}, rangeErr(fd, fieldName, value)
+		}
+		return protoreflect.ValueOfUint32(uint32(u)), nil
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return protoreflect.ValueOfUint64(u), nil
+	}
+	if u > math.MaxInt64 {
+		if isInteger(fd.Kind()) {
+			return protoreflect.Value{}, rangeErr(fd, fieldName, value)
+		}
+		return protoreflect.Value{}, mismatchErr(fd, fieldName, value)
+	}
+	return intValue(fd, fieldName, int64(u), value)
+}
+
+// floatValue converts f into a value for a float or double field. value is the
+// original value and is used in error messages.
+func floatValue(fd protoreflect.FieldDescriptor, fieldName protoreflect.Name, f float64, value interface{}) (protoreflect.Value, error) {
+	switch fd.Kind() {
+	case protoreflect.FloatKind:
+		if !math.IsInf(f, 0) && !math.IsNaN(f) && math.Abs(f) > math.MaxFloat32 {
+			return protoreflect.Value{}, rangeErr(fd, fieldName, value)
+		}
+		return protoreflect.ValueOfFloat32(float32(f)), nil
+	case protoreflect.DoubleKind:
+		return protoreflect.ValueOfFloat64(f), nil
+	}
+	return protoreflect.Value{}, mismatchErr(fd, fieldName, value)
+}
+
+// isInteger returns true if k is one of the integer kinds or an enum.
+func isInteger(k protoreflect.Kind) bool {
+	switch k {
+	case protoreflect.BoolKind, protoreflect.FloatKind, protoreflect.DoubleKind, protoreflect.StringKind,
+		protoreflect.BytesKind, protoreflect.MessageKind, protoreflect.GroupKind:
+		return false
+	}
+	return true
 }
 
 // HumanDiff is a wrapper aound go-cmp using the protocmp.Transform. It outputs a string of what changes from a (older) to b (newer).
 // Options to pass can be found at: https://pkg.go.dev/google.golang.org/protobuf/testing/protocmp .
+// This package also has options that select fields with fqPaths, such as IgnorePaths() and FloatTolerance().
+// Unknown fields are compared as raw bytes, use ShowUnknownFields() to decode them or IgnoreUnknownFields() to skip them.
+// Use Diff() if you need the changes in a form a program can read.
 func HumanDiff(a, b proto.Message, options ...cmp.Option) string {
 	options = append(options, protocmp.Transform())
 	return cmp.Diff(a, b, options...)
 }
 
 // Equal returns an empty string if the two protos are equal. Otherwise it returns -want/+got.
-// This is the same as HumanDiff, but order is reversed.
+// This is the same as HumanDiff, but order is reversed. It takes the same options.
 func Equal(want, got proto.Message, options ...cmp.Option) string {
 	options = append(options, protocmp.Transform())
 	return cmp.Diff(want, got, options...)
 }
 
-/*
-// FieldChange details a field that changed in a proto. If the field is a non-message, .From* and .To*
-// will contain the value.
-type FieldChange struct {
-	// Name is the name of the field.
-	Name string
-	// Path is the path to that field in the proto you diffed. The field will be at:
-	// strings.Join(fc.Path, ".") + "." + fc.Name .
-	Path []string
-	Kind protoreflect.Kind
-	From, To interface{}
-}
-
-// FQPath will return the fully qualified path to the value.
-func (f FieldChange) FQPath() string {
-	return strings.Join(f.Path, ".") + "." + f.Name
-}
-
-func DiffField(msg proto.Message, fqPath string) (FieldChange, error) {
-
-}
-*/
-
 // this code is borrowed and modified faith code(github.com/fatih/camelcase)
 func split(src string, splitNum bool) (entries []string) {
 	// don't split invalid utf8
@@ -1,6 +1,9 @@
 package prototools
 
 import (
+	"errors"
+	"math"
+	"reflect"
 	"sort"
 	"testing"
 
@@ -118,7 +121,7 @@ func TestGetLastMessage(t *testing.T) {
 	// That option isn't used there, so here's that test.
 	toUpdate := &pb.Layer0{}
 
-	msg, err := getLastMessage(toUpdate, []string{"layer1", "supported", "vstring"}, true)
+	msg, err := getLastMessage(toUpdate, []string{"layer1", "supported", "vstring"}, true, nil)
 	if err != nil {
 		t.Fatalf("TestGetLastMessage: got err == %s, want err == nil", err)
 	}
@@ -695,6 +698,13 @@ func TestFieldValue(t *testing.T) {
 			continue
 		}
 
+		if got.IsList {
+			if got.List == nil || got.List.Len() != reflect.ValueOf(got.Value).Len() {
+				t.Errorf("TestFieldValue(%s): .List does not match .Value", test.desc)
+			}
+			got.List = nil
+		}
+
 		switch test.compareType {
 		case stdList, stdVal:
 			if diff := myPretty.Compare(test.want, got); diff != "" {
@@ -721,3 +731,121 @@ func TestFieldValue(t *testing.T) {
 
 	}
 }
+
+func TestUpdateProtoFieldKinds(t *testing.T) {
+	tests := []struct {
+		desc  string
+		field string
+		value interface{}
+		want  *pb.Kinds
+		code  ErrCode
+	}{
+		{desc: "int to int32", field: "vint32", value: 32, want: &pb.Kinds{Vint32: 32}},
+		{desc: "int64 to sint32", field: "vsint32", value: int64(-32), want: &pb.Kinds{Vsint32: -32}},
+		{desc: "int8 to sfixed32", field: "vsfixed32", value: int8(8), want: &pb.Kinds{Vsfixed32: 8}},
+		{desc: "uint64 to int64", field: "vint64", value: uint64(64), want: &pb.Kinds{Vint64: 64}},
+		{desc: "int to sint64", field: "vsint64", value: -64, want: &pb.Kinds{Vsint64: -64}},
+		{desc: "int64 to sfixed64", field: "vsfixed64", value: int64(64), want: &pb.Kinds{Vsfixed64: 64}},
+		{desc: "uint32 to uint32", field: "vuint32", value: uint32(32), want: &pb.Kinds{Vuint32: 32}},
+		{desc: "int to fixed32", field: "vfixed32", value: 32, want: &pb.Kinds{Vfixed32: 32}},
+		{desc: "uint64 to uint64", field: "vuint64", value: uint64(math.MaxUint64), want: &pb.Kinds{Vuint64: math.MaxUint64}},
+		{desc: "uint to fixed64", field: "vfixed64", value: uint(64), want: &pb.Kinds{Vfixed64: 64}},
+		{desc: "float32 to float", field: "vfloat", value: float32(1.5), want: &pb.Kinds{Vfloat: 1.5}},
+		{desc: "float64 to float", field: "vfloat", value: 1.5, want: &pb.Kinds{Vfloat: 1.5}},
+		{desc: "float64 to double", field: "vdouble", value: 1.5, want: &pb.Kinds{Vdouble: 1.5}},
+		{desc: "bytes", field: "vbytes", value: []byte("hello"), want: &pb.Kinds{Vbytes: []byte("hello")}},
+		{desc: "EnumNumber to enum", field: "venum", value: protoreflect.EnumNumber(2), want: &pb.Kinds{Venum: pb.EnumValues_EV_Not_Ok}},
+		{desc: "int to enum", field: "venum", value: 3, want: &pb.Kinds{Venum: pb.EnumValues_EV_Eh}},
+		{desc: "error: int64 overflows int32", field: "vint32", value: int64(math.MaxInt32 + 1), code: ErrValueOutOfRange},
+		{desc: "error: negative to uint32", field: "vuint32", value: -1, code: ErrValueOutOfRange},
+		{desc: "error: uint64 overflows int64", field: "vint64", value: uint64(math.MaxUint64), code: ErrValueOutOfRange},
+		{desc: "error: uint64 overflows uint32", field: "vfixed32", value: uint64(math.MaxUint32 + 1), code: ErrValueOutOfRange},
+		{desc: "error: float64 overflows float", field: "vfloat", value: math.MaxFloat64, code: ErrValueOutOfRange},
+		{desc: "error: enum value doesn't exist", field: "venum", value: 20, code: ErrValueOutOfRange},
+		{desc: "error: string to bytes", field: "vbytes", value: "hello", code: ErrTypeMismatch},
+		{desc: "error: float to int", field: "vint64", value: 1.5, code: ErrTypeMismatch},
+		{desc: "error: int to float", field: "vdouble", value: 1, code: ErrTypeMismatch},
+		{desc: "error: uint64 to string", field: "vstring", value: uint64(math.MaxUint64), code: ErrTypeMismatch},
+		{desc: "error: wrong enum type", field: "venum", value: pb.Layer0_EE_WHATEVER, code: ErrTypeMismatch},
+		{desc: "error: unsupported type", field: "vstring", value: struct{}{}, code: ErrTypeMismatch},
+	}
+
+	for _, test := range tests {
+		got := &pb.Kinds{}
+		err := UpdateProtoField(got, test.field, test.value)
+		switch {
+		case err == nil && test.code != ErrUnknown:
+			t.Errorf("TestUpdateProtoFieldKinds(%s): got err == nil, want err != nil", test.desc)
+			continue
+		case err != nil && test.code == ErrUnknown:
+			t.Errorf("TestUpdateProtoFieldKinds(%s): got err == %s, want err == nil", test.desc, err)
+			continue
+		case err != nil:
+			var e Error
+			if !errors.As(err, &e) || e.Code != test.code {
+				t.Errorf("TestUpdateProtoFieldKinds(%s): got err == %s, want code %s", test.desc, err, test.code)
+			}
+			continue
+		}
+		if diff := cmp.Diff(test.want, got, protocmp.Transform()); diff != "" {
+			t.Errorf("TestUpdateProtoFieldKinds(%s): -want/+got:\n%s", test.desc, diff)
+		}
+	}
+}
+
+func TestFieldValueListKinds(t *testing.T) {
+	msg := &pb.Kinds{
+		LBool:     []bool{true},
+		LInt32:    []int32{-1},
+		LSint32:   []int32{-2},
+		LSfixed32: []int32{-3},
+		LInt64:    []int64{-4},
+		LSint64:   []int64{-5},
+		LSfixed64: []int64{-6},
+		LUint32:   []uint32{7},
+		LFixed32:  []uint32{8},
+		LUint64:   []uint64{9},
+		LFixed64:  []uint64{10},
+		LFloat:    []float32{1.5},
+		LDouble:   []float64{2.5},
+		LString:   []string{"hello"},
+		LBytes:    [][]byte{[]byte("bytes")},
+		LEnum:     []pb.EnumValues{pb.EnumValues_EV_Ok},
+	}
+
+	tests := []struct {
+		field string
+		want  interface{}
+	}{
+		{"l_bool", []bool{true}},
+		{"l_int32", []int32{-1}},
+		{"l_sint32", []int32{-2}},
+		{"l_sfixed32", []int32{-3}},
+		{"l_int64", []int64{-4}},
+		{"l_sint64", []int64{-5}},
+		{"l_sfixed64", []int64{-6}},
+		{"l_uint32", []uint32{7}},
+		{"l_fixed32", []uint32{8}},
+		{"l_uint64", []uint64{9}},
+		{"l_fixed64", []uint64{10}},
+		{"l_float", []float32{1.5}},
+		{"l_double", []float64{2.5}},
+		{"l_string", []string{"hello"}},
+		{"l_bytes", [][]byte{[]byte("bytes")}},
+		{"l_enum", []protoreflect.EnumNumber{1}},
+	}
+
+	for _, test := range tests {
+		fv, err := GetField(msg, test.field)
+		if err != nil {
+			t.Errorf("TestFieldValueListKinds(%s): got err == %s, want err == nil", test.field, err)
+			continue
+		}
+		if !fv.IsList || fv.List == nil || fv.List.Len() != 1 {
+			t.Errorf("TestFieldValueListKinds(%s): got IsList == %v, List == %v, want a list of 1 entry", test.field, fv.IsList, fv.List)
+		}
+		if diff := cmp.Diff(test.want, fv.Value); diff != "" {
+			t.Errorf("TestFieldValueListKinds(%s): -want/+got:\n%s", test.field, diff)
+		}
+	}
+}
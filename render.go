@@ -0,0 +1,245 @@
+package prototools
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// NotSet is how a renderer shows the value of a field that is not set.
+const NotSet = "(not set)"
+
+type renderOpts struct {
+	jsonLabels bool
+	readable   []ReadableOption
+	diff       []DiffOption
+}
+
+// RenderOption is an option for RenderDiff(), DiffText(), DiffMarkdown() and DiffHTML().
+type RenderOption func(o *renderOpts)
+
+// RenderJSONLabels labels fields with ReadableJSON() of the JSON field name instead of ReadableProto() of
+// the proto field name.
+func RenderJSONLabels() RenderOption {
+	return func(o *renderOpts) {
+		o.jsonLabels = true
+	}
+}
+
+// RenderReadableOptions passes options to ReadableProto() when making labels. These are ignored with
+// RenderJSONLabels().
+func RenderReadableOptions(options ...ReadableOption) RenderOption {
+	return func(o *renderOpts) {
+		o.readable = append(o.readable, options...)
+	}
+}
+
+// RenderDiffOptions passes options to Diff() when comparing the messages.
+func RenderDiffOptions(options ...DiffOption) RenderOption {
+	return func(o *renderOpts) {
+		o.diff = append(o.diff, options...)
+	}
+}
+
+// RenderedChange is a FieldChange formatted for people to read.
+type RenderedChange struct {
+	// Label is the readable name of the field, with parent fields separated by " > ", aka "Orders[1] > Id".
+	Label string
+	// FQPath is the path to the field, as in FieldChange.FQPath().
+	FQPath string
+	// Type is the type of change.
+	Type ChangeType
+	// From and To are the old and new values, formatted like FieldAsStr(pretty=true). A value
+	// that is not set is NotSet.
+	From, To string
+}
+
+/*
+RenderDiff compares a (older) to b (newer) with Diff() and formats each change for people to read.
+Labels come from ReadableProto(), or ReadableJSON() with RenderJSONLabels(). Values are formatted like
+FieldAsStr() with pretty set, so enums are prettified, bools are titled, floats have 2 decimal places, int64
+fields ending in "_time" are times and messages are JSON. Kinds FieldAsStr() does not support are formatted with fmt.
+*/
+func RenderDiff(a, b proto.Message, options ...RenderOption) ([]RenderedChange, error) {
+	opts := renderOpts{}
+	for _, o := range options {
+		o(&opts)
+	}
+
+	changes, err := Diff(a, b, opts.diff...)
+	if err != nil {
+		return nil, err
+	}
+
+	var md protoreflect.MessageDescriptor
+	if a != nil {
+		md = a.ProtoReflect().Descriptor()
+	} else if b != nil {
+		md = b.ProtoReflect().Descriptor()
+	}
+
+	rendered := make([]RenderedChange, 0, len(changes))
+	for _, c := range changes {
+		rc := RenderedChange{
+			Label:  renderLabel(md, c.FQPath(), opts),
+			FQPath: c.FQPath(),
+			Type:   c.Type,
+		}
+		if rc.From, err = renderValue(c, c.From); err != nil {
+			return nil, err
+		}
+		if rc.To, err = renderValue(c, c.To); err != nil {
+			return nil, err
+		}
+		rendered = append(rendered, rc)
+	}
+	return rendered, nil
+}
+
+/*
+DiffText renders the changes between a and b as one "Label: old → new" line per change. An empty string
+means there are no changes. See RenderDiff() for how labels and values are formatted.
+*/
+func DiffText(a, b proto.Message, options ...RenderOption) (string, error) {
+	changes, err := RenderDiff(a, b, options...)
+	if err != nil {
+		return "", err
+	}
+
+	buff := strings.Builder{}
+	for _, c := range changes {
+		fmt.Fprintf(&buff, "%s: %s → %s\n", c.Label, c.From, c.To)
+	}
+	return buff.String(), nil
+}
+
+/*
+DiffMarkdown renders the changes between a and b as a Markdown table with the columns Field, Change, Old
+and New. An empty string means there are no changes. See RenderDiff() for how labels and values are formatted.
+Cells are HTML escaped, as Markdown renderers pass raw HTML through, so values from a message can't inject
+markup into the page.
+*/
+func DiffMarkdown(a, b proto.Message, options ...RenderOption) (string, error) {
+	changes, err := RenderDiff(a, b, options...)
+	if err != nil {
+		return "", err
+	}
+	if len(changes) == 0 {
+		return "", nil
+	}
+
+	buff := strings.Builder{}
+	buff.WriteString("| Field | Change | Old | New |\n")
+	buff.WriteString("| --- | --- | --- | --- |\n")
+	for _, c := range changes {
+		fmt.Fprintf(
+			&buff,
+			"| %s | %s | %s | %s |\n",
+			markdownCell(c.Label), c.Type, markdownCell(c.From), markdownCell(c.To),
+		)
+	}
+	return buff.String(), nil
+}
+
+var diffHTML = template.Must(template.New("diff").Parse(
+	`<table class="proto-diff">
+<thead><tr><th>Field</th><th>Change</th><th>Old</th><th>New</th></tr></thead>
+<tbody>
+{{- range .}}
+<tr class="{{.Type}}"><td>{{.Label}}</td><td>{{.Type}}</td><td>{{.From}}</td><td>{{.To}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+`))
+
+/*
+DiffHTML renders the changes between a and b as an HTML <table> fragment with the class "proto-diff" and
+the columns Field, Change, Old and New. Each row has the class of its ChangeType, aka "Modified", for styling.
+All text is escaped. An empty string means there are no changes. See RenderDiff() for how labels and values
+are formatted.
+*/
+func DiffHTML(a, b proto.Message, options ...RenderOption) (string, error) {
+	changes, err := RenderDiff(a, b, options...)
+	if err != nil {
+		return "", err
+	}
+	if len(changes) == 0 {
+		return "", nil
+	}
+
+	buff := bytes.Buffer{}
+	if err := diffHTML.Execute(&buff, changes); err != nil {
+		return "", err
+	}
+	return buff.String(), nil
+}
+
+// renderLabel returns the readable label for fqPath in the message described by md.
+func renderLabel(md protoreflect.MessageDescriptor, fqPath string, opts renderOpts) string {
+	fields := FQPathSplit(fqPath)
+	labels := make([]string, 0, len(fields))
+	for _, field := range fields {
+		pp, _ := parsePart(field)
+		fd := partField(md, pp.name)
+		if fd == nil {
+			// This is not a path in md, so there is nothing to make it readable with.
+			labels = append(labels, strings.Join(fields[len(labels):], "."))
+			break
+		}
+
+		// An extension keeps its full name, aka "(my.pkg.ext)", so it can't be taken for a field.
+		var label string
+		switch {
+		case fd.IsExtension():
+			label = pp.name
+		case opts.jsonLabels:
+			label = ReadableJSON(fd.JSONName())
+		default:
+			label = ReadableProto(pp.name, opts.readable...)
+		}
+		if pp.hasSelector {
+			label += field[len(pp.name):]
+		}
+		labels = append(labels, label)
+
+		if vd := valueDesc(fd); vd.Kind() == protoreflect.MessageKind || vd.Kind() == protoreflect.GroupKind {
+			md = vd.Message()
+		}
+	}
+	return strings.Join(labels, " > ")
+}
+
+// renderValue formats v, which is c.From or c.To, like FieldAsStr(pretty=true).
+func renderValue(c FieldChange, v interface{}) (string, error) {
+	if v == nil {
+		return NotSet, nil
+	}
+
+	fv := FieldValue{Value: v, Kind: c.Kind, FieldDesc: c.FieldDesc}
+	if c.Kind == protoreflect.EnumKind {
+		fv.EnumDesc = valueDesc(c.FieldDesc).Enum().Values().ByNumber(v.(protoreflect.EnumNumber))
+	}
+
+	s, err := valueAsStr(fv, string(c.FieldDesc.Name()), true)
+	if err != nil {
+		if c.Kind == protoreflect.MessageKind || c.Kind == protoreflect.GroupKind {
+			return "", err
+		}
+		return fmt.Sprintf("%v", v), nil
+	}
+	return s, nil
+}
+
+var markdownEscaper = strings.NewReplacer(
+	"&", "&amp;", "<", "&lt;", ">", "&gt;",
+	`\`, `\\`, "|", `\|`, "\r\n", "<br>", "\n", "<br>",
+)
+
+// markdownCell escapes s so that it stays in a single Markdown table cell and any HTML in it is shown as text.
+func markdownCell(s string) string {
+	return markdownEscaper.Replace(s)
+}
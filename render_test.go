@@ -0,0 +1,219 @@
+package prototools
+
+import (
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/johnsiilver/prototools/sample"
+)
+
+func TestRenderDiff(t *testing.T) {
+	tests := []struct {
+		desc    string
+		a, b    proto.Message
+		options []RenderOption
+		want    []RenderedChange
+		err     bool
+	}{
+		{
+			desc: "equal",
+			a:    newCustomer(),
+			b:    newCustomer(),
+			want: []RenderedChange{},
+		},
+		{
+			desc: "values are formatted like FieldAsStr",
+			a: &pb.Layer0{
+				Vint32: 1,
+				Layer1: &pb.Layer1{Supported: &pb.Supported{Vbool: true, Vfloat: 1.5, VTime: 1}},
+			},
+			b: &pb.Layer0{
+				Ee:     pb.Layer0_EE_WHATEVER,
+				Layer1: &pb.Layer1{Supported: &pb.Supported{Ev: pb.EnumValues_EV_Not_Ok, Vfloat: 2.25, VTime: 86400}},
+			},
+			want: []RenderedChange{
+				{Label: "Layer1 > Supported > Ev", FQPath: "layer1.supported.ev", Type: FieldAdded, From: NotSet, To: "Not Ok"},
+				{Label: "Layer1 > Supported > Vbool", FQPath: "layer1.supported.vbool", Type: FieldRemoved, From: "True", To: NotSet},
+				{Label: "Layer1 > Supported > V Time", FQPath: "layer1.supported.v_time", Type: FieldModified, From: "1970-01-01 00:00:01 +0000 UTC", To: "1970-01-02 00:00:00 +0000 UTC"},
+				{Label: "Layer1 > Supported > Vfloat", FQPath: "layer1.supported.vfloat", Type: FieldModified, From: "1.50", To: "2.25"},
+				{Label: "Vint32", FQPath: "vint32", Type: FieldRemoved, From: "1", To: NotSet},
+				{Label: "Ee", FQPath: "ee", Type: FieldAdded, From: NotSet, To: "Whatever"},
+			},
+		},
+		{
+			desc: "selectors and messages",
+			a: &pb.Customer{
+				Orders: []*pb.Order{{Id: "1"}},
+				Labels: map[string]string{"env": "dev"},
+			},
+			b: &pb.Customer{
+				Orders: []*pb.Order{{Id: "2"}, {Id: "3"}},
+				Labels: map[string]string{"env": "prod"},
+			},
+			want: []RenderedChange{
+				{Label: "Orders[0] > Id", FQPath: "orders[0].id", Type: FieldModified, From: "1", To: "2"},
+				{Label: "Orders[1]", FQPath: "orders[1]", Type: FieldAdded, From: NotSet, To: `{"id":"3"}`},
+				{Label: `Labels["env"]`, FQPath: `labels["env"]`, Type: FieldModified, From: "dev", To: "prod"},
+			},
+		},
+		{
+			desc:    "JSON labels",
+			a:       &pb.Customer{},
+			b:       &pb.Customer{ById: map[int64]*pb.Order{5: {Id: "a"}}},
+			options: []RenderOption{RenderJSONLabels()},
+			want: []RenderedChange{
+				{Label: "By Id[5]", FQPath: "by_id[5]", Type: FieldAdded, From: NotSet, To: `{"id":"a"}`},
+			},
+		},
+		{
+			desc:    "readable options",
+			a:       &pb.Layer0{},
+			b:       &pb.Layer0{Layer1: &pb.Layer1{Supported: &pb.Supported{VTime: 1}}},
+			options: []RenderOption{RenderReadableOptions(RemovePrefix())},
+			want: []RenderedChange{
+				{Label: "Layer1", FQPath: "layer1", Type: FieldAdded, From: NotSet, To: `{"supported":{"vTime":"1"}}`},
+			},
+		},
+		{
+			desc:    "diff options",
+			a:       &pb.Customer{Orders: []*pb.Order{{Id: "1"}, {Id: "2"}}},
+			b:       &pb.Customer{Orders: []*pb.Order{{Id: "2"}}},
+			options: []RenderOption{RenderDiffOptions(DiffListByKey("orders", "id"))},
+			want: []RenderedChange{
+				{Label: "Orders[0]", FQPath: "orders[0]", Type: FieldRemoved, From: `{"id":"1"}`, To: NotSet},
+			},
+		},
+		{
+			desc: "error: different types",
+			a:    &pb.Layer0{},
+			b:    &pb.Layer1{},
+			err:  true,
+		},
+	}
+
+	for _, test := range tests {
+		got, err := RenderDiff(test.a, test.b, test.options...)
+		switch {
+		case err == nil && test.err:
+			t.Errorf("TestRenderDiff(%s): got err == nil, want err != nil", test.desc)
+			continue
+		case err != nil && !test.err:
+			t.Errorf("TestRenderDiff(%s): got err == %s, want err == nil", test.desc, err)
+			continue
+		case err != nil:
+			continue
+		}
+
+		if diff := pretty.Compare(test.want, got); diff != "" {
+			t.Errorf("TestRenderDiff(%s): -want/+got:\n%s", test.desc, diff)
+		}
+	}
+}
+
+func TestDiffRenderers(t *testing.T) {
+	a := &pb.Customer{Name: "Bob", Labels: map[string]string{"note": "a|b"}}
+	b := &pb.Customer{Name: "<Alice>", Labels: map[string]string{"note": "c"}}
+
+	tests := []struct {
+		desc   string
+		render func(a, b proto.Message, options ...RenderOption) (string, error)
+		a, b   proto.Message
+		want   string
+	}{
+		{
+			desc:   "text",
+			render: DiffText,
+			a:      a,
+			b:      b,
+			want:   "Name: Bob → <Alice>\nLabels[\"note\"]: a|b → c\n",
+		},
+		{
+			desc:   "markdown",
+			render: DiffMarkdown,
+			a:      a,
+			b:      b,
+			want: "| Field | Change | Old | New |\n" +
+				"| --- | --- | --- | --- |\n" +
+				"| Name | Modified | Bob | &lt;Alice&gt; |\n" +
+				"| Labels[\"note\"] | Modified | a\\|b | c |\n",
+		},
+		{
+			desc:   "markdown hostile values",
+			render: DiffMarkdown,
+			a:      &pb.Customer{Name: "&lt;b&gt;"},
+			b:      &pb.Customer{Name: "<img src=x onerror=alert(1)>\n</td>"},
+			want: "| Field | Change | Old | New |\n" +
+				"| --- | --- | --- | --- |\n" +
+				"| Name | Modified | &amp;lt;b&amp;gt; | &lt;img src=x onerror=alert(1)&gt;<br>&lt;/td&gt; |\n",
+		},
+		{
+			desc:   "html",
+			render: DiffHTML,
+			a:      a,
+			b:      b,
+			want: `<table class="proto-diff">
+<thead><tr><th>Field</th><th>Change</th><th>Old</th><th>New</th></tr></thead>
+<tbody>
+<tr class="Modified"><td>Name</td><td>Modified</td><td>Bob</td><td>&lt;Alice&gt;</td></tr>
+<tr class="Modified"><td>Labels[&#34;note&#34;]</td><td>Modified</td><td>a|b</td><td>c</td></tr>
+</tbody>
+</table>
+`,
+		},
+		{
+			desc:   "text no changes",
+			render: DiffText,
+			a:      a,
+			b:      a,
+		},
+		{
+			desc:   "markdown no changes",
+			render: DiffMarkdown,
+			a:      a,
+			b:      a,
+		},
+		{
+			desc:   "html no changes",
+			render: DiffHTML,
+			a:      a,
+			b:      a,
+		},
+	}
+
+	for _, test := range tests {
+		got, err := test.render(test.a, test.b)
+		if err != nil {
+			t.Errorf("TestDiffRenderers(%s): got err == %s, want err == nil", test.desc, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("TestDiffRenderers(%s): got:\n%s\nwant:\n%s", test.desc, got, test.want)
+		}
+	}
+}
+
+func TestRenderLabel(t *testing.T) {
+	md := (&pb.Legacy{}).ProtoReflect().Descriptor()
+
+	tests := []struct {
+		desc   string
+		fqPath string
+		opts   renderOpts
+		want   string
+	}{
+		{desc: "field", fqPath: "name", want: "Name"},
+		{desc: "extension", fqPath: "(r3.nickname)", want: "(r3.nickname)"},
+		{desc: "field in an extension", fqPath: "(r3.details).child.(r3.nickname)", want: "(r3.details) > Child > (r3.nickname)"},
+		{desc: "extension with a selector", fqPath: "(r3.scores)[1]", want: "(r3.scores)[1]"},
+		{desc: "JSON labels", fqPath: "(r3.details).count", opts: renderOpts{jsonLabels: true}, want: "(r3.details) > Count"},
+		{desc: "unknown field", fqPath: "name.nope", want: "Name > nope"},
+	}
+
+	for _, test := range tests {
+		if got := renderLabel(md, test.fqPath, test.opts); got != test.want {
+			t.Errorf("TestRenderLabel(%s): got %q, want %q", test.desc, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,449 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.26.0
+// 	protoc        v3.15.2
+// source: kinds.proto
+
+package sample
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Kinds struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Vbool     bool         `protobuf:"varint,1,opt,name=vbool,proto3" json:"vbool,omitempty"`
+	Vint32    int32        `protobuf:"varint,2,opt,name=vint32,proto3" json:"vint32,omitempty"`
+	Vsint32   int32        `protobuf:"zigzag32,3,opt,name=vsint32,proto3" json:"vsint32,omitempty"`
+	Vsfixed32 int32        `protobuf:"fixed32,4,opt,name=vsfixed32,proto3" json:"vsfixed32,omitempty"`
+	Vint64    int64        `protobuf:"varint,5,opt,name=vint64,proto3" json:"vint64,omitempty"`
+	Vsint64   int64        `protobuf:"zigzag64,6,opt,name=vsint64,proto3" json:"vsint64,omitempty"`
+	Vsfixed64 int64        `protobuf:"fixed64,7,opt,name=vsfixed64,proto3" json:"vsfixed64,omitempty"`
+	Vuint32   uint32       `protobuf:"varint,8,opt,name=vuint32,proto3" json:"vuint32,omitempty"`
+	Vfixed32  uint32       `protobuf:"fixed32,9,opt,name=vfixed32,proto3" json:"vfixed32,omitempty"`
+	Vuint64   uint64       `protobuf:"varint,10,opt,name=vuint64,proto3" json:"vuint64,omitempty"`
+	Vfixed64  uint64       `protobuf:"fixed64,11,opt,name=vfixed64,proto3" json:"vfixed64,omitempty"`
+	Vfloat    float32      `protobuf:"fixed32,12,opt,name=vfloat,proto3" json:"vfloat,omitempty"`
+	Vdouble   float64      `protobuf:"fixed64,13,opt,name=vdouble,proto3" json:"vdouble,omitempty"`
+	Vstring   string       `protobuf:"bytes,14,opt,name=vstring,proto3" json:"vstring,omitempty"`
+	Vbytes    []byte       `protobuf:"bytes,15,opt,name=vbytes,proto3" json:"vbytes,omitempty"`
+	Venum     EnumValues   `protobuf:"varint,16,opt,name=venum,proto3,enum=r3.EnumValues" json:"venum,omitempty"`
+	LBool     []bool       `protobuf:"varint,17,rep,packed,name=l_bool,json=lBool,proto3" json:"l_bool,omitempty"`
+	LInt32    []int32      `protobuf:"varint,18,rep,packed,name=l_int32,json=lInt32,proto3" json:"l_int32,omitempty"`
+	LSint32   []int32      `protobuf:"zigzag32,19,rep,packed,name=l_sint32,json=lSint32,proto3" json:"l_sint32,omitempty"`
+	LSfixed32 []int32      `protobuf:"fixed32,20,rep,packed,name=l_sfixed32,json=lSfixed32,proto3" json:"l_sfixed32,omitempty"`
+	LInt64    []int64      `protobuf:"varint,21,rep,packed,name=l_int64,json=lInt64,proto3" json:"l_int64,omitempty"`
+	LSint64   []int64      `protobuf:"zigzag64,22,rep,packed,name=l_sint64,json=lSint64,proto3" json:"l_sint64,omitempty"`
+	LSfixed64 []int64      `protobuf:"fixed64,23,rep,packed,name=l_sfixed64,json=lSfixed64,proto3" json:"l_sfixed64,omitempty"`
+	LUint32   []uint32     `protobuf:"varint,24,rep,packed,name=l_uint32,json=lUint32,proto3" json:"l_uint32,omitempty"`
+	LFixed32  []uint32     `protobuf:"fixed32,25,rep,packed,name=l_fixed32,json=lFixed32,proto3" json:"l_fixed32,omitempty"`
+	LUint64   []uint64     `protobuf:"varint,26,rep,packed,name=l_uint64,json=lUint64,proto3" json:"l_uint64,omitempty"`
+	LFixed64  []uint64     `protobuf:"fixed64,27,rep,packed,name=l_fixed64,json=lFixed64,proto3" json:"l_fixed64,omitempty"`
+	LFloat    []float32    `protobuf:"fixed32,28,rep,packed,name=l_float,json=lFloat,proto3" json:"l_float,omitempty"`
+	LDouble   []float64    `protobuf:"fixed64,29,rep,packed,name=l_double,json=lDouble,proto3" json:"l_double,omitempty"`
+	LString   []string     `protobuf:"bytes,30,rep,name=l_string,json=lString,proto3" json:"l_string,omitempty"`
+	LBytes    [][]byte     `protobuf:"bytes,31,rep,name=l_bytes,json=lBytes,proto3" json:"l_bytes,omitempty"`
+	LEnum     []EnumValues `protobuf:"varint,32,rep,packed,name=l_enum,json=lEnum,proto3,enum=r3.EnumValues" json:"l_enum,omitempty"`
+}
+
+func (x *Kinds) Reset() {
+	*x = Kinds{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_kinds_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Kinds) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Kinds) ProtoMessage() {}
+
+func (x *Kinds) ProtoReflect() protoreflect.Message {
+	mi := &file_kinds_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Kinds.ProtoReflect.Descriptor instead.
+func (*Kinds) Descriptor() ([]byte, []int) {
+	return file_kinds_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Kinds) GetVbool() bool {
+	if x != nil {
+		return x.Vbool
+	}
+	return false
+}
+
+func (x *Kinds) GetVint32() int32 {
+	if x != nil {
+		return x.Vint32
+	}
+	return 0
+}
+
+func (x *Kinds) GetVsint32() int32 {
+	if x != nil {
+		return x.Vsint32
+	}
+	return 0
+}
+
+func (x *Kinds) GetVsfixed32() int32 {
+	if x != nil {
+		return x.Vsfixed32
+	}
+	return 0
+}
+
+func (x *Kinds) GetVint64() int64 {
+	if x != nil {
+		return x.Vint64
+	}
+	return 0
+}
+
+func (x *Kinds) GetVsint64() int64 {
+	if x != nil {
+		return x.Vsint64
+	}
+	return 0
+}
+
+func (x *Kinds) GetVsfixed64() int64 {
+	if x != nil {
+		return x.Vsfixed64
+	}
+	return 0
+}
+
+func (x *Kinds) GetVuint32() uint32 {
+	if x != nil {
+		return x.Vuint32
+	}
+	return 0
+}
+
+func (x *Kinds) GetVfixed32() uint32 {
+	if x != nil {
+		return x.Vfixed32
+	}
+	return 0
+}
+
+func (x *Kinds) GetVuint64() uint64 {
+	if x != nil {
+		return x.Vuint64
+	}
+	return 0
+}
+
+func (x *Kinds) GetVfixed64() uint64 {
+	if x != nil {
+		return x.Vfixed64
+	}
+	return 0
+}
+
+func (x *Kinds) GetVfloat() float32 {
+	if x != nil {
+		return x.Vfloat
+	}
+	return 0
+}
+
+func (x *Kinds) GetVdouble() float64 {
+	if x != nil {
+		return x.Vdouble
+	}
+	return 0
+}
+
+func (x *Kinds) GetVstring() string {
+	if x != nil {
+		return x.Vstring
+	}
+	return ""
+}
+
+func (x *Kinds) GetVbytes() []byte {
+	if x != nil {
+		return x.Vbytes
+	}
+	return nil
+}
+
+func (x *Kinds) GetVenum() EnumValues {
+	if x != nil {
+		return x.Venum
+	}
+	return EnumValues_EV_Unknown
+}
+
+func (x *Kinds) GetLBool() []bool {
+	if x != nil {
+		return x.LBool
+	}
+	return nil
+}
+
+func (x *Kinds) GetLInt32() []int32 {
+	if x != nil {
+		return x.LInt32
+	}
+	return nil
+}
+
+func (x *Kinds) GetLSint32() []int32 {
+	if x != nil {
+		return x.LSint32
+	}
+	return nil
+}
+
+func (x *Kinds) GetLSfixed32() []int32 {
+	if x != nil {
+		return x.LSfixed32
+	}
+	return nil
+}
+
+func (x *Kinds) GetLInt64() []int64 {
+	if x != nil {
+		return x.LInt64
+	}
+	return nil
+}
+
+func (x *Kinds) GetLSint64() []int64 {
+	if x != nil {
+		return x.LSint64
+	}
+	return nil
+}
+
+func (x *Kinds) GetLSfixed64() []int64 {
+	if x != nil {
+		return x.LSfixed64
+	}
+	return nil
+}
+
+func (x *Kinds) GetLUint32() []uint32 {
+	if x != nil {
+		return x.LUint32
+	}
+	return nil
+}
+
+func (x *Kinds) GetLFixed32() []uint32 {
+	if x != nil {
+		return x.LFixed32
+	}
+	return nil
+}
+
+func (x *Kinds) GetLUint64() []uint64 {
+	if x != nil {
+		return x.LUint64
+	}
+	return nil
+}
+
+func (x *Kinds) GetLFixed64() []uint64 {
+	if x != nil {
+		return x.LFixed64
+	}
+	return nil
+}
+
+func (x *Kinds) GetLFloat() []float32 {
+	if x != nil {
+		return x.LFloat
+	}
+	return nil
+}
+
+func (x *Kinds) GetLDouble() []float64 {
+	if x != nil {
+		return x.LDouble
+	}
+	return nil
+}
+
+func (x *Kinds) GetLString() []string {
+	if x != nil {
+		return x.LString
+	}
+	return nil
+}
+
+func (x *Kinds) GetLBytes() [][]byte {
+	if x != nil {
+		return x.LBytes
+	}
+	return nil
+}
+
+func (x *Kinds) GetLEnum() []EnumValues {
+	if x != nil {
+		return x.LEnum
+	}
+	return nil
+}
+
+var File_kinds_proto protoreflect.FileDescriptor
+
+var file_kinds_proto_rawDesc = []byte{
+	0x0a, 0x0b, 0x6b, 0x69, 0x6e, 0x64, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x02, 0x72,
+	0x33, 0x1a, 0x0c, 0x73, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22,
+	0xef, 0x06, 0x0a, 0x05, 0x4b, 0x69, 0x6e, 0x64, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x62, 0x6f,
+	0x6f, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x76, 0x62, 0x6f, 0x6f, 0x6c, 0x12,
+	0x16, 0x0a, 0x06, 0x76, 0x69, 0x6e, 0x74, 0x33, 0x32, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x06, 0x76, 0x69, 0x6e, 0x74, 0x33, 0x32, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x73, 0x69, 0x6e, 0x74,
+	0x33, 0x32, 0x18, 0x03, 0x20, 0x01, 0x28, 0x11, 0x52, 0x07, 0x76, 0x73, 0x69, 0x6e, 0x74, 0x33,
+	0x32, 0x12, 0x1c, 0x0a, 0x09, 0x76, 0x73, 0x66, 0x69, 0x78, 0x65, 0x64, 0x33, 0x32, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x0f, 0x52, 0x09, 0x76, 0x73, 0x66, 0x69, 0x78, 0x65, 0x64, 0x33, 0x32, 0x12,
+	0x16, 0x0a, 0x06, 0x76, 0x69, 0x6e, 0x74, 0x36, 0x34, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x06, 0x76, 0x69, 0x6e, 0x74, 0x36, 0x34, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x73, 0x69, 0x6e, 0x74,
+	0x36, 0x34, 0x18, 0x06, 0x20, 0x01, 0x28, 0x12, 0x52, 0x07, 0x76, 0x73, 0x69, 0x6e, 0x74, 0x36,
+	0x34, 0x12, 0x1c, 0x0a, 0x09, 0x76, 0x73, 0x66, 0x69, 0x78, 0x65, 0x64, 0x36, 0x34, 0x18, 0x07,
+	0x20, 0x01, 0x28, 0x10, 0x52, 0x09, 0x76, 0x73, 0x66, 0x69, 0x78, 0x65, 0x64, 0x36, 0x34, 0x12,
+	0x18, 0x0a, 0x07, 0x76, 0x75, 0x69, 0x6e, 0x74, 0x33, 0x32, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x07, 0x76, 0x75, 0x69, 0x6e, 0x74, 0x33, 0x32, 0x12, 0x1a, 0x0a, 0x08, 0x76, 0x66, 0x69,
+	0x78, 0x65, 0x64, 0x33, 0x32, 0x18, 0x09, 0x20, 0x01, 0x28, 0x07, 0x52, 0x08, 0x76, 0x66, 0x69,
+	0x78, 0x65, 0x64, 0x33, 0x32, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x75, 0x69, 0x6e, 0x74, 0x36, 0x34,
+	0x18, 0x0a, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x76, 0x75, 0x69, 0x6e, 0x74, 0x36, 0x34, 0x12,
+	0x1a, 0x0a, 0x08, 0x76, 0x66, 0x69, 0x78, 0x65, 0x64, 0x36, 0x34, 0x18, 0x0b, 0x20, 0x01, 0x28,
+	0x06, 0x52, 0x08, 0x76, 0x66, 0x69, 0x78, 0x65, 0x64, 0x36, 0x34, 0x12, 0x16, 0x0a, 0x06, 0x76,
+	0x66, 0x6c, 0x6f, 0x61, 0x74, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x02, 0x52, 0x06, 0x76, 0x66, 0x6c,
+	0x6f, 0x61, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x64, 0x6f, 0x75, 0x62, 0x6c, 0x65, 0x18, 0x0d,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x07, 0x76, 0x64, 0x6f, 0x75, 0x62, 0x6c, 0x65, 0x12, 0x18, 0x0a,
+	0x07, 0x76, 0x73, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
+	0x76, 0x73, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x12, 0x16, 0x0a, 0x06, 0x76, 0x62, 0x79, 0x74, 0x65,
+	0x73, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x76, 0x62, 0x79, 0x74, 0x65, 0x73, 0x12,
+	0x24, 0x0a, 0x05, 0x76, 0x65, 0x6e, 0x75, 0x6d, 0x18, 0x10, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0e,
+	0x2e, 0x72, 0x33, 0x2e, 0x45, 0x6e, 0x75, 0x6d, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x52, 0x05,
+	0x76, 0x65, 0x6e, 0x75, 0x6d, 0x12, 0x15, 0x0a, 0x06, 0x6c, 0x5f, 0x62, 0x6f, 0x6f, 0x6c, 0x18,
+	0x11, 0x20, 0x03, 0x28, 0x08, 0x52, 0x05, 0x6c, 0x42, 0x6f, 0x6f, 0x6c, 0x12, 0x17, 0x0a, 0x07,
+	0x6c, 0x5f, 0x69, 0x6e, 0x74, 0x33, 0x32, 0x18, 0x12, 0x20, 0x03, 0x28, 0x05, 0x52, 0x06, 0x6c,
+	0x49, 0x6e, 0x74, 0x33, 0x32, 0x12, 0x19, 0x0a, 0x08, 0x6c, 0x5f, 0x73, 0x69, 0x6e, 0x74, 0x33,
+	0x32, 0x18, 0x13, 0x20, 0x03, 0x28, 0x11, 0x52, 0x07, 0x6c, 0x53, 0x69, 0x6e, 0x74, 0x33, 0x32,
+	0x12, 0x1d, 0x0a, 0x0a, 0x6c, 0x5f, 0x73, 0x66, 0x69, 0x78, 0x65, 0x64, 0x33, 0x32, 0x18, 0x14,
+	0x20, 0x03, 0x28, 0x0f, 0x52, 0x09, 0x6c, 0x53, 0x66, 0x69, 0x78, 0x65, 0x64, 0x33, 0x32, 0x12,
+	0x17, 0x0a, 0x07, 0x6c, 0x5f, 0x69, 0x6e, 0x74, 0x36, 0x34, 0x18, 0x15, 0x20, 0x03, 0x28, 0x03,
+	0x52, 0x06, 0x6c, 0x49, 0x6e, 0x74, 0x36, 0x34, 0x12, 0x19, 0x0a, 0x08, 0x6c, 0x5f, 0x73, 0x69,
+	0x6e, 0x74, 0x36, 0x34, 0x18, 0x16, 0x20, 0x03, 0x28, 0x12, 0x52, 0x07, 0x6c, 0x53, 0x69, 0x6e,
+	0x74, 0x36, 0x34, 0x12, 0x1d, 0x0a, 0x0a, 0x6c, 0x5f, 0x73, 0x66, 0x69, 0x78, 0x65, 0x64, 0x36,
+	0x34, 0x18, 0x17, 0x20, 0x03, 0x28, 0x10, 0x52, 0x09, 0x6c, 0x53, 0x66, 0x69, 0x78, 0x65, 0x64,
+	0x36, 0x34, 0x12, 0x19, 0x0a, 0x08, 0x6c, 0x5f, 0x75, 0x69, 0x6e, 0x74, 0x33, 0x32, 0x18, 0x18,
+	0x20, 0x03, 0x28, 0x0d, 0x52, 0x07, 0x6c, 0x55, 0x69, 0x6e, 0x74, 0x33, 0x32, 0x12, 0x1b, 0x0a,
+	0x09, 0x6c, 0x5f, 0x66, 0x69, 0x78, 0x65, 0x64, 0x33, 0x32, 0x18, 0x19, 0x20, 0x03, 0x28, 0x07,
+	0x52, 0x08, 0x6c, 0x46, 0x69, 0x78, 0x65, 0x64, 0x33, 0x32, 0x12, 0x19, 0x0a, 0x08, 0x6c, 0x5f,
+	0x75, 0x69, 0x6e, 0x74, 0x36, 0x34, 0x18, 0x1a, 0x20, 0x03, 0x28, 0x04, 0x52, 0x07, 0x6c, 0x55,
+	0x69, 0x6e, 0x74, 0x36, 0x34, 0x12, 0x1b, 0x0a, 0x09, 0x6c, 0x5f, 0x66, 0x69, 0x78, 0x65, 0x64,
+	0x36, 0x34, 0x18, 0x1b, 0x20, 0x03, 0x28, 0x06, 0x52, 0x08, 0x6c, 0x46, 0x69, 0x78, 0x65, 0x64,
+	0x36, 0x34, 0x12, 0x17, 0x0a, 0x07, 0x6c, 0x5f, 0x66, 0x6c, 0x6f, 0x61, 0x74, 0x18, 0x1c, 0x20,
+	0x03, 0x28, 0x02, 0x52, 0x06, 0x6c, 0x46, 0x6c, 0x6f, 0x61, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x6c,
+	0x5f, 0x64, 0x6f, 0x75, 0x62, 0x6c, 0x65, 0x18, 0x1d, 0x20, 0x03, 0x28, 0x01, 0x52, 0x07, 0x6c,
+	0x44, 0x6f, 0x75, 0x62, 0x6c, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x6c, 0x5f, 0x73, 0x74, 0x72, 0x69,
+	0x6e, 0x67, 0x18, 0x1e, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x6c, 0x53, 0x74, 0x72, 0x69, 0x6e,
+	0x67, 0x12, 0x17, 0x0a, 0x07, 0x6c, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x1f, 0x20, 0x03,
+	0x28, 0x0c, 0x52, 0x06, 0x6c, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x25, 0x0a, 0x06, 0x6c, 0x5f,
+	0x65, 0x6e, 0x75, 0x6d, 0x18, 0x20, 0x20, 0x03, 0x28, 0x0e, 0x32, 0x0e, 0x2e, 0x72, 0x33, 0x2e,
+	0x45, 0x6e, 0x75, 0x6d, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x52, 0x05, 0x6c, 0x45, 0x6e, 0x75,
+	0x6d, 0x42, 0x2a, 0x5a, 0x28, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f,
+	0x6a, 0x6f, 0x68, 0x6e, 0x73, 0x69, 0x69, 0x6c, 0x76, 0x65, 0x72, 0x2f, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x74, 0x6f, 0x6f, 0x6c, 0x73, 0x2f, 0x73, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x62, 0x06, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_kinds_proto_rawDescOnce sync.Once
+	file_kinds_proto_rawDescData = file_kinds_proto_rawDesc
+)
+
+func file_kinds_proto_rawDescGZIP() []byte {
+	file_kinds_proto_rawDescOnce.Do(func() {
+		file_kinds_proto_rawDescData = protoimpl.X.CompressGZIP(file_kinds_proto_rawDescData)
+	})
+	return file_kinds_proto_rawDescData
+}
+
+var file_kinds_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_kinds_proto_goTypes = []interface{}{
+	(*Kinds)(nil),   // 0: r3.Kinds
+	(EnumValues)(0), // 1: r3.EnumValues
+}
+var file_kinds_proto_depIdxs = []int32{
+	1, // 0: r3.Kinds.venum:type_name -> r3.EnumValues
+	1, // 1: r3.Kinds.l_enum:type_name -> r3.EnumValues
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_kinds_proto_init() }
+func file_kinds_proto_init() {
+	if File_kinds_proto != nil {
+		return
+	}
+	file_sample_proto_init()
+	if !protoimpl.UnsafeEnabled {
+		file_kinds_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Kinds); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_kinds_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_kinds_proto_goTypes,
+		DependencyIndexes: file_kinds_proto_depIdxs,
+		MessageInfos:      file_kinds_proto_msgTypes,
+	}.Build()
+	File_kinds_proto = out.File
+	file_kinds_proto_rawDesc = nil
+	file_kinds_proto_goTypes = nil
+	file_kinds_proto_depIdxs = nil
+}
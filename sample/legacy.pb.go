@@ -0,0 +1,354 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.26.0
+// 	protoc        v3.15.2
+// source: legacy.proto
+
+package sample
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoiface "google.golang.org/protobuf/runtime/protoiface"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Legacy struct {
+	state           protoimpl.MessageState
+	sizeCache       protoimpl.SizeCache
+	unknownFields   protoimpl.UnknownFields
+	extensionFields protoimpl.ExtensionFields
+
+	Name *string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+}
+
+func (x *Legacy) Reset() {
+	*x = Legacy{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_legacy_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Legacy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Legacy) ProtoMessage() {}
+
+func (x *Legacy) ProtoReflect() protoreflect.Message {
+	mi := &file_legacy_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Legacy.ProtoReflect.Descriptor instead.
+func (*Legacy) Descriptor() ([]byte, []int) {
+	return file_legacy_proto_rawDescGZIP(), []int{0}
+}
+
+var extRange_Legacy = []protoiface.ExtensionRangeV1{
+	{Start: 100, End: 199},
+}
+
+// Deprecated: Use Legacy.ProtoReflect.Descriptor.ExtensionRanges instead.
+func (*Legacy) ExtensionRangeArray() []protoiface.ExtensionRangeV1 {
+	return extRange_Legacy
+}
+
+func (x *Legacy) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
+type LegacyDetails struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Count *int32   `protobuf:"varint,1,opt,name=count" json:"count,omitempty"`
+	Child *Legacy  `protobuf:"bytes,2,opt,name=child" json:"child,omitempty"`
+	Notes []string `protobuf:"bytes,3,rep,name=notes" json:"notes,omitempty"`
+}
+
+func (x *LegacyDetails) Reset() {
+	*x = LegacyDetails{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_legacy_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LegacyDetails) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LegacyDetails) ProtoMessage() {}
+
+func (x *LegacyDetails) ProtoReflect() protoreflect.Message {
+	mi := &file_legacy_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LegacyDetails.ProtoReflect.Descriptor instead.
+func (*LegacyDetails) Descriptor() ([]byte, []int) {
+	return file_legacy_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *LegacyDetails) GetCount() int32 {
+	if x != nil && x.Count != nil {
+		return *x.Count
+	}
+	return 0
+}
+
+func (x *LegacyDetails) GetChild() *Legacy {
+	if x != nil {
+		return x.Child
+	}
+	return nil
+}
+
+func (x *LegacyDetails) GetNotes() []string {
+	if x != nil {
+		return x.Notes
+	}
+	return nil
+}
+
+type LegacyHolder struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *LegacyHolder) Reset() {
+	*x = LegacyHolder{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_legacy_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LegacyHolder) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LegacyHolder) ProtoMessage() {}
+
+func (x *LegacyHolder) ProtoReflect() protoreflect.Message {
+	mi := &file_legacy_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LegacyHolder.ProtoReflect.Descriptor instead.
+func (*LegacyHolder) Descriptor() ([]byte, []int) {
+	return file_legacy_proto_rawDescGZIP(), []int{2}
+}
+
+var file_legacy_proto_extTypes = []protoimpl.ExtensionInfo{
+	{
+		ExtendedType:  (*Legacy)(nil),
+		ExtensionType: (*string)(nil),
+		Field:         100,
+		Name:          "r3.nickname",
+		Tag:           "bytes,100,opt,name=nickname",
+		Filename:      "legacy.proto",
+	},
+	{
+		ExtendedType:  (*Legacy)(nil),
+		ExtensionType: (*LegacyDetails)(nil),
+		Field:         101,
+		Name:          "r3.details",
+		Tag:           "bytes,101,opt,name=details",
+		Filename:      "legacy.proto",
+	},
+	{
+		ExtendedType:  (*Legacy)(nil),
+		ExtensionType: ([]int32)(nil),
+		Field:         102,
+		Name:          "r3.scores",
+		Tag:           "varint,102,rep,name=scores",
+		Filename:      "legacy.proto",
+	},
+	{
+		ExtendedType:  (*Legacy)(nil),
+		ExtensionType: (*bool)(nil),
+		Field:         103,
+		Name:          "r3.LegacyHolder.flag",
+		Tag:           "varint,103,opt,name=flag",
+		Filename:      "legacy.proto",
+	},
+}
+
+// Extension fields to Legacy.
+var (
+	// optional string nickname = 100;
+	E_Nickname = &file_legacy_proto_extTypes[0]
+	// optional r3.LegacyDetails details = 101;
+	E_Details = &file_legacy_proto_extTypes[1]
+	// repeated int32 scores = 102;
+	E_Scores = &file_legacy_proto_extTypes[2]
+	// optional bool flag = 103;
+	E_LegacyHolder_Flag = &file_legacy_proto_extTypes[3]
+)
+
+var File_legacy_proto protoreflect.FileDescriptor
+
+var file_legacy_proto_rawDesc = []byte{
+	0x0a, 0x0c, 0x6c, 0x65, 0x67, 0x61, 0x63, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x02,
+	0x72, 0x33, 0x22, 0x23, 0x0a, 0x06, 0x4c, 0x65, 0x67, 0x61, 0x63, 0x79, 0x12, 0x12, 0x0a, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x2a, 0x05, 0x08, 0x64, 0x10, 0xc8, 0x01, 0x22, 0x5d, 0x0a, 0x0d, 0x4c, 0x65, 0x67, 0x61, 0x63,
+	0x79, 0x44, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f, 0x75, 0x6e,
+	0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x20,
+	0x0a, 0x05, 0x63, 0x68, 0x69, 0x6c, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e,
+	0x72, 0x33, 0x2e, 0x4c, 0x65, 0x67, 0x61, 0x63, 0x79, 0x52, 0x05, 0x63, 0x68, 0x69, 0x6c, 0x64,
+	0x12, 0x14, 0x0a, 0x05, 0x6e, 0x6f, 0x74, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x05, 0x6e, 0x6f, 0x74, 0x65, 0x73, 0x22, 0x2e, 0x0a, 0x0c, 0x4c, 0x65, 0x67, 0x61, 0x63, 0x79,
+	0x48, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x32, 0x1e, 0x0a, 0x04, 0x66, 0x6c, 0x61, 0x67, 0x12, 0x0a,
+	0x2e, 0x72, 0x33, 0x2e, 0x4c, 0x65, 0x67, 0x61, 0x63, 0x79, 0x18, 0x67, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x04, 0x66, 0x6c, 0x61, 0x67, 0x3a, 0x26, 0x0a, 0x08, 0x6e, 0x69, 0x63, 0x6b, 0x6e, 0x61,
+	0x6d, 0x65, 0x12, 0x0a, 0x2e, 0x72, 0x33, 0x2e, 0x4c, 0x65, 0x67, 0x61, 0x63, 0x79, 0x18, 0x64,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6e, 0x69, 0x63, 0x6b, 0x6e, 0x61, 0x6d, 0x65, 0x3a, 0x37,
+	0x0a, 0x07, 0x64, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x12, 0x0a, 0x2e, 0x72, 0x33, 0x2e, 0x4c,
+	0x65, 0x67, 0x61, 0x63, 0x79, 0x18, 0x65, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x72, 0x33,
+	0x2e, 0x4c, 0x65, 0x67, 0x61, 0x63, 0x79, 0x44, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x52, 0x07,
+	0x64, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x3a, 0x22, 0x0a, 0x06, 0x73, 0x63, 0x6f, 0x72, 0x65,
+	0x73, 0x12, 0x0a, 0x2e, 0x72, 0x33, 0x2e, 0x4c, 0x65, 0x67, 0x61, 0x63, 0x79, 0x18, 0x66, 0x20,
+	0x03, 0x28, 0x05, 0x52, 0x06, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x73, 0x42, 0x2a, 0x5a, 0x28, 0x67,
+	0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6a, 0x6f, 0x68, 0x6e, 0x73, 0x69,
+	0x69, 0x6c, 0x76, 0x65, 0x72, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x74, 0x6f, 0x6f, 0x6c, 0x73,
+	0x2f, 0x73, 0x61, 0x6d, 0x70, 0x6c, 0x65,
+}
+
+var (
+	file_legacy_proto_rawDescOnce sync.Once
+	file_legacy_proto_rawDescData = file_legacy_proto_rawDesc
+)
+
+func file_legacy_proto_rawDescGZIP() []byte {
+	file_legacy_proto_rawDescOnce.Do(func() {
+		file_legacy_proto_rawDescData = protoimpl.X.CompressGZIP(file_legacy_proto_rawDescData)
+	})
+	return file_legacy_proto_rawDescData
+}
+
+var file_legacy_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_legacy_proto_goTypes = []interface{}{
+	(*Legacy)(nil),        // 0: r3.Legacy
+	(*LegacyDetails)(nil), // 1: r3.LegacyDetails
+	(*LegacyHolder)(nil),  // 2: r3.LegacyHolder
+}
+var file_legacy_proto_depIdxs = []int32{
+	0, // 0: r3.LegacyDetails.child:type_name -> r3.Legacy
+	0, // 1: r3.nickname:extendee -> r3.Legacy
+	0, // 2: r3.details:extendee -> r3.Legacy
+	0, // 3: r3.scores:extendee -> r3.Legacy
+	0, // 4: r3.LegacyHolder.flag:extendee -> r3.Legacy
+	1, // 5: r3.details:type_name -> r3.LegacyDetails
+	6, // [6:6] is the sub-list for method output_type
+	6, // [6:6] is the sub-list for method input_type
+	5, // [5:6] is the sub-list for extension type_name
+	1, // [1:5] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_legacy_proto_init() }
+func file_legacy_proto_init() {
+	if File_legacy_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_legacy_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Legacy); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			case 3:
+				return &v.extensionFields
+			default:
+				return nil
+			}
+		}
+		file_legacy_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LegacyDetails); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_legacy_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LegacyHolder); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_legacy_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 4,
+			NumServices:   0,
+		},
+		GoTypes:           file_legacy_proto_goTypes,
+		DependencyIndexes: file_legacy_proto_depIdxs,
+		MessageInfos:      file_legacy_proto_msgTypes,
+		ExtensionInfos:    file_legacy_proto_extTypes,
+	}.Build()
+	File_legacy_proto = out.File
+	file_legacy_proto_rawDesc = nil
+	file_legacy_proto_goTypes = nil
+	file_legacy_proto_depIdxs = nil
+}
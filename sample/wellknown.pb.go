@@ -0,0 +1,311 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.26.0
+// 	protoc        v3.15.2
+// source: wellknown.proto
+
+package sample
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	anypb "google.golang.org/protobuf/types/known/anypb"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	wrapperspb "google.golang.org/protobuf/types/known/wrapperspb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type WellKnown struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Vtimestamp   *timestamppb.Timestamp   `protobuf:"bytes,1,opt,name=vtimestamp,proto3" json:"vtimestamp,omitempty"`
+	Vduration    *durationpb.Duration     `protobuf:"bytes,2,opt,name=vduration,proto3" json:"vduration,omitempty"`
+	LTimestamp   []*timestamppb.Timestamp `protobuf:"bytes,3,rep,name=l_timestamp,json=lTimestamp,proto3" json:"l_timestamp,omitempty"`
+	VstringValue *wrapperspb.StringValue  `protobuf:"bytes,4,opt,name=vstring_value,json=vstringValue,proto3" json:"vstring_value,omitempty"`
+	Vint64Value  *wrapperspb.Int64Value   `protobuf:"bytes,5,opt,name=vint64_value,json=vint64Value,proto3" json:"vint64_value,omitempty"`
+	Vuint32Value *wrapperspb.UInt32Value  `protobuf:"bytes,6,opt,name=vuint32_value,json=vuint32Value,proto3" json:"vuint32_value,omitempty"`
+	VboolValue   *wrapperspb.BoolValue    `protobuf:"bytes,7,opt,name=vbool_value,json=vboolValue,proto3" json:"vbool_value,omitempty"`
+	VdoubleValue *wrapperspb.DoubleValue  `protobuf:"bytes,8,opt,name=vdouble_value,json=vdoubleValue,proto3" json:"vdouble_value,omitempty"`
+	VbytesValue  *wrapperspb.BytesValue   `protobuf:"bytes,9,opt,name=vbytes_value,json=vbytesValue,proto3" json:"vbytes_value,omitempty"`
+	Vstruct      *structpb.Struct         `protobuf:"bytes,10,opt,name=vstruct,proto3" json:"vstruct,omitempty"`
+	Vany         *anypb.Any               `protobuf:"bytes,11,opt,name=vany,proto3" json:"vany,omitempty"`
+	LAny         []*anypb.Any             `protobuf:"bytes,12,rep,name=l_any,json=lAny,proto3" json:"l_any,omitempty"`
+}
+
+func (x *WellKnown) Reset() {
+	*x = WellKnown{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_wellknown_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WellKnown) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WellKnown) ProtoMessage() {}
+
+func (x *WellKnown) ProtoReflect() protoreflect.Message {
+	mi := &file_wellknown_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WellKnown.ProtoReflect.Descriptor instead.
+func (*WellKnown) Descriptor() ([]byte, []int) {
+	return file_wellknown_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *WellKnown) GetVtimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Vtimestamp
+	}
+	return nil
+}
+
+func (x *WellKnown) GetVduration() *durationpb.Duration {
+	if x != nil {
+		return x.Vduration
+	}
+	return nil
+}
+
+func (x *WellKnown) GetLTimestamp() []*timestamppb.Timestamp {
+	if x != nil {
+		return x.LTimestamp
+	}
+	return nil
+}
+
+func (x *WellKnown) GetVstringValue() *wrapperspb.StringValue {
+	if x != nil {
+		return x.VstringValue
+	}
+	return nil
+}
+
+func (x *WellKnown) GetVint64Value() *wrapperspb.Int64Value {
+	if x != nil {
+		return x.Vint64Value
+	}
+	return nil
+}
+
+func (x *WellKnown) GetVuint32Value() *wrapperspb.UInt32Value {
+	if x != nil {
+		return x.Vuint32Value
+	}
+	return nil
+}
+
+func (x *WellKnown) GetVboolValue() *wrapperspb.BoolValue {
+	if x != nil {
+		return x.VboolValue
+	}
+	return nil
+}
+
+func (x *WellKnown) GetVdoubleValue() *wrapperspb.DoubleValue {
+	if x != nil {
+		return x.VdoubleValue
+	}
+	return nil
+}
+
+func (x *WellKnown) GetVbytesValue() *wrapperspb.BytesValue {
+	if x != nil {
+		return x.VbytesValue
+	}
+	return nil
+}
+
+func (x *WellKnown) GetVstruct() *structpb.Struct {
+	if x != nil {
+		return x.Vstruct
+	}
+	return nil
+}
+
+func (x *WellKnown) GetVany() *anypb.Any {
+	if x != nil {
+		return x.Vany
+	}
+	return nil
+}
+
+func (x *WellKnown) GetLAny() []*anypb.Any {
+	if x != nil {
+		return x.LAny
+	}
+	return nil
+}
+
+var File_wellknown_proto protoreflect.FileDescriptor
+
+var file_wellknown_proto_rawDesc = []byte{
+	0x0a, 0x0f, 0x77, 0x65, 0x6c, 0x6c, 0x6b, 0x6e, 0x6f, 0x77, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x12, 0x02, 0x72, 0x33, 0x1a, 0x19, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x61, 0x6e, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x1a, 0x1e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2f, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x1a, 0x1c, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2f, 0x73, 0x74, 0x72, 0x75, 0x63, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f,
+	0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a,
+	0x1e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2f, 0x77, 0x72, 0x61, 0x70, 0x70, 0x65, 0x72, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22,
+	0xcb, 0x05, 0x0a, 0x09, 0x57, 0x65, 0x6c, 0x6c, 0x4b, 0x6e, 0x6f, 0x77, 0x6e, 0x12, 0x3a, 0x0a,
+	0x0a, 0x76, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0a, 0x76,
+	0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x37, 0x0a, 0x09, 0x76, 0x64, 0x75,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44,
+	0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x09, 0x76, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x12, 0x3b, 0x0a, 0x0b, 0x6c, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x52, 0x0a, 0x6c, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12,
+	0x41, 0x0a, 0x0d, 0x76, 0x73, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x56,
+	0x61, 0x6c, 0x75, 0x65, 0x52, 0x0c, 0x76, 0x73, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x56, 0x61, 0x6c,
+	0x75, 0x65, 0x12, 0x3e, 0x0a, 0x0c, 0x76, 0x69, 0x6e, 0x74, 0x36, 0x34, 0x5f, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x49, 0x6e, 0x74, 0x36, 0x34,
+	0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x0b, 0x76, 0x69, 0x6e, 0x74, 0x36, 0x34, 0x56, 0x61, 0x6c,
+	0x75, 0x65, 0x12, 0x41, 0x0a, 0x0d, 0x76, 0x75, 0x69, 0x6e, 0x74, 0x33, 0x32, 0x5f, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x55, 0x49, 0x6e, 0x74,
+	0x33, 0x32, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x0c, 0x76, 0x75, 0x69, 0x6e, 0x74, 0x33, 0x32,
+	0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x3b, 0x0a, 0x0b, 0x76, 0x62, 0x6f, 0x6f, 0x6c, 0x5f, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x42, 0x6f, 0x6f,
+	0x6c, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x0a, 0x76, 0x62, 0x6f, 0x6f, 0x6c, 0x56, 0x61, 0x6c,
+	0x75, 0x65, 0x12, 0x41, 0x0a, 0x0d, 0x76, 0x64, 0x6f, 0x75, 0x62, 0x6c, 0x65, 0x5f, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x6f, 0x75, 0x62,
+	0x6c, 0x65, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x0c, 0x76, 0x64, 0x6f, 0x75, 0x62, 0x6c, 0x65,
+	0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x3e, 0x0a, 0x0c, 0x76, 0x62, 0x79, 0x74, 0x65, 0x73, 0x5f,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x42, 0x79,
+	0x74, 0x65, 0x73, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x0b, 0x76, 0x62, 0x79, 0x74, 0x65, 0x73,
+	0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x31, 0x0a, 0x07, 0x76, 0x73, 0x74, 0x72, 0x75, 0x63, 0x74,
+	0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x53, 0x74, 0x72, 0x75, 0x63, 0x74, 0x52,
+	0x07, 0x76, 0x73, 0x74, 0x72, 0x75, 0x63, 0x74, 0x12, 0x28, 0x0a, 0x04, 0x76, 0x61, 0x6e, 0x79,
+	0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x41, 0x6e, 0x79, 0x52, 0x04, 0x76, 0x61,
+	0x6e, 0x79, 0x12, 0x29, 0x0a, 0x05, 0x6c, 0x5f, 0x61, 0x6e, 0x79, 0x18, 0x0c, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x14, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x41, 0x6e, 0x79, 0x52, 0x04, 0x6c, 0x41, 0x6e, 0x79, 0x42, 0x2a, 0x5a,
+	0x28, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6a, 0x6f, 0x68, 0x6e,
+	0x73, 0x69, 0x69, 0x6c, 0x76, 0x65, 0x72, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x74, 0x6f, 0x6f,
+	0x6c, 0x73, 0x2f, 0x73, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x33,
+}
+
+var (
+	file_wellknown_proto_rawDescOnce sync.Once
+	file_wellknown_proto_rawDescData = file_wellknown_proto_rawDesc
+)
+
+func file_wellknown_proto_rawDescGZIP() []byte {
+	file_wellknown_proto_rawDescOnce.Do(func() {
+		file_wellknown_proto_rawDescData = protoimpl.X.CompressGZIP(file_wellknown_proto_rawDescData)
+	})
+	return file_wellknown_proto_rawDescData
+}
+
+var file_wellknown_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_wellknown_proto_goTypes = []interface{}{
+	(*WellKnown)(nil),              // 0: r3.WellKnown
+	(*timestamppb.Timestamp)(nil),  // 1: google.protobuf.Timestamp
+	(*durationpb.Duration)(nil),    // 2: google.protobuf.Duration
+	(*wrapperspb.StringValue)(nil), // 3: google.protobuf.StringValue
+	(*wrapperspb.Int64Value)(nil),  // 4: google.protobuf.Int64Value
+	(*wrapperspb.UInt32Value)(nil), // 5: google.protobuf.UInt32Value
+	(*wrapperspb.BoolValue)(nil),   // 6: google.protobuf.BoolValue
+	(*wrapperspb.DoubleValue)(nil), // 7: google.protobuf.DoubleValue
+	(*wrapperspb.BytesValue)(nil),  // 8: google.protobuf.BytesValue
+	(*structpb.Struct)(nil),        // 9: google.protobuf.Struct
+	(*anypb.Any)(nil),              // 10: google.protobuf.Any
+}
+var file_wellknown_proto_depIdxs = []int32{
+	1,  // 0: r3.WellKnown.vtimestamp:type_name -> google.protobuf.Timestamp
+	2,  // 1: r3.WellKnown.vduration:type_name -> google.protobuf.Duration
+	1,  // 2: r3.WellKnown.l_timestamp:type_name -> google.protobuf.Timestamp
+	3,  // 3: r3.WellKnown.vstring_value:type_name -> google.protobuf.StringValue
+	4,  // 4: r3.WellKnown.vint64_value:type_name -> google.protobuf.Int64Value
+	5,  // 5: r3.WellKnown.vuint32_value:type_name -> google.protobuf.UInt32Value
+	6,  // 6: r3.WellKnown.vbool_value:type_name -> google.protobuf.BoolValue
+	7,  // 7: r3.WellKnown.vdouble_value:type_name -> google.protobuf.DoubleValue
+	8,  // 8: r3.WellKnown.vbytes_value:type_name -> google.protobuf.BytesValue
+	9,  // 9: r3.WellKnown.vstruct:type_name -> google.protobuf.Struct
+	10, // 10: r3.WellKnown.vany:type_name -> google.protobuf.Any
+	10, // 11: r3.WellKnown.l_any:type_name -> google.protobuf.Any
+	12, // [12:12] is the sub-list for method output_type
+	12, // [12:12] is the sub-list for method input_type
+	12, // [12:12] is the sub-list for extension type_name
+	12, // [12:12] is the sub-list for extension extendee
+	0,  // [0:12] is the sub-list for field type_name
+}
+
+func init() { file_wellknown_proto_init() }
+func file_wellknown_proto_init() {
+	if File_wellknown_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_wellknown_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WellKnown); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_wellknown_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_wellknown_proto_goTypes,
+		DependencyIndexes: file_wellknown_proto_depIdxs,
+		MessageInfos:      file_wellknown_proto_msgTypes,
+	}.Build()
+	File_wellknown_proto = out.File
+	file_wellknown_proto_rawDesc = nil
+	file_wellknown_proto_goTypes = nil
+	file_wellknown_proto_depIdxs = nil
+}
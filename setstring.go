@@ -0,0 +1,216 @@
+package prototools
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	timestampName protoreflect.FullName = "google.protobuf.Timestamp"
+	durationName  protoreflect.FullName = "google.protobuf.Duration"
+)
+
+// timeLayouts are the layouts we will try when parsing a string into a time. These cover RFC 3339 and
+// what HTML date and datetime-local inputs send. Layouts without a zone are in UTC.
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+/*
+SetFieldFromString is like UpdateProtoField(), except that the value is a string that is parsed according to the
+field's Kind. This is useful for web apps, where everything from query parameters and forms is a string.
+
+Strings are parsed as follows:
+
+	╔═══════════════════════════╤════════════════════════════════════════════════════════════╗
+	║ Protobuf kind             │ String format                                              ║
+	╠═══════════════════════════╪════════════════════════════════════════════════════════════╣
+	║ StringKind                │ As is                                                      ║
+	║ BoolKind                  │ true/false, t/f, yes/no, y/n, on/off, 1/0 (any case)       ║
+	║ All integer kinds         │ Base 10, must fit in the field                             ║
+	║ FloatKind, DoubleKind     │ As strconv.ParseFloat(), must fit in the field             ║
+	║ BytesKind                 │ Base64, standard or URL encoding, padded or not            ║
+	║ EnumKind                  │ The number or the ProtoName, JSONName or TitledName of the ║
+	║                           │ value, as found in ForwardLookup                           ║
+	║ google.protobuf.Timestamp │ RFC 3339 or an HTML date/datetime-local value (UTC)        ║
+	║ google.protobuf.Duration  │ As time.ParseDuration(), aka "1h30m"                       ║
+	╚═══════════════════════════╧════════════════════════════════════════════════════════════╝
+
+An int64 field whose name ends in _time can also be set with a time, which is stored as unix time(epoch)
+in seconds, the same way FieldAsStr() reads it.
+
+Any error is an Error that says which field failed and why. A bad string has the code ErrParse and a value that
+does not fit has the code ErrValueOutOfRange.
+*/
+func SetFieldFromString(msg proto.Message, fqPath, s string, options ...UpdateOption) error {
+	return updateField(msg, fqPath, options, func(m protoreflect.Message, fd protoreflect.FieldDescriptor) (protoreflect.Value, error) {
+		return stringValue(m, fd, fqPath, s)
+	})
+}
+
+// stringValue parses s into a value for field fd in msg. fqPath is used in error messages.
+func stringValue(msg protoreflect.Message, fd protoreflect.FieldDescriptor, fqPath, s string) (protoreflect.Value, error) {
+	vd := valueDesc(fd)
+
+	switch vd.Kind() {
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(s), nil
+	case protoreflect.BoolKind:
+		switch strings.ToLower(strings.TrimSpace(s)) {
+		case "true", "t", "yes", "y", "on", "1":
+			return protoreflect.ValueOfBool(true), nil
+		case "false", "f", "no", "n", "off", "0":
+			return protoreflect.ValueOfBool(false), nil
+		}
+		return protoreflect.Value{}, parseErr(fqPath, vd, s, "not a bool")
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		i, err := strconv.ParseInt(strings.TrimSpace(s), 10, 32)
+		if err != nil {
+			return protoreflect.Value{}, numErr(fqPath, vd, s, err)
+		}
+		return protoreflect.ValueOfInt32(int32(i)), nil
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		i, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+		if err != nil {
+			if strings.HasSuffix(string(fd.Name()), "_time") {
+				if t, ok := parseTime(s); ok {
+					return protoreflect.ValueOfInt64(t.Unix()), nil
+				}
+			}
+			return protoreflect.Value{}, numErr(fqPath, vd, s, err)
+		}
+		return protoreflect.ValueOfInt64(i), nil
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		u, err := strconv.ParseUint(strings.TrimSpace(s), 10, 32)
+		if err != nil {
+			return protoreflect.Value{}, numErr(fqPath, vd, s, err)
+		}
+		return protoreflect.ValueOfUint32(uint32(u)), nil
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		u, err := strconv.ParseUint(strings.TrimSpace(s), 10, 64)
+		if err != nil {
+			return protoreflect.Value{}, numErr(fqPath, vd, s, err)
+		}
+		return protoreflect.ValueOfUint64(u), nil
+	case protoreflect.FloatKind:
+		f, err := strconv.ParseFloat(strings.TrimSpace(s), 32)
+		if err != nil {
+			return protoreflect.Value{}, numErr(fqPath, vd, s, err)
+		}
+		return protoreflect.ValueOfFloat32(float32(f)), nil
+	case protoreflect.DoubleKind:
+		f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+		if err != nil {
+			return protoreflect.Value{}, numErr(fqPath, vd, s, err)
+		}
+		return protoreflect.ValueOfFloat64(f), nil
+	case protoreflect.BytesKind:
+		for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.URLEncoding, base64.RawStdEncoding, base64.RawURLEncoding} {
+			if b, err := enc.DecodeString(s); err == nil {
+				return protoreflect.ValueOfBytes(b), nil
+			}
+		}
+		return protoreflect.Value{}, parseErr(fqPath, vd, s, "not base64")
+	case protoreflect.EnumKind:
+		return enumFromString(vd, fqPath, s)
+	case protoreflect.MessageKind:
+		switch vd.Message().FullName() {
+		case timestampName:
+			t, ok := parseTime(s)
+			if !ok {
+				return protoreflect.Value{}, parseErr(fqPath, vd, s, "not an RFC 3339 time")
+			}
+			return secondsNanos(msg, fd, t.Unix(), int32(t.Nanosecond())), nil
+		case durationName:
+			d, err := time.ParseDuration(strings.TrimSpace(s))
+			if err != nil {
+				return protoreflect.Value{}, parseErr(fqPath, vd, s, err.Error())
+			}
+			return secondsNanos(msg, fd, int64(d/time.Second), int32(d%time.Second)), nil
+		}
+	}
+	return protoreflect.Value{}, Errorf(ErrTypeMismatch, "field(%s) is of type %s, which cannot be set from a string", fqPath, kindName(vd))
+}
+
+// enumFromString finds the enum value for s, which can be the number or any spelling ForwardLookup knows.
+func enumFromString(fd protoreflect.FieldDescriptor, fqPath, s string) (protoreflect.Value, error) {
+	ed := fd.Enum()
+	s = strings.TrimSpace(s)
+
+	if i, err := strconv.ParseInt(s, 10, 32); err == nil {
+		if ed.Values().ByNumber(protoreflect.EnumNumber(i)) == nil {
+			return protoreflect.Value{}, Errorf(ErrValueOutOfRange, "field(%s) is an enum and %d is not a valid value", fqPath, i)
+		}
+		return protoreflect.ValueOfEnum(protoreflect.EnumNumber(i)), nil
+	}
+
+	forward := ForwardLookup{}
+	for i := 0; i < ed.Values().Len(); i++ {
+		v := ed.Values().Get(i)
+		popForward(forward, string(ed.Name()), string(v.Name()), int32(v.Number()))
+	}
+	rec, ok := forward.Find(s)
+	if !ok {
+		return protoreflect.Value{}, parseErr(fqPath, fd, s, "not a value of enum "+string(ed.FullName()))
+	}
+	return protoreflect.ValueOfEnum(protoreflect.EnumNumber(rec.Int32)), nil
+}
+
+// parseTime parses s with each of the timeLayouts.
+func parseTime(s string) (time.Time, bool) {
+	s = strings.TrimSpace(s)
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// secondsNanos returns a new message for field fd in msg that has its "seconds" and "nanos" fields set. This
+// works for both google.protobuf.Timestamp and google.protobuf.Duration.
+func secondsNanos(msg protoreflect.Message, fd protoreflect.FieldDescriptor, seconds int64, nanos int32) protoreflect.Value {
+	return setSecondsNanos(newFieldMessage(msg, fd), seconds, nanos)
+}
+
+// newFieldMessage returns a new message that can be stored in field fd of msg. If fd is a repeated field or
+// map, the message can be stored as an entry. This does not change msg.
+func newFieldMessage(msg protoreflect.Message, fd protoreflect.FieldDescriptor) protoreflect.Message {
+	switch {
+	case fd.IsList():
+		return msg.NewField(fd).List().NewElement().Message()
+	case fd.IsMap():
+		return msg.NewField(fd).Map().NewValue().Message()
+	}
+	return msg.NewField(fd).Message()
+}
+
+// kindName returns the name of the kind for fd, using the message's full name for messages.
+func kindName(fd protoreflect.FieldDescriptor) string {
+	if fd.Kind() == protoreflect.MessageKind {
+		return string(fd.Message().FullName())
+	}
+	return fd.Kind().String()
+}
+
+func parseErr(fqPath string, fd protoreflect.FieldDescriptor, s, why string) Error {
+	return Errorf(ErrParse, "field(%s) is of type %s, could not parse %q: %s", fqPath, kindName(fd), s, why)
+}
+
+// numErr converts an error from the strconv package into an Error.
+func numErr(fqPath string, fd protoreflect.FieldDescriptor, s string, err error) Error {
+	if errors.Is(err, strconv.ErrRange) {
+		return Errorf(ErrValueOutOfRange, "field(%s) is of type %s, value %q does not fit", fqPath, kindName(fd), s)
+	}
+	return parseErr(fqPath, fd, s, "not a number")
+}
@@ -0,0 +1,89 @@
+package prototools
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "github.com/johnsiilver/prototools/sample"
+)
+
+func TestSetFieldFromString(t *testing.T) {
+	ts := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+
+	tests := []struct {
+		desc  string
+		msg   proto.Message
+		field string
+		s     string
+		want  proto.Message
+		code  ErrCode
+	}{
+		{desc: "string", msg: &pb.Kinds{}, field: "vstring", s: " hello ", want: &pb.Kinds{Vstring: " hello "}},
+		{desc: "bool true", msg: &pb.Kinds{}, field: "vbool", s: "true", want: &pb.Kinds{Vbool: true}},
+		{desc: "bool on", msg: &pb.Kinds{}, field: "vbool", s: "On", want: &pb.Kinds{Vbool: true}},
+		{desc: "bool 0", msg: &pb.Kinds{Vbool: true}, field: "vbool", s: "0", want: &pb.Kinds{}},
+		{desc: "int32", msg: &pb.Kinds{}, field: "vint32", s: "-32", want: &pb.Kinds{Vint32: -32}},
+		{desc: "sfixed64", msg: &pb.Kinds{}, field: "vsfixed64", s: "9223372036854775807", want: &pb.Kinds{Vsfixed64: 9223372036854775807}},
+		{desc: "uint32", msg: &pb.Kinds{}, field: "vuint32", s: "32", want: &pb.Kinds{Vuint32: 32}},
+		{desc: "fixed64", msg: &pb.Kinds{}, field: "vfixed64", s: "18446744073709551615", want: &pb.Kinds{Vfixed64: 18446744073709551615}},
+		{desc: "float", msg: &pb.Kinds{}, field: "vfloat", s: "1.5", want: &pb.Kinds{Vfloat: 1.5}},
+		{desc: "double", msg: &pb.Kinds{}, field: "vdouble", s: "-2.25", want: &pb.Kinds{Vdouble: -2.25}},
+		{desc: "bytes std", msg: &pb.Kinds{}, field: "vbytes", s: "aGVsbG8=", want: &pb.Kinds{Vbytes: []byte("hello")}},
+		{desc: "bytes raw url", msg: &pb.Kinds{}, field: "vbytes", s: "_-8", want: &pb.Kinds{Vbytes: []byte{0xff, 0xef}}},
+		{desc: "enum proto name", msg: &pb.Kinds{}, field: "venum", s: "EV_Not_Ok", want: &pb.Kinds{Venum: pb.EnumValues_EV_Not_Ok}},
+		{desc: "enum json name", msg: &pb.Kinds{}, field: "venum", s: "evNotOk", want: &pb.Kinds{Venum: pb.EnumValues_EV_Not_Ok}},
+		{desc: "enum titled name", msg: &pb.Kinds{}, field: "venum", s: "Eh", want: &pb.Kinds{Venum: pb.EnumValues_EV_Eh}},
+		{desc: "enum number", msg: &pb.Kinds{}, field: "venum", s: "1", want: &pb.Kinds{Venum: pb.EnumValues_EV_Ok}},
+		{desc: "repeated index", msg: &pb.Kinds{LInt32: []int32{1, 2}}, field: "l_int32[1]", s: "5", want: &pb.Kinds{LInt32: []int32{1, 5}}},
+		{desc: "map key", msg: &pb.Customer{}, field: `labels["env"]`, s: "prod", want: &pb.Customer{Labels: map[string]string{"env": "prod"}}},
+		{desc: "error: intermediate not set", msg: &pb.Layer0{}, field: "layer1.vstring", s: "hi", code: ErrIntermdiateNotSet},
+		{desc: "_time as unix", msg: &pb.Supported{}, field: "v_time", s: "1614834367", want: &pb.Supported{VTime: ts.Unix()}},
+		{desc: "_time as RFC3339", msg: &pb.Supported{}, field: "v_time", s: "2021-03-04T05:06:07Z", want: &pb.Supported{VTime: ts.Unix()}},
+		{desc: "timestamp RFC3339", msg: &pb.WellKnown{}, field: "vtimestamp", s: "2021-03-04T05:06:07.5Z", want: &pb.WellKnown{Vtimestamp: timestamppb.New(ts.Add(500 * time.Millisecond))}},
+		{desc: "timestamp datetime-local", msg: &pb.WellKnown{}, field: "vtimestamp", s: "2021-03-04T05:06", want: &pb.WellKnown{Vtimestamp: timestamppb.New(ts.Add(-7 * time.Second))}},
+		{desc: "timestamp date", msg: &pb.WellKnown{}, field: "vtimestamp", s: "2021-03-04", want: &pb.WellKnown{Vtimestamp: timestamppb.New(time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC))}},
+		{desc: "repeated timestamp", msg: &pb.WellKnown{LTimestamp: []*timestamppb.Timestamp{{}}}, field: "l_timestamp[0]", s: "2021-03-04T05:06:07Z", want: &pb.WellKnown{LTimestamp: []*timestamppb.Timestamp{timestamppb.New(ts)}}},
+		{desc: "duration", msg: &pb.WellKnown{}, field: "vduration", s: "1h1.5s", want: &pb.WellKnown{Vduration: durationpb.New(time.Hour + 1500*time.Millisecond)}},
+		{desc: "error: bad bool", msg: &pb.Kinds{}, field: "vbool", s: "maybe", code: ErrParse},
+		{desc: "error: bad int", msg: &pb.Kinds{}, field: "vint64", s: "1.5", code: ErrParse},
+		{desc: "error: int32 overflow", msg: &pb.Kinds{}, field: "vsint32", s: "2147483648", code: ErrValueOutOfRange},
+		{desc: "error: negative uint", msg: &pb.Kinds{}, field: "vuint64", s: "-1", code: ErrParse},
+		{desc: "error: uint32 overflow", msg: &pb.Kinds{}, field: "vfixed32", s: "4294967296", code: ErrValueOutOfRange},
+		{desc: "error: float overflow", msg: &pb.Kinds{}, field: "vfloat", s: "1e40", code: ErrValueOutOfRange},
+		{desc: "error: bad base64", msg: &pb.Kinds{}, field: "vbytes", s: "!!!", code: ErrParse},
+		{desc: "error: bad enum name", msg: &pb.Kinds{}, field: "venum", s: "EV_Nope", code: ErrParse},
+		{desc: "error: bad enum number", msg: &pb.Kinds{}, field: "venum", s: "20", code: ErrValueOutOfRange},
+		{desc: "error: bad timestamp", msg: &pb.WellKnown{}, field: "vtimestamp", s: "yesterday", code: ErrParse},
+		{desc: "error: bad duration", msg: &pb.WellKnown{}, field: "vduration", s: "1 hour", code: ErrParse},
+		{desc: "error: message", msg: &pb.Layer0{}, field: "layer1", s: "hi", code: ErrTypeMismatch},
+		{desc: "error: no field", msg: &pb.Kinds{}, field: "nope", s: "hi", code: ErrBadFieldName},
+	}
+
+	for _, test := range tests {
+		err := SetFieldFromString(test.msg, test.field, test.s)
+		switch {
+		case err == nil && test.code != ErrUnknown:
+			t.Errorf("TestSetFieldFromString(%s): got err == nil, want err != nil", test.desc)
+			continue
+		case err != nil && test.code == ErrUnknown:
+			t.Errorf("TestSetFieldFromString(%s): got err == %s, want err == nil", test.desc, err)
+			continue
+		case err != nil:
+			var e Error
+			if !errors.As(err, &e) || e.Code != test.code {
+				t.Errorf("TestSetFieldFromString(%s): got err == %s, want code %s", test.desc, err, test.code)
+			}
+			continue
+		}
+		if diff := cmp.Diff(test.want, test.msg, protocmp.Transform()); diff != "" {
+			t.Errorf("TestSetFieldFromString(%s): -want/+got:\n%s", test.desc, diff)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package prototools
+
+import (
+	"reflect"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+// UnknownField is a field that was decoded into a message whose descriptor does not have it. This usually
+// means the message was written by something with a newer version of the .proto file.
+type UnknownField struct {
+	// Path is the fqPath to the message holding the field, "" for the message passed in.
+	Path string
+	// Number is the field number from the tag.
+	Number protowire.Number
+	// WireType is the wire type from the tag.
+	WireType protowire.Type
+	// Raw is the encoded field, including the tag.
+	Raw []byte
+	// Value is a best effort decode of the field. Without the .proto file we can't know the field's type, so
+	// this is a guess:
+	//
+	//	╔════════════╤═══════════════════════════════════════════════════════════════════════╗
+	//	║ Wire type  │ Value                                                                 ║
+	//	╠════════════╪═══════════════════════════════════════════════════════════════════════╣
+	//	║ Varint     │ uint64                                                                ║
+	//	║ Fixed32    │ uint32                                                                ║
+	//	║ Fixed64    │ uint64                                                                ║
+	//	║ Bytes      │ string if it is printable UTF-8, []UnknownField if it decodes as a    ║
+	//	║            │ message, otherwise []byte                                             ║
+	//	║ StartGroup │ []UnknownField                                                        ║
+	//	╚════════════╧═══════════════════════════════════════════════════════════════════════╝
+	//
+	// A nested []UnknownField has the same Path as its parent.
+	Value interface{}
+}
+
+/*
+UnknownFields returns the unknown fields in msg and every message inside of it, in the order they are
+stored. Messages inside of msg are searched in the order their fields are declared, map entries in sorted order.
+It is an error if the unknown fields of a message cannot be parsed.
+
+This is useful to detect schema drift, where a service with an older .proto file round trips a message written
+with a newer one.
+*/
+func UnknownFields(msg proto.Message) ([]UnknownField, error) {
+	var fields []UnknownField
+	var err error
+	rangeMessages(nil, msg.ProtoReflect(), func(path []string, m protoreflect.Message) bool {
+		var uf []UnknownField
+		uf, err = parseUnknown(strings.Join(path, "."), m.GetUnknown())
+		if err != nil {
+			return false
+		}
+		fields = append(fields, uf...)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// StripUnknown removes the unknown fields from msg and every message inside of it.
+func StripUnknown(msg proto.Message) {
+	rangeMessages(nil, msg.ProtoReflect(), func(_ []string, m protoreflect.Message) bool {
+		if len(m.GetUnknown()) > 0 {
+			m.SetUnknown(nil)
+		}
+		return true
+	})
+}
+
+// ShowUnknownFields is an option for Equal() and HumanDiff() that shows unknown fields as []UnknownField
+// instead of raw bytes, which makes a difference in them easier to read. Unknown fields are compared either way.
+func ShowUnknownFields() cmp.Option {
+	// A []byte is assignable to RawFields, so only transform values that are RawFields. Otherwise
+	// UnknownField.Raw would be transformed again.
+	rawType := reflect.TypeOf(protoreflect.RawFields(nil))
+	isRaw := func(p cmp.Path) bool {
+		return p.Last().Type() == rawType
+	}
+	return cmp.FilterPath(isRaw, cmp.Transformer("prototools.UnknownFields", func(raw protoreflect.RawFields) []UnknownField {
+		fields, err := parseUnknown("", raw)
+		if err != nil {
+			return []UnknownField{{Raw: raw, Value: []byte(raw)}}
+		}
+		return fields
+	}))
+}
+
+// IgnoreUnknownFields is an option for Equal() and HumanDiff() that ignores unknown fields.
+func IgnoreUnknownFields() cmp.Option {
+	return protocmp.IgnoreUnknown()
+}
+
+// rangeMessages calls f with m and every message set inside of m, depth first. path is the path to m.
+// If f returns false, this stops.
+func rangeMessages(path []string, m protoreflect.Message, f func(path []string, m protoreflect.Message) bool) bool {
+	if !f(path, m) {
+		return false
+	}
+
+	fields := m.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if vd := valueDesc(fd); vd.Kind() != protoreflect.MessageKind && vd.Kind() != protoreflect.GroupKind {
+			continue
+		}
+		if !m.Has(fd) {
+			continue
+		}
+
+		name := string(fd.Name())
+		switch {
+		case fd.IsList():
+			l := m.Get(fd).List()
+			for x := 0; x < l.Len(); x++ {
+				if !rangeMessages(append(path, indexName(fd, x)), l.Get(x).Message(), f) {
+					return false
+				}
+			}
+		case fd.IsMap():
+			mp := m.Get(fd).Map()
+			for _, k := range sortedMapKeys(mp) {
+				if !rangeMessages(append(path, name+"["+keySelector(k)+"]"), mp.Get(k).Message(), f) {
+					return false
+				}
+			}
+		default:
+			if !rangeMessages(append(path, name), m.Get(fd).Message(), f) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// parseUnknown parses the unknown fields in b, which are in the message at path.
+func parseUnknown(path string, b []byte) ([]UnknownField, error) {
+	var fields []UnknownField
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return nil, Errorf(ErrParse, "unknown fields in message(%s) could not be parsed: %s", path, protowire.ParseError(n))
+		}
+		tagLen := n
+
+		uf := UnknownField{Path: path, Number: num, WireType: typ}
+		body := b[tagLen:]
+		switch typ {
+		case protowire.VarintType:
+			uf.Value, n = protowire.ConsumeVarint(body)
+		case protowire.Fixed32Type:
+			uf.Value, n = protowire.ConsumeFixed32(body)
+		case protowire.Fixed64Type:
+			uf.Value, n = protowire.ConsumeFixed64(body)
+		case protowire.BytesType:
+			var v []byte
+			v, n = protowire.ConsumeBytes(body)
+			uf.Value = guessBytes(path, v)
+		case protowire.StartGroupType:
+			var v []byte
+			v, n = protowire.ConsumeGroup(num, body)
+			if n >= 0 {
+				group, err := parseUnknown(path, v)
+				if err != nil {
+					return nil, err
+				}
+				uf.Value = group
+			}
+		default:
+			n = -1
+		}
+		if n < 0 {
+			return nil, Errorf(ErrParse, "unknown field(%d) in message(%s) could not be parsed: %s", num, path, protowire.ParseError(n))
+		}
+
+		uf.Raw = b[:tagLen+n]
+		fields = append(fields, uf)
+		b = b[tagLen+n:]
+	}
+	return fields, nil
+}
+
+// guessBytes guesses what a length delimited field holds. See UnknownField.Value.
+func guessBytes(path string, b []byte) interface{} {
+	if len(b) > 0 && utf8.Valid(b) && isPrintable(string(b)) {
+		return string(b)
+	}
+	if len(b) > 0 {
+		if msg, err := parseUnknown(path, b); err == nil {
+			return msg
+		}
+	}
+	return b
+}
+
+// isPrintable returns true if every rune in s is printable or whitespace.
+func isPrintable(s string) bool {
+	for _, r := range s {
+		if !unicode.IsPrint(r) && !unicode.IsSpace(r) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,146 @@
+package prototools
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/johnsiilver/prototools/sample"
+)
+
+// newUnknown returns a Customer with unknown fields in it and in its second order.
+func newUnknown() *pb.Customer {
+	c := &pb.Customer{
+		Name:   "Bob",
+		Orders: []*pb.Order{{Id: "1"}, {Id: "2"}},
+	}
+
+	var b []byte
+	b = protowire.AppendTag(b, 20, protowire.VarintType)
+	b = protowire.AppendVarint(b, 150)
+	b = protowire.AppendTag(b, 21, protowire.BytesType)
+	b = protowire.AppendString(b, "hello")
+	c.ProtoReflect().SetUnknown(b)
+
+	var sub []byte
+	sub = protowire.AppendTag(sub, 1, protowire.VarintType)
+	sub = protowire.AppendVarint(sub, 1)
+	var o []byte
+	o = protowire.AppendTag(o, 30, protowire.Fixed32Type)
+	o = protowire.AppendFixed32(o, 7)
+	o = protowire.AppendTag(o, 31, protowire.BytesType)
+	o = protowire.AppendBytes(o, sub)
+	c.Orders[1].ProtoReflect().SetUnknown(o)
+	return c
+}
+
+func TestUnknownFields(t *testing.T) {
+	tests := []struct {
+		desc string
+		msg  proto.Message
+		want []UnknownField
+		err  bool
+	}{
+		{
+			desc: "no unknown fields",
+			msg:  &pb.Customer{Name: "Bob", Orders: []*pb.Order{{Id: "1"}}},
+		},
+		{
+			desc: "nested unknown fields",
+			msg:  newUnknown(),
+			want: []UnknownField{
+				{Path: "", Number: 20, WireType: protowire.VarintType, Value: uint64(150)},
+				{Path: "", Number: 21, WireType: protowire.BytesType, Value: "hello"},
+				{Path: "orders[1]", Number: 30, WireType: protowire.Fixed32Type, Value: uint32(7)},
+				{
+					Path: "orders[1]", Number: 31, WireType: protowire.BytesType,
+					Value: []UnknownField{
+						{Path: "orders[1]", Number: 1, WireType: protowire.VarintType, Value: uint64(1)},
+					},
+				},
+			},
+		},
+		{
+			desc: "unknown fields cannot be parsed",
+			msg: func() proto.Message {
+				c := &pb.Customer{}
+				c.ProtoReflect().SetUnknown(protowire.AppendTag(nil, 20, protowire.BytesType))
+				return c
+			}(),
+			err: true,
+		},
+	}
+
+	config := pretty.Config{Diffable: true, IncludeUnexported: false, SkipZeroFields: true}
+	for _, test := range tests {
+		got, err := UnknownFields(test.msg)
+		switch {
+		case err == nil && test.err:
+			t.Errorf("TestUnknownFields(%s): got err == nil, want err != nil", test.desc)
+			continue
+		case err != nil && !test.err:
+			t.Errorf("TestUnknownFields(%s): got err == %s, want err == nil", test.desc, err)
+			continue
+		case err != nil:
+			continue
+		}
+
+		for i := range got {
+			if len(got[i].Raw) == 0 {
+				t.Errorf("TestUnknownFields(%s): field %d has no raw bytes", test.desc, got[i].Number)
+			}
+			got[i].Raw = nil
+			if sub, ok := got[i].Value.([]UnknownField); ok {
+				for x := range sub {
+					sub[x].Raw = nil
+				}
+			}
+		}
+		if diff := config.Compare(test.want, got); diff != "" {
+			t.Errorf("TestUnknownFields(%s): -want/+got:\n%s", test.desc, diff)
+		}
+	}
+}
+
+func TestStripUnknown(t *testing.T) {
+	msg := newUnknown()
+	StripUnknown(msg)
+
+	got, err := UnknownFields(msg)
+	if err != nil {
+		t.Fatalf("TestStripUnknown: got err == %s, want err == nil", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("TestStripUnknown: got %d unknown fields, want 0", len(got))
+	}
+	if msg.Name != "Bob" || len(msg.Orders) != 2 {
+		t.Errorf("TestStripUnknown: known fields were changed: %v", msg)
+	}
+}
+
+func TestCompareUnknown(t *testing.T) {
+	a := newUnknown()
+	b := newUnknown()
+	var raw []byte
+	raw = protowire.AppendTag(raw, 20, protowire.VarintType)
+	raw = protowire.AppendVarint(raw, 151)
+	raw = protowire.AppendTag(raw, 21, protowire.BytesType)
+	raw = protowire.AppendString(raw, "hello")
+	b.ProtoReflect().SetUnknown(raw)
+
+	if diff := Equal(a, b); diff == "" {
+		t.Errorf("TestCompareUnknown(default): got no diff, want a diff")
+	}
+	if diff := Equal(a, b, ShowUnknownFields()); !strings.Contains(diff, "151") {
+		t.Errorf("TestCompareUnknown(ShowUnknownFields): got diff %q, want it to contain the decoded value", diff)
+	}
+	if diff := Equal(a, b, IgnoreUnknownFields()); diff != "" {
+		t.Errorf("TestCompareUnknown(IgnoreUnknownFields): got diff:\n%s", diff)
+	}
+	if diff := HumanDiff(a, newUnknown(), ShowUnknownFields()); diff != "" {
+		t.Errorf("TestCompareUnknown(equal): got diff:\n%s", diff)
+	}
+}
@@ -0,0 +1,145 @@
+package prototools
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+const (
+	structName    protoreflect.FullName = "google.protobuf.Struct"
+	listValueName protoreflect.FullName = "google.protobuf.ListValue"
+	valueName     protoreflect.FullName = "google.protobuf.Value"
+)
+
+// wrapperNames are the well-known types that wrap a single scalar in a field called "value".
+var wrapperNames = map[protoreflect.FullName]bool{
+	"google.protobuf.DoubleValue": true,
+	"google.protobuf.FloatValue":  true,
+	"google.protobuf.Int64Value":  true,
+	"google.protobuf.UInt64Value": true,
+	"google.protobuf.Int32Value":  true,
+	"google.protobuf.UInt32Value": true,
+	"google.protobuf.BoolValue":   true,
+	"google.protobuf.StringValue": true,
+	"google.protobuf.BytesValue":  true,
+}
+
+// wellKnownValue returns the Go value for m if it is a well-known type, using the table in GetField().
+// ok is false if m is not a well-known type or is not set.
+func wellKnownValue(m protoreflect.Message) (v interface{}, ok bool) {
+	if m == nil || !m.IsValid() {
+		return nil, false
+	}
+	md := m.Descriptor()
+	fields := md.Fields()
+
+	switch name := md.FullName(); {
+	case name == timestampName:
+		secs, nanos := m.Get(fields.ByName("seconds")).Int(), m.Get(fields.ByName("nanos")).Int()
+		return time.Unix(secs, nanos).UTC(), true
+	case name == durationName:
+		secs, nanos := m.Get(fields.ByName("seconds")).Int(), m.Get(fields.ByName("nanos")).Int()
+		return time.Duration(secs)*time.Second + time.Duration(nanos), true
+	case wrapperNames[name]:
+		return m.Get(fields.ByName("value")).Interface(), true
+	case name == structName, name == listValueName, name == valueName:
+		b, err := protojson.Marshal(m.Interface())
+		if err != nil {
+			return nil, false
+		}
+		if err := json.Unmarshal(b, &v); err != nil {
+			return nil, false
+		}
+		return v, true
+	}
+	return nil, false
+}
+
+// wellKnownStr formats m for FieldAsStr() if it is a Timestamp, Duration or wrapper. A Timestamp is formatted
+// like an int64 "_time" field, a Duration with time.Duration.String() and a wrapper like its wrapped scalar.
+func wellKnownStr(m protoreflect.Message, pretty bool) (string, bool) {
+	v, ok := wellKnownValue(m)
+	if !ok {
+		return "", false
+	}
+	switch t := v.(type) {
+	case time.Time:
+		return fmt.Sprintf("%v", t), true
+	case time.Duration:
+		return t.String(), true
+	}
+	if !wrapperNames[m.Descriptor().FullName()] {
+		return "", false
+	}
+	vfd := m.Descriptor().Fields().ByName("value")
+	s, err := valueAsStr(FieldValue{Value: v, Kind: vfd.Kind(), FieldDesc: vfd}, "", pretty)
+	if err != nil {
+		return fmt.Sprintf("%v", v), true
+	}
+	return s, true
+}
+
+// wellKnownProto converts value into a well-known type for the message field fd. This handles a time.Time for
+// a Timestamp, a time.Duration for a Duration and a scalar for a wrapper, which follows the same rules as
+// a scalar field. ok is false if fd is not one of those types or value is a message.
+func wellKnownProto(fd protoreflect.FieldDescriptor, fieldName protoreflect.Name, value interface{}) (val protoreflect.Value, ok bool, err error) {
+	if fd.Kind() != protoreflect.MessageKind {
+		return protoreflect.Value{}, false, nil
+	}
+	switch value.(type) {
+	case protoreflect.ProtoMessage, protoreflect.Message:
+		return protoreflect.Value{}, false, nil
+	}
+
+	md := fd.Message()
+	switch name := md.FullName(); {
+	case name == timestampName:
+		t, isTime := value.(time.Time)
+		if !isTime {
+			return protoreflect.Value{}, true, Errorf(ErrTypeMismatch, "field(%s) is a %s, you sent a %T, it must be a time.Time", fieldName, name, value)
+		}
+		return setSecondsNanos(newMessage(md), t.Unix(), int32(t.Nanosecond())), true, nil
+	case name == durationName:
+		d, isDur := value.(time.Duration)
+		if !isDur {
+			return protoreflect.Value{}, true, Errorf(ErrTypeMismatch, "field(%s) is a %s, you sent a %T, it must be a time.Duration", fieldName, name, value)
+		}
+		return setSecondsNanos(newMessage(md), int64(d/time.Second), int32(d%time.Second)), true, nil
+	case wrapperNames[name]:
+		vfd := md.Fields().ByName("value")
+		v, err := protoValue(vfd, value)
+		if err != nil {
+			if e, isErr := err.(Error); isErr {
+				return protoreflect.Value{}, true, Errorf(e.Code, "field(%s) is a %s: %s", fieldName, name, e.Msg)
+			}
+			return protoreflect.Value{}, true, err
+		}
+		m := newMessage(md)
+		m.Set(vfd, v)
+		return protoreflect.ValueOfMessage(m), true, nil
+	}
+	return protoreflect.Value{}, false, nil
+}
+
+// setSecondsNanos sets the seconds and nanos fields of m, which is a Timestamp or Duration.
+func setSecondsNanos(m protoreflect.Message, seconds int64, nanos int32) protoreflect.Value {
+	fields := m.Descriptor().Fields()
+	m.Set(fields.ByName("seconds"), protoreflect.ValueOfInt64(seconds))
+	m.Set(fields.ByName("nanos"), protoreflect.ValueOfInt32(nanos))
+	return protoreflect.ValueOfMessage(m)
+}
+
+// newMessage returns a new message for md. This uses the registered Go type if there is one and it was
+// made from md, otherwise a dynamicpb message, such as when md came from a Registry.
+func newMessage(md protoreflect.MessageDescriptor) protoreflect.Message {
+	if mt, err := protoregistry.GlobalTypes.FindMessageByName(md.FullName()); err == nil && mt.Descriptor() == md {
+		return mt.New()
+	}
+	return dynamicpb.NewMessage(md)
+}
@@ -0,0 +1,200 @@
+package prototools
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kylelemons/godebug/pretty"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	pb "github.com/johnsiilver/prototools/sample"
+)
+
+var wkTime = time.Date(2021, 3, 4, 5, 6, 7, 500, time.UTC)
+
+func newWellKnown() *pb.WellKnown {
+	s, err := structpb.NewStruct(map[string]interface{}{"a": 1.0, "b": []interface{}{"x", true}})
+	if err != nil {
+		panic(err)
+	}
+	return &pb.WellKnown{
+		Vtimestamp:   timestamppb.New(wkTime),
+		Vduration:    durationpb.New(90 * time.Second),
+		LTimestamp:   []*timestamppb.Timestamp{timestamppb.New(wkTime.Add(time.Hour))},
+		VstringValue: wrapperspb.String("hello"),
+		Vint64Value:  wrapperspb.Int64(-5),
+		Vuint32Value: wrapperspb.UInt32(5),
+		VboolValue:   wrapperspb.Bool(true),
+		VdoubleValue: wrapperspb.Double(1.234),
+		VbytesValue:  wrapperspb.Bytes([]byte("abc")),
+		Vstruct:      s,
+	}
+}
+
+func TestGetFieldWellKnown(t *testing.T) {
+	tests := []struct {
+		desc   string
+		msg    proto.Message
+		fqPath string
+		want   interface{}
+	}{
+		{desc: "timestamp", msg: newWellKnown(), fqPath: "vtimestamp", want: wkTime},
+		{desc: "duration", msg: newWellKnown(), fqPath: "vduration", want: 90 * time.Second},
+		{desc: "timestamp entry", msg: newWellKnown(), fqPath: "l_timestamp[0]", want: wkTime.Add(time.Hour)},
+		{desc: "string wrapper", msg: newWellKnown(), fqPath: "vstring_value", want: "hello"},
+		{desc: "int64 wrapper", msg: newWellKnown(), fqPath: "vint64_value", want: int64(-5)},
+		{desc: "uint32 wrapper", msg: newWellKnown(), fqPath: "vuint32_value", want: uint32(5)},
+		{desc: "bytes wrapper", msg: newWellKnown(), fqPath: "vbytes_value", want: []byte("abc")},
+		{
+			desc:   "struct",
+			msg:    newWellKnown(),
+			fqPath: "vstruct",
+			want:   map[string]interface{}{"a": 1.0, "b": []interface{}{"x", true}},
+		},
+		{desc: "not set", msg: &pb.WellKnown{}, fqPath: "vtimestamp", want: nil},
+		{desc: "not a well-known type", msg: &pb.Layer0{Layer1: &pb.Layer1{}}, fqPath: "layer1", want: nil},
+	}
+
+	for _, test := range tests {
+		fv, err := GetField(test.msg, test.fqPath)
+		if err != nil {
+			t.Errorf("TestGetFieldWellKnown(%s): got err == %s, want err == nil", test.desc, err)
+			continue
+		}
+		if diff := pretty.Compare(test.want, fv.WellKnown); diff != "" {
+			t.Errorf("TestGetFieldWellKnown(%s): -want/+got:\n%s", test.desc, diff)
+		}
+		if test.want != nil {
+			if _, ok := fv.Value.(proto.Message); !ok {
+				t.Errorf("TestGetFieldWellKnown(%s): .Value was a %T, want a proto.Message", test.desc, fv.Value)
+			}
+		}
+	}
+}
+
+func TestFieldAsStrWellKnown(t *testing.T) {
+	tests := []struct {
+		desc   string
+		fqPath string
+		pretty bool
+		want   string
+	}{
+		{desc: "timestamp", fqPath: "vtimestamp", want: "2021-03-04 05:06:07.0000005 +0000 UTC"},
+		{desc: "duration", fqPath: "vduration", want: "1m30s"},
+		{desc: "string wrapper", fqPath: "vstring_value", want: "hello"},
+		{desc: "int64 wrapper", fqPath: "vint64_value", want: "-5"},
+		{desc: "uint32 wrapper", fqPath: "vuint32_value", want: "5"},
+		{desc: "bool wrapper", fqPath: "vbool_value", want: "true"},
+		{desc: "bool wrapper pretty", fqPath: "vbool_value", pretty: true, want: "True"},
+		{desc: "double wrapper", fqPath: "vdouble_value", want: "1.23"},
+		{desc: "bytes wrapper", fqPath: "vbytes_value", want: "[3]bytes"},
+	}
+
+	msg := newWellKnown()
+	for _, test := range tests {
+		got, _, err := FieldAsStr(msg, test.fqPath, test.pretty)
+		if err != nil {
+			t.Errorf("TestFieldAsStrWellKnown(%s): got err == %s, want err == nil", test.desc, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("TestFieldAsStrWellKnown(%s): got %q, want %q", test.desc, got, test.want)
+		}
+	}
+}
+
+func TestUpdateProtoFieldWellKnown(t *testing.T) {
+	tests := []struct {
+		desc   string
+		fqPath string
+		value  interface{}
+		want   *pb.WellKnown
+		code   ErrCode
+	}{
+		{
+			desc:   "time.Time",
+			fqPath: "vtimestamp",
+			value:  wkTime,
+			want:   &pb.WellKnown{Vtimestamp: timestamppb.New(wkTime)},
+		},
+		{
+			desc:   "time.Duration",
+			fqPath: "vduration",
+			value:  90 * time.Second,
+			want:   &pb.WellKnown{Vduration: durationpb.New(90 * time.Second)},
+		},
+		{
+			desc:   "time.Time entry",
+			fqPath: "l_timestamp[0]",
+			value:  wkTime,
+			want:   &pb.WellKnown{LTimestamp: []*timestamppb.Timestamp{timestamppb.New(wkTime)}},
+		},
+		{
+			desc:   "string wrapper",
+			fqPath: "vstring_value",
+			value:  "hello",
+			want:   &pb.WellKnown{VstringValue: wrapperspb.String("hello")},
+		},
+		{
+			desc:   "int64 wrapper from an int",
+			fqPath: "vint64_value",
+			value:  3,
+			want:   &pb.WellKnown{Vint64Value: wrapperspb.Int64(3)},
+		},
+		{
+			desc:   "bool wrapper",
+			fqPath: "vbool_value",
+			value:  true,
+			want:   &pb.WellKnown{VboolValue: wrapperspb.Bool(true)},
+		},
+		{
+			desc:   "wrapper message still works",
+			fqPath: "vstring_value",
+			value:  wrapperspb.String("msg"),
+			want:   &pb.WellKnown{VstringValue: wrapperspb.String("msg")},
+		},
+		{
+			desc:   "error: wrong type for a timestamp",
+			fqPath: "vtimestamp",
+			value:  "2021-03-04",
+			code:   ErrTypeMismatch,
+		},
+		{
+			desc:   "error: wrong type for a wrapper",
+			fqPath: "vbool_value",
+			value:  "true",
+			code:   ErrTypeMismatch,
+		},
+		{
+			desc:   "error: out of range for a wrapper",
+			fqPath: "vuint32_value",
+			value:  -1,
+			code:   ErrValueOutOfRange,
+		},
+	}
+
+	for _, test := range tests {
+		msg := &pb.WellKnown{
+			LTimestamp: []*timestamppb.Timestamp{{}},
+		}
+		err := UpdateProtoField(msg, test.fqPath, test.value)
+		if !checkCode(t, "TestUpdateProtoFieldWellKnown", test.desc, err, test.code) {
+			continue
+		}
+		if err != nil {
+			continue
+		}
+
+		want := test.want
+		if want.LTimestamp == nil {
+			want.LTimestamp = []*timestamppb.Timestamp{{}}
+		}
+		if diff := Equal(want, msg); diff != "" {
+			t.Errorf("TestUpdateProtoFieldWellKnown(%s): -want/+got:\n%s", test.desc, diff)
+		}
+	}
+}